@@ -12,7 +12,10 @@ import (
 )
 
 // SetACL sets the ACL for a bucket.
-func (s *bucketService) SetACL(ctx context.Context, bucketName string, policy acl.ACL) error {
+func (s *bucketService) SetACL(ctx context.Context, bucketName string, policy acl.ACL) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetACL", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -52,7 +55,10 @@ func (s *bucketService) SetACL(ctx context.Context, bucketName string, policy ac
 }
 
 // GetACL retrieves the ACL for a bucket.
-func (s *bucketService) GetACL(ctx context.Context, bucketName string) (acl.ACL, error) {
+func (s *bucketService) GetACL(ctx context.Context, bucketName string) (_ acl.ACL, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetACL", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return acl.ACL{}, err
 	}
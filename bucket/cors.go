@@ -13,7 +13,10 @@ import (
 )
 
 // SetCORS sets the CORS configuration for a bucket.
-func (s *bucketService) SetCORS(ctx context.Context, bucketName string, config cors.Config) error {
+func (s *bucketService) SetCORS(ctx context.Context, bucketName string, config cors.Config) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetCORS", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -46,7 +49,10 @@ func (s *bucketService) SetCORS(ctx context.Context, bucketName string, config c
 }
 
 // GetCORS retrieves the CORS configuration for a bucket.
-func (s *bucketService) GetCORS(ctx context.Context, bucketName string) (cors.Config, error) {
+func (s *bucketService) GetCORS(ctx context.Context, bucketName string) (_ cors.Config, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetCORS", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return cors.Config{}, err
 	}
@@ -83,7 +89,10 @@ func (s *bucketService) GetCORS(ctx context.Context, bucketName string) (cors.Co
 }
 
 // DeleteCORS removes the CORS configuration from a bucket.
-func (s *bucketService) DeleteCORS(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteCORS(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteCORS", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -12,7 +12,10 @@ import (
 )
 
 // Create bucket
-func (s *bucketService) Create(ctx context.Context, bucketName string, opts ...CreateOption) error {
+func (s *bucketService) Create(ctx context.Context, bucketName string, opts ...CreateOption) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.Create", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// validate name
 	if err := validateBucketName(bucketName); err != nil {
 		return err
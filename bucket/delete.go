@@ -10,7 +10,10 @@ import (
 )
 
 // Delete bucket
-func (s *bucketService) Delete(ctx context.Context, bucketName string, opts ...DeleteOption) error {
+func (s *bucketService) Delete(ctx context.Context, bucketName string, opts ...DeleteOption) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.Delete", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// validate bucket name
 	if err := validateBucketName(bucketName); err != nil {
 		return err
@@ -14,7 +14,10 @@ import (
 )
 
 // SetEncryption sets default encryption configuration for a bucket
-func (s *bucketService) SetEncryption(ctx context.Context, bucketName string, config sse.Configuration) error {
+func (s *bucketService) SetEncryption(ctx context.Context, bucketName string, config sse.Configuration) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetEncryption", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// Validate bucket name
 	if err := validateBucketName(bucketName); err != nil {
 		return err
@@ -56,7 +59,10 @@ func (s *bucketService) SetEncryption(ctx context.Context, bucketName string, co
 }
 
 // GetEncryption retrieves the default encryption configuration of a bucket
-func (s *bucketService) GetEncryption(ctx context.Context, bucketName string) (sse.Configuration, error) {
+func (s *bucketService) GetEncryption(ctx context.Context, bucketName string) (_ sse.Configuration, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetEncryption", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// Validate bucket name
 	if err := validateBucketName(bucketName); err != nil {
 		return sse.Configuration{}, err
@@ -104,7 +110,10 @@ func (s *bucketService) GetEncryption(ctx context.Context, bucketName string) (s
 }
 
 // DeleteEncryption removes the default encryption configuration from a bucket
-func (s *bucketService) DeleteEncryption(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteEncryption(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteEncryption", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// Validate bucket name
 	if err := validateBucketName(bucketName); err != nil {
 		return err
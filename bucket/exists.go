@@ -10,7 +10,10 @@ import (
 )
 
 // Exists checks if a bucket exists.
-func (s *bucketService) Exists(ctx context.Context, bucketName string) (bool, error) {
+func (s *bucketService) Exists(ctx context.Context, bucketName string) (_ bool, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.Exists", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// validate bucket name
 	if err := validateBucketName(bucketName); err != nil {
 		return false, err
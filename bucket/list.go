@@ -45,7 +45,10 @@ func (s *bucketService) List(ctx context.Context) ([]types.BucketInfo, error) {
 }
 
 // GetLocation gets the location of a bucket
-func (s *bucketService) GetLocation(ctx context.Context, bucketName string) (string, error) {
+func (s *bucketService) GetLocation(ctx context.Context, bucketName string) (_ string, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetLocation", bucketName, "")
+	defer func() { call.End(err) }()
+
 	// validate bucket name
 	if err := validateBucketName(bucketName); err != nil {
 		return "", err
@@ -12,7 +12,10 @@ import (
 )
 
 // SetLogging sets bucket access logging configuration (XML).
-func (s *bucketService) SetLogging(ctx context.Context, bucketName string, config []byte) error {
+func (s *bucketService) SetLogging(ctx context.Context, bucketName string, config []byte) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetLogging", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -43,7 +46,10 @@ func (s *bucketService) SetLogging(ctx context.Context, bucketName string, confi
 }
 
 // GetLogging retrieves bucket access logging configuration (XML).
-func (s *bucketService) GetLogging(ctx context.Context, bucketName string) ([]byte, error) {
+func (s *bucketService) GetLogging(ctx context.Context, bucketName string) (_ []byte, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetLogging", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -73,7 +79,10 @@ func (s *bucketService) GetLogging(ctx context.Context, bucketName string) ([]by
 }
 
 // DeleteLogging removes bucket access logging configuration.
-func (s *bucketService) DeleteLogging(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteLogging(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteLogging", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
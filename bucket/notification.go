@@ -15,7 +15,10 @@ import (
 )
 
 // SetNotification sets bucket event notification configuration (XML/JSON).
-func (s *bucketService) SetNotification(ctx context.Context, bucketName string, config []byte) error {
+func (s *bucketService) SetNotification(ctx context.Context, bucketName string, config []byte) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetNotification", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -46,7 +49,10 @@ func (s *bucketService) SetNotification(ctx context.Context, bucketName string,
 }
 
 // GetNotification retrieves bucket event notification configuration.
-func (s *bucketService) GetNotification(ctx context.Context, bucketName string) ([]byte, error) {
+func (s *bucketService) GetNotification(ctx context.Context, bucketName string) (_ []byte, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetNotification", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -76,7 +82,10 @@ func (s *bucketService) GetNotification(ctx context.Context, bucketName string)
 }
 
 // DeleteNotification removes bucket event notification configuration.
-func (s *bucketService) DeleteNotification(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteNotification(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteNotification", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -102,13 +111,21 @@ func (s *bucketService) DeleteNotification(ctx context.Context, bucketName strin
 
 // ListenNotification listens for bucket events and streams notification info.
 func (s *bucketService) ListenNotification(ctx context.Context, bucketName, prefix, suffix string, events []notification.EventType) <-chan notification.Info {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.ListenNotification", bucketName, "")
 	notificationCh := make(chan notification.Info, 1)
 
 	go func() {
+		// listenErr is the error that ended listening
+		var listenErr error
+		defer func() { call.End(listenErr) }()
 		defer close(notificationCh)
+		fail := func(err error) {
+			listenErr = err
+			notificationCh <- notification.Info{Err: err}
+		}
 
 		if err := validateBucketName(bucketName); err != nil {
-			notificationCh <- notification.Info{Err: err}
+			fail(err)
 			return
 		}
 
@@ -128,13 +145,13 @@ func (s *bucketService) ListenNotification(ctx context.Context, bucketName, pref
 		req := core.NewRequest(ctx, http.MethodGet, meta)
 		resp, err := s.executor.Execute(ctx, req)
 		if err != nil {
-			notificationCh <- notification.Info{Err: err}
+			fail(err)
 			return
 		}
 		defer closeResponse(resp)
 
 		if resp.StatusCode != http.StatusOK {
-			notificationCh <- notification.Info{Err: parseErrorResponse(resp, bucketName, "")}
+			fail(parseErrorResponse(resp, bucketName, ""))
 			return
 		}
 
@@ -150,7 +167,7 @@ func (s *bucketService) ListenNotification(ctx context.Context, bucketName, pref
 
 			var info notification.Info
 			if err := json.Unmarshal(line, &info); err != nil {
-				notificationCh <- notification.Info{Err: err}
+				fail(err)
 				return
 			}
 
@@ -166,7 +183,7 @@ func (s *bucketService) ListenNotification(ctx context.Context, bucketName, pref
 		}
 
 		if err := scanner.Err(); err != nil {
-			notificationCh <- notification.Info{Err: err}
+			fail(err)
 		}
 	}()
 
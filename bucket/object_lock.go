@@ -14,7 +14,10 @@ import (
 )
 
 // SetObjectLockConfig sets the object lock configuration for a bucket.
-func (s *bucketService) SetObjectLockConfig(ctx context.Context, bucketName string, config objectlock.Config) error {
+func (s *bucketService) SetObjectLockConfig(ctx context.Context, bucketName string, config objectlock.Config) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetObjectLockConfig", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -50,7 +53,10 @@ func (s *bucketService) SetObjectLockConfig(ctx context.Context, bucketName stri
 }
 
 // GetObjectLockConfig retrieves the object lock configuration for a bucket.
-func (s *bucketService) GetObjectLockConfig(ctx context.Context, bucketName string) (objectlock.Config, error) {
+func (s *bucketService) GetObjectLockConfig(ctx context.Context, bucketName string) (_ objectlock.Config, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetObjectLockConfig", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return objectlock.Config{}, err
 	}
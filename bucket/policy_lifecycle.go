@@ -12,7 +12,10 @@ import (
 )
 
 // SetPolicy sets the bucket policy JSON document.
-func (s *bucketService) SetPolicy(ctx context.Context, bucketName, policy string) error {
+func (s *bucketService) SetPolicy(ctx context.Context, bucketName, policy string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetPolicy", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -41,7 +44,10 @@ func (s *bucketService) SetPolicy(ctx context.Context, bucketName, policy string
 }
 
 // GetPolicy retrieves the bucket policy JSON document.
-func (s *bucketService) GetPolicy(ctx context.Context, bucketName string) (string, error) {
+func (s *bucketService) GetPolicy(ctx context.Context, bucketName string) (_ string, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetPolicy", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return "", err
 	}
@@ -72,7 +78,10 @@ func (s *bucketService) GetPolicy(ctx context.Context, bucketName string) (strin
 }
 
 // DeletePolicy deletes the bucket policy.
-func (s *bucketService) DeletePolicy(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeletePolicy(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeletePolicy", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -97,7 +106,10 @@ func (s *bucketService) DeletePolicy(ctx context.Context, bucketName string) err
 }
 
 // SetLifecycle sets the bucket lifecycle configuration (XML).
-func (s *bucketService) SetLifecycle(ctx context.Context, bucketName string, config []byte) error {
+func (s *bucketService) SetLifecycle(ctx context.Context, bucketName string, config []byte) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetLifecycle", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -125,7 +137,10 @@ func (s *bucketService) SetLifecycle(ctx context.Context, bucketName string, con
 }
 
 // GetLifecycle fetches the bucket lifecycle configuration (XML).
-func (s *bucketService) GetLifecycle(ctx context.Context, bucketName string) ([]byte, error) {
+func (s *bucketService) GetLifecycle(ctx context.Context, bucketName string) (_ []byte, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetLifecycle", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -155,7 +170,10 @@ func (s *bucketService) GetLifecycle(ctx context.Context, bucketName string) ([]
 }
 
 // DeleteLifecycle removes the lifecycle configuration for the bucket.
-func (s *bucketService) DeleteLifecycle(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteLifecycle(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteLifecycle", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
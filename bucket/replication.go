@@ -14,7 +14,10 @@ import (
 )
 
 // SetReplication sets the bucket replication configuration (XML).
-func (s *bucketService) SetReplication(ctx context.Context, bucketName string, config []byte) error {
+func (s *bucketService) SetReplication(ctx context.Context, bucketName string, config []byte) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetReplication", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -45,7 +48,10 @@ func (s *bucketService) SetReplication(ctx context.Context, bucketName string, c
 }
 
 // GetReplication retrieves the bucket replication configuration (XML).
-func (s *bucketService) GetReplication(ctx context.Context, bucketName string) ([]byte, error) {
+func (s *bucketService) GetReplication(ctx context.Context, bucketName string) (_ []byte, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetReplication", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -75,7 +81,10 @@ func (s *bucketService) GetReplication(ctx context.Context, bucketName string) (
 }
 
 // DeleteReplication removes the bucket replication configuration.
-func (s *bucketService) DeleteReplication(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteReplication(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteReplication", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -100,7 +109,10 @@ func (s *bucketService) DeleteReplication(ctx context.Context, bucketName string
 }
 
 // GetReplicationMetrics retrieves replication metrics for a bucket.
-func (s *bucketService) GetReplicationMetrics(ctx context.Context, bucketName string) (replication.Metrics, error) {
+func (s *bucketService) GetReplicationMetrics(ctx context.Context, bucketName string) (_ replication.Metrics, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetReplicationMetrics", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return replication.Metrics{}, err
 	}
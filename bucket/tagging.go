@@ -22,7 +22,10 @@ type taggingConfig struct {
 }
 
 // SetTagging sets tags on a bucket.
-func (s *bucketService) SetTagging(ctx context.Context, bucketName string, tags map[string]string) error {
+func (s *bucketService) SetTagging(ctx context.Context, bucketName string, tags map[string]string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetTagging", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -60,7 +63,10 @@ func (s *bucketService) SetTagging(ctx context.Context, bucketName string, tags
 }
 
 // GetTagging retrieves tags for a bucket.
-func (s *bucketService) GetTagging(ctx context.Context, bucketName string) (map[string]string, error) {
+func (s *bucketService) GetTagging(ctx context.Context, bucketName string) (_ map[string]string, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetTagging", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -96,7 +102,10 @@ func (s *bucketService) GetTagging(ctx context.Context, bucketName string) (map[
 }
 
 // DeleteTagging removes tags from a bucket.
-func (s *bucketService) DeleteTagging(ctx context.Context, bucketName string) error {
+func (s *bucketService) DeleteTagging(ctx context.Context, bucketName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.DeleteTagging", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
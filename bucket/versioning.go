@@ -20,7 +20,10 @@ type versioningConfiguration struct {
 }
 
 // SetVersioning sets bucket versioning configuration.
-func (s *bucketService) SetVersioning(ctx context.Context, bucketName string, cfg types.VersioningConfig) error {
+func (s *bucketService) SetVersioning(ctx context.Context, bucketName string, cfg types.VersioningConfig) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.SetVersioning", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -60,7 +63,10 @@ func (s *bucketService) SetVersioning(ctx context.Context, bucketName string, cf
 }
 
 // GetVersioning retrieves bucket versioning configuration.
-func (s *bucketService) GetVersioning(ctx context.Context, bucketName string) (types.VersioningConfig, error) {
+func (s *bucketService) GetVersioning(ctx context.Context, bucketName string) (_ types.VersioningConfig, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Bucket.GetVersioning", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return types.VersioningConfig{}, err
 	}
@@ -2,17 +2,20 @@
 package rustfs
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Scorpio69t/rustfs-go/bucket"
 	"github.com/Scorpio69t/rustfs-go/internal/cache"
 	"github.com/Scorpio69t/rustfs-go/internal/core"
 	"github.com/Scorpio69t/rustfs-go/internal/transport"
 	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
 	"github.com/Scorpio69t/rustfs-go/types"
 	"golang.org/x/net/publicsuffix"
 )
@@ -22,11 +25,15 @@ type Client struct {
 	// Core components
 	executor      *core.Executor
 	locationCache *cache.LocationCache
+	rateLimits    *ratelimit.Limits
 
 	// Service modules
 	bucketService bucket.Service
 	objectService object.Service
 
+	// Stops background endpoint health probes
+	stopProbes func()
+
 	// Client info
 	endpointURL *url.URL
 	httpClient  *http.Client
@@ -57,6 +64,11 @@ type Client struct {
 //	    Secure:      false,
 //	})
 func New(endpoint string, opts *Options) (*Client, error) {
+	return newClient([]string{endpoint}, opts)
+}
+
+// newClient creates a client balancing requests across endpoints
+func newClient(endpoints []string, opts *Options) (*Client, error) {
 	// Validate options
 	if err := opts.validate(); err != nil {
 		return nil, err
@@ -65,11 +77,12 @@ func New(endpoint string, opts *Options) (*Client, error) {
 	// Apply defaults
 	opts.setDefaults()
 
-	// Parse endpoint URL
-	endpointURL, err := parseEndpointURL(endpoint, opts.Secure)
+	// Parse endpoint URLs; the first is the primary endpoint
+	endpointURLs, err := parseEndpointURLs(endpoints, opts.Secure)
 	if err != nil {
 		return nil, err
 	}
+	endpointURL := endpointURLs[0]
 
 	// If BucketLookup is Auto and endpoint is an IP, force path-style
 	if opts.BucketLookup == types.BucketLookupAuto && isIPAddress(endpointURL.Host) {
@@ -112,18 +125,41 @@ func New(endpoint string, opts *Options) (*Client, error) {
 		region = detectRegion(endpointURL, opts.CustomRegionViaURL)
 	}
 
-	// Create location cache (0 = no expiration)
+	// Create location cache (0 = default TTL), shared with the executor
 	locationCache := cache.NewLocationCache(0)
 
+	// Create rate limits, adjustable through Client.RateLimits
+	rateLimits := ratelimit.New(ratelimit.Config{
+		UploadBytesPerSecond:   opts.UploadBandwidth,
+		DownloadBytesPerSecond: opts.DownloadBandwidth,
+		RequestsPerSecond:      opts.RequestRate,
+	})
+
 	// Create core executor
 	executor := core.NewExecutor(core.ExecutorConfig{
-		HTTPClient:   httpClient,
-		EndpointURL:  endpointURL,
-		Credentials:  opts.Credentials,
-		Region:       region,
-		BucketLookup: int(opts.BucketLookup),
-		Accelerate:   opts.Accelerate,
-		MaxRetries:   opts.MaxRetries,
+		HTTPClient:    httpClient,
+		EndpointURL:   endpointURL,
+		Endpoints:     endpointURLs,
+		Balance:       opts.LoadBalancing,
+		EjectionTime:  opts.EjectionTime,
+		Credentials:   opts.Credentials,
+		Region:        region,
+		BucketLookup:  int(opts.BucketLookup),
+		Accelerate:    opts.Accelerate,
+		MaxRetries:    opts.MaxRetries,
+		LocationCache: locationCache,
+
+		TrailingHeaders: opts.TrailingHeaders,
+		RetryPolicy:     opts.RetryPolicy,
+		Interceptors:    opts.Interceptors,
+		Tracer:          opts.Tracer,
+		ClientTrace:     opts.Trace,
+
+		MetricsCollector: opts.MetricsCollector,
+		RateLimits:       rateLimits,
+		CircuitBreaker:   opts.CircuitBreaker,
+		Hedger:           opts.Hedger,
+		TrackClockSkew:   opts.TrackClockSkew,
 	})
 
 	// Create service instances
@@ -134,6 +170,7 @@ func New(endpoint string, opts *Options) (*Client, error) {
 	client := &Client{
 		executor:      executor,
 		locationCache: locationCache,
+		rateLimits:    rateLimits,
 		bucketService: bucketService,
 		objectService: objectService,
 		endpointURL:   endpointURL,
@@ -142,6 +179,11 @@ func New(endpoint string, opts *Options) (*Client, error) {
 		region:        region,
 	}
 
+	// Probe the endpoints of a multi-endpoint client in the background
+	if len(endpointURLs) > 1 && opts.HealthCheckInterval >= 0 {
+		client.stopProbes = executor.StartHealthProbes(context.Background(), opts.HealthCheckInterval, nil)
+	}
+
 	return client, nil
 }
 
@@ -163,6 +205,22 @@ func (c *Client) Object() object.Service {
 	return c.objectService
 }
 
+// RateLimits returns the bandwidth and request-rate limits shared by all
+// requests of the client, which can be changed while transfers are running
+//
+// Example:
+//
+//	client.RateLimits().SetUploadBandwidth(10 << 20) // 10 MiB/s
+func (c *Client) RateLimits() *ratelimit.Limits {
+	return c.rateLimits
+}
+
+// ClockOffset returns the estimated server clock minus the local clock,
+// which is added to the signing time of requests and presigned URLs
+func (c *Client) ClockOffset() time.Duration {
+	return c.executor.ClockOffset()
+}
+
 // EndpointURL returns the client's endpoint URL
 func (c *Client) EndpointURL() *url.URL {
 	endpoint := *c.endpointURL // copy to avoid mutating internal state
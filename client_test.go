@@ -115,6 +115,17 @@ func TestClientMethods(t *testing.T) {
 		}
 	})
 
+	t.Run("RateLimits", func(t *testing.T) {
+		limits := client.RateLimits()
+		if limits == nil || limits.UploadBandwidth() != 0 {
+			t.Fatal("RateLimits() should start unlimited")
+		}
+		limits.SetUploadBandwidth(1 << 20)
+		if client.RateLimits().UploadBandwidth() != 1<<20 {
+			t.Error("SetUploadBandwidth() did not change the client's limit")
+		}
+	})
+
 	t.Run("SetAppInfo", func(t *testing.T) {
 		client.SetAppInfo("test-app", "1.0.0")
 		if client.appInfo.appName != "test-app" {
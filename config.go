@@ -0,0 +1,226 @@
+// Package rustfs config.go
+package rustfs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// NewFromAlias creates a client for an alias of the client config file
+//
+// The config file is named by RUSTFS_SHARED_CREDENTIALS_FILE, or is
+// $HOME/.rustfs/config.json. An empty alias defaults to RUSTFS_ALIAS, then
+// "s3". See OptionsFromAlias for how the alias entry maps to Options.
+//
+// Example:
+//
+//	client, err := rustfs.NewFromAlias("local")
+func NewFromAlias(alias string) (*Client, error) {
+	endpoint, opts, err := OptionsFromAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	return New(endpoint, opts)
+}
+
+// NewFromEnv creates a client from environment variables
+//
+// See OptionsFromEnv for the variables read.
+//
+// Example:
+//
+//	// RUSTFS_ENDPOINT=https://rustfs.example.com RUSTFS_ACCESS_KEY=... RUSTFS_SECRET_KEY=...
+//	client, err := rustfs.NewFromEnv()
+func NewFromEnv() (*Client, error) {
+	endpoint, opts, err := OptionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return New(endpoint, opts)
+}
+
+// OptionsFromAlias returns the endpoint and Options NewFromAlias would use,
+// for callers that set further options before calling New
+//
+// The alias entry provides the URL, whose scheme sets Secure, the API
+// signature ("S3v4" or "S3v2"), the bucket lookup style of its "path" key
+// ("auto", "on" for path-style, "off" for virtual-host style) and the
+// region. Credentials come from the default chain: RUSTFS_* and AWS_*
+// environment variables, then the keys of the alias, then IAM.
+func OptionsFromAlias(alias string) (string, *Options, error) {
+	entry, err := credentials.LoadAlias("", alias)
+	if err != nil {
+		return "", nil, err
+	}
+	return newConfigOptions(configSettings{
+		endpoint: entry.URL,
+		api:      entry.API,
+		lookup:   entry.Path,
+		region:   entry.Region,
+		alias:    alias,
+	})
+}
+
+// OptionsFromEnv returns the endpoint and Options NewFromEnv would use, for
+// callers that set further options before calling New
+//
+// Environment variables used:
+//
+//   - RUSTFS_ENDPOINT: server address or URL, required; an https:// URL sets Secure
+//   - RUSTFS_SECURE: "true" to use HTTPS for an address without a scheme
+//   - RUSTFS_REGION: region
+//   - RUSTFS_API: API signature, "S3v4" (default) or "S3v2"
+//   - RUSTFS_BUCKET_LOOKUP: "auto" (default), "path" or "dns"
+//
+// Credentials come from the default chain: RUSTFS_* and AWS_* environment
+// variables, then the RUSTFS_ALIAS entry of the client config file, then IAM.
+func OptionsFromEnv() (string, *Options, error) {
+	settings := configSettings{
+		endpoint: os.Getenv("RUSTFS_ENDPOINT"),
+		api:      os.Getenv("RUSTFS_API"),
+		lookup:   os.Getenv("RUSTFS_BUCKET_LOOKUP"),
+		region:   os.Getenv("RUSTFS_REGION"),
+	}
+	if settings.endpoint == "" {
+		return "", nil, errInvalidArgument("RUSTFS_ENDPOINT is not set")
+	}
+	if value := os.Getenv("RUSTFS_SECURE"); value != "" {
+		secure, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", nil, errInvalidArgument(fmt.Sprintf("invalid RUSTFS_SECURE %q", value))
+		}
+		settings.secure = secure
+	}
+	return newConfigOptions(settings)
+}
+
+// configSettings are client settings read from a config source
+type configSettings struct {
+	endpoint string
+	secure   bool
+	api      string
+	lookup   string
+	region   string
+	alias    string
+}
+
+// newConfigOptions builds the endpoint and Options of settings
+func newConfigOptions(settings configSettings) (string, *Options, error) {
+	endpoint, secure, err := splitEndpointURL(settings.endpoint, settings.secure)
+	if err != nil {
+		return "", nil, err
+	}
+	signerType, err := parseSignatureType(settings.api)
+	if err != nil {
+		return "", nil, err
+	}
+	lookup, err := parseBucketLookup(settings.lookup)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return endpoint, &Options{
+		Credentials:  defaultCredentials(settings.alias, signerType),
+		Secure:       secure,
+		Region:       settings.region,
+		BucketLookup: lookup,
+	}, nil
+}
+
+// splitEndpointURL returns the host of an endpoint and whether it uses
+// HTTPS; the scheme of a URL takes precedence over secure
+func splitEndpointURL(endpoint string, secure bool) (string, bool, error) {
+	if endpoint == "" {
+		return "", false, errInvalidArgument("endpoint cannot be empty")
+	}
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, secure, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false, errInvalidArgument("endpoint scheme must be http or https")
+	}
+	if u.Host == "" {
+		return "", false, errInvalidArgument(fmt.Sprintf("endpoint %q has no host", endpoint))
+	}
+	if u.Path != "" && u.Path != "/" {
+		return "", false, errInvalidArgument(fmt.Sprintf("endpoint %q must not have a path", endpoint))
+	}
+	return u.Host, u.Scheme == "https", nil
+}
+
+// parseSignatureType parses an API signature name; empty selects V4
+func parseSignatureType(api string) (credentials.SignatureType, error) {
+	switch strings.ToLower(api) {
+	case "", "s3v4":
+		return credentials.SignatureV4, nil
+	case "s3v4streaming":
+		return credentials.SignatureV4Streaming, nil
+	case "s3v2":
+		return credentials.SignatureV2, nil
+	}
+	return 0, errInvalidArgument(fmt.Sprintf("unsupported API signature %q", api))
+}
+
+// parseBucketLookup parses a bucket lookup style, accepting the "on" and
+// "off" path values of the client config file
+func parseBucketLookup(lookup string) (types.BucketLookupType, error) {
+	switch strings.ToLower(lookup) {
+	case "", "auto":
+		return types.BucketLookupAuto, nil
+	case "path", "on":
+		return types.BucketLookupPath, nil
+	case "dns", "off":
+		return types.BucketLookupDNS, nil
+	}
+	return 0, errInvalidArgument(fmt.Sprintf("unsupported bucket lookup %q", lookup))
+}
+
+// defaultCredentials returns the default credential chain: environment
+// variables, then the alias of the client config file, then IAM. The keys
+// found are signed with signerType.
+func defaultCredentials(alias string, signerType credentials.SignatureType) *credentials.Credentials {
+	return credentials.New(&signedProvider{
+		Provider: &credentials.Chain{Providers: []credentials.Provider{
+			&credentials.EnvRustfs{},
+			&credentials.EnvAWS{},
+			&credentials.FileRustfsClient{Alias: alias},
+			&credentials.IAM{},
+		}},
+		signerType: signerType,
+	})
+}
+
+// signedProvider sets the signature type of the keys of a provider
+type signedProvider struct {
+	credentials.Provider
+	signerType credentials.SignatureType
+}
+
+// Retrieve returns the keys of the provider
+func (p *signedProvider) Retrieve() (credentials.Value, error) {
+	return p.sign(p.Provider.Retrieve())
+}
+
+// RetrieveWithCredContext returns the keys of the provider
+func (p *signedProvider) RetrieveWithCredContext(cc *credentials.CredContext) (credentials.Value, error) {
+	return p.sign(p.Provider.RetrieveWithCredContext(cc))
+}
+
+// sign sets the signature type of non-anonymous keys
+func (p *signedProvider) sign(value credentials.Value, err error) (credentials.Value, error) {
+	if err == nil && value.AccessKeyID != "" {
+		value.SignerType = p.signerType
+	}
+	return value, err
+}
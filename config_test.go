@@ -0,0 +1,172 @@
+// Package rustfs config_test.go
+package rustfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const testConfigFile = `{
+	"version": "10",
+	"aliases": {
+		"local": {
+			"url": "https://rustfs.example.com:9000",
+			"accessKey": "file-access",
+			"secretKey": "file-secret",
+			"api": "S3v2",
+			"path": "on",
+			"region": "eu-west-1"
+		},
+		"plain": {
+			"url": "http://127.0.0.1:9000",
+			"accessKey": "plain-access",
+			"secretKey": "plain-secret",
+			"api": "s3v4",
+			"path": "auto"
+		}
+	}
+}`
+
+// clearCredentialEnv unsets the credential variables of the default chain
+// and points it at a test config file
+func clearCredentialEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"RUSTFS_ROOT_USER", "RUSTFS_ROOT_PASSWORD", "RUSTFS_ACCESS_KEY", "RUSTFS_SECRET_KEY",
+		"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY", "AWS_SESSION_TOKEN",
+		"RUSTFS_ALIAS", "RUSTFS_ENDPOINT", "RUSTFS_SECURE", "RUSTFS_REGION", "RUSTFS_API", "RUSTFS_BUCKET_LOOKUP",
+	} {
+		t.Setenv(name, "")
+	}
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(testConfigFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RUSTFS_SHARED_CREDENTIALS_FILE", filename)
+}
+
+func TestOptionsFromAlias(t *testing.T) {
+	clearCredentialEnv(t)
+
+	endpoint, opts, err := OptionsFromAlias("local")
+	if err != nil {
+		t.Fatalf("OptionsFromAlias() error = %v", err)
+	}
+	if endpoint != "rustfs.example.com:9000" || !opts.Secure || opts.Region != "eu-west-1" || opts.BucketLookup != types.BucketLookupPath {
+		t.Errorf("OptionsFromAlias() = %q, %+v", endpoint, opts)
+	}
+	creds, err := opts.Credentials.Get()
+	if err != nil {
+		t.Fatalf("Credentials.Get() error = %v", err)
+	}
+	if creds.AccessKeyID != "file-access" || creds.SecretAccessKey != "file-secret" || creds.SignerType != credentials.SignatureV2 {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	t.Setenv("RUSTFS_ALIAS", "plain")
+	endpoint, opts, err = OptionsFromAlias("")
+	if err != nil {
+		t.Fatalf("OptionsFromAlias(RUSTFS_ALIAS) error = %v", err)
+	}
+	if endpoint != "127.0.0.1:9000" || opts.Secure || opts.BucketLookup != types.BucketLookupAuto {
+		t.Errorf("OptionsFromAlias(RUSTFS_ALIAS) = %q, %+v", endpoint, opts)
+	}
+
+	if _, _, err := OptionsFromAlias("missing"); err == nil {
+		t.Error("OptionsFromAlias(missing) error = nil")
+	}
+}
+
+func TestOptionsFromAliasEnvCredentials(t *testing.T) {
+	clearCredentialEnv(t)
+	t.Setenv("RUSTFS_ACCESS_KEY", "env-access")
+	t.Setenv("RUSTFS_SECRET_KEY", "env-secret")
+
+	_, opts, err := OptionsFromAlias("local")
+	if err != nil {
+		t.Fatalf("OptionsFromAlias() error = %v", err)
+	}
+	creds, err := opts.Credentials.Get()
+	if err != nil {
+		t.Fatalf("Credentials.Get() error = %v", err)
+	}
+	// Environment keys take precedence and are signed as the alias says
+	if creds.AccessKeyID != "env-access" || creds.SignerType != credentials.SignatureV2 {
+		t.Errorf("credentials = %+v", creds)
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	clearCredentialEnv(t)
+	if _, _, err := OptionsFromEnv(); err == nil {
+		t.Error("OptionsFromEnv() without RUSTFS_ENDPOINT error = nil")
+	}
+
+	t.Setenv("RUSTFS_ENDPOINT", "rustfs.internal:9000")
+	t.Setenv("RUSTFS_SECURE", "true")
+	t.Setenv("RUSTFS_REGION", "cn-east-1")
+	t.Setenv("RUSTFS_BUCKET_LOOKUP", "dns")
+	t.Setenv("AWS_ACCESS_KEY_ID", "aws-access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "aws-secret")
+
+	endpoint, opts, err := OptionsFromEnv()
+	if err != nil {
+		t.Fatalf("OptionsFromEnv() error = %v", err)
+	}
+	if endpoint != "rustfs.internal:9000" || !opts.Secure || opts.Region != "cn-east-1" || opts.BucketLookup != types.BucketLookupDNS {
+		t.Errorf("OptionsFromEnv() = %q, %+v", endpoint, opts)
+	}
+	creds, err := opts.Credentials.Get()
+	if err != nil {
+		t.Fatalf("Credentials.Get() error = %v", err)
+	}
+	if creds.AccessKeyID != "aws-access" || creds.SignerType != credentials.SignatureV4 {
+		t.Errorf("credentials = %+v", creds)
+	}
+
+	// Without keys in the environment, the RUSTFS_ALIAS entry provides them
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("RUSTFS_ALIAS", "plain")
+	_, opts, err = OptionsFromEnv()
+	if err != nil {
+		t.Fatalf("OptionsFromEnv() error = %v", err)
+	}
+	if creds, err = opts.Credentials.Get(); err != nil || creds.AccessKeyID != "plain-access" {
+		t.Errorf("credentials = %+v, %v", creds, err)
+	}
+
+	client, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	if got := client.EndpointURL().String(); got != "https://rustfs.internal:9000" {
+		t.Errorf("EndpointURL() = %q", got)
+	}
+}
+
+func TestOptionsFromEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name, key, value string
+	}{
+		{"secure", "RUSTFS_SECURE", "maybe"},
+		{"api", "RUSTFS_API", "S3v5"},
+		{"lookup", "RUSTFS_BUCKET_LOOKUP", "sideways"},
+		{"scheme", "RUSTFS_ENDPOINT", "ftp://rustfs.internal"},
+		{"path", "RUSTFS_ENDPOINT", "https://rustfs.internal/bucket"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCredentialEnv(t)
+			t.Setenv("RUSTFS_ENDPOINT", "rustfs.internal:9000")
+			t.Setenv(tt.key, tt.value)
+			if _, _, err := OptionsFromEnv(); err == nil {
+				t.Errorf("OptionsFromEnv() with %s=%q error = nil", tt.key, tt.value)
+			}
+		})
+	}
+}
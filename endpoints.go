@@ -0,0 +1,91 @@
+// Package rustfs endpoints.go
+package rustfs
+
+import (
+	"net/url"
+
+	"github.com/Scorpio69t/rustfs-go/internal/core"
+)
+
+// BalancePolicy selects the endpoint of each request of a client created
+// with NewWithEndpoints
+type BalancePolicy = core.BalancePolicy
+
+const (
+	// BalanceRoundRobin cycles through the available endpoints
+	BalanceRoundRobin = core.BalanceRoundRobin
+	// BalanceLeastOutstanding picks the available endpoint with the fewest
+	// requests in flight
+	BalanceLeastOutstanding = core.BalanceLeastOutstanding
+)
+
+// EndpointStatus describes an endpoint of a client created with
+// NewWithEndpoints: availability, last health probe, ejection and load
+type EndpointStatus = core.EndpointStatus
+
+// NewWithEndpoints creates a client that balances requests across several
+// nodes of a cluster
+//
+// Requests are spread with Options.LoadBalancing. An endpoint that fails
+// with a connection-level error is ejected for Options.EjectionTime and
+// retries go to a different endpoint. Background health probes run every
+// Options.HealthCheckInterval until Close is called. Presigned URLs are
+// pinned to one endpoint chosen by the balancer.
+//
+// All endpoints must use the same scheme. The first is the primary endpoint,
+// returned by EndpointURL and used for HealthCheck and region detection.
+//
+// Example:
+//
+//	client, err := rustfs.NewWithEndpoints([]string{"node1:9000", "node2:9000", "node3:9000"}, &rustfs.Options{
+//	    Credentials:   credentials.NewStaticV4("access-key", "secret-key", ""),
+//	    LoadBalancing: rustfs.BalanceLeastOutstanding,
+//	})
+//	defer client.Close()
+func NewWithEndpoints(endpoints []string, opts *Options) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, errInvalidArgument("at least one endpoint is required")
+	}
+	return newClient(endpoints, opts)
+}
+
+// Endpoints returns the status of each endpoint of a client created with
+// NewWithEndpoints, or nil for a single-endpoint client
+func (c *Client) Endpoints() []EndpointStatus {
+	return c.executor.Endpoints()
+}
+
+// Close stops the background endpoint health probes. The client must not be
+// used afterwards. Close is a no-op for single-endpoint clients.
+func (c *Client) Close() error {
+	if c.stopProbes != nil {
+		c.stopProbes()
+	}
+	return nil
+}
+
+// parseEndpointURLs parses the endpoints, which must share a scheme and be
+// distinct
+func parseEndpointURLs(endpoints []string, secure bool) ([]*url.URL, error) {
+	if len(endpoints) == 0 {
+		return nil, errInvalidArgument("endpoint cannot be empty")
+	}
+
+	urls := make([]*url.URL, 0, len(endpoints))
+	seen := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		endpointURL, err := parseEndpointURL(endpoint, secure)
+		if err != nil {
+			return nil, err
+		}
+		if len(urls) > 0 && endpointURL.Scheme != urls[0].Scheme {
+			return nil, errInvalidArgument("endpoints must all use the same scheme")
+		}
+		if seen[endpointURL.Host] {
+			return nil, errInvalidArgument("duplicate endpoint " + endpointURL.Host)
+		}
+		seen[endpointURL.Host] = true
+		urls = append(urls, endpointURL)
+	}
+	return urls, nil
+}
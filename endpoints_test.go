@@ -0,0 +1,81 @@
+// Package rustfs endpoints_test.go
+package rustfs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+)
+
+func TestNewWithEndpoints(t *testing.T) {
+	creds := credentials.NewStaticV4("access-key", "secret-key", "")
+	tests := []struct {
+		name      string
+		endpoints []string
+		wantErr   bool
+	}{
+		{name: "Several endpoints", endpoints: []string{"10.0.0.1:9000", "10.0.0.2:9000"}},
+		{name: "No endpoints", wantErr: true},
+		{name: "Mixed schemes", endpoints: []string{"http://10.0.0.1:9000", "https://10.0.0.2:9000"}, wantErr: true},
+		{name: "Duplicate endpoints", endpoints: []string{"10.0.0.1:9000", "10.0.0.1:9000"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewWithEndpoints(tt.endpoints, &Options{Credentials: creds, HealthCheckInterval: -1})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWithEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer client.Close()
+			if len(client.Endpoints()) != len(tt.endpoints) || client.EndpointURL().Host != "10.0.0.1:9000" {
+				t.Errorf("Endpoints() = %v, EndpointURL() = %v", client.Endpoints(), client.EndpointURL())
+			}
+		})
+	}
+}
+
+func TestNewWithEndpointsProbesHealth(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	client, err := NewWithEndpoints([]string{healthy.URL, failing.URL}, &Options{
+		Credentials:         credentials.NewStaticV4("access-key", "secret-key", ""),
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWithEndpoints() error = %v", err)
+	}
+	defer client.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status := client.Endpoints()
+		if status[1].LastCheck != nil && status[0].LastCheck != nil {
+			if !status[0].Available || status[1].Available {
+				t.Errorf("endpoint availability = %v, %v; want true, false", status[0].Available, status[1].Available)
+			}
+			if !strings.HasPrefix(status[1].LastCheck.Endpoint, failing.URL) {
+				t.Errorf("probe endpoint = %s", status[1].LastCheck.Endpoint)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("endpoints were not probed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
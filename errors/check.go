@@ -99,6 +99,17 @@ func IsRetryable(err error) bool {
 	return false
 }
 
+// IsChecksumMismatch checks if data failed checksum verification
+func IsChecksumMismatch(err error) bool {
+	var mismatchErr *ChecksumMismatchError
+	return errors.As(err, &mismatchErr)
+}
+
+// IsCircuitOpen checks if a request was rejected by an open circuit breaker
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
+
 // ToAPIError converts a generic error to an APIError if possible
 func ToAPIError(err error) *APIError {
 	var apiErr *APIError
@@ -41,6 +41,7 @@ const (
 	ErrMovedPermanently         RustfsGoErrorCode = "MovedPermanently"
 	ErrConflict                 RustfsGoErrorCode = "Conflict"
 	ErrInvalidRange             RustfsGoErrorCode = "InvalidRange"
+	ErrCircuitOpen              RustfsGoErrorCode = "CircuitOpen"
 
 	// region and authorization
 	ErrCodeInvalidRegion                RustfsGoErrorCode = "InvalidRegion"
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // Error RustFSGo error interface
@@ -126,3 +127,39 @@ func ParseErrorResponse(resp *http.Response, bucketName, objectName string) erro
 		ErrorResource:   "/" + bucketName + "/" + objectName,
 	}
 }
+
+// CircuitOpenError reports that a request was rejected without being sent
+// because the circuit breaker of its endpoint or bucket is open
+type CircuitOpenError struct {
+	// Key is the circuit: the endpoint host, or host and bucket
+	Key string
+	// RetryAt is when the circuit lets a trial request through
+	RetryAt time.Time
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for %s until %s", e.Key, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is matches ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// ChecksumMismatchError reports that data did not match its expected checksum
+type ChecksumMismatchError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+	Resource  string
+}
+
+// Error implements the error interface
+func (e *ChecksumMismatchError) Error() string {
+	msg := fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+	if e.Resource != "" {
+		msg += " (" + e.Resource + ")"
+	}
+	return msg
+}
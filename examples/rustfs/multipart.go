@@ -6,7 +6,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"io"
 	"log"
 	"strings"
 
@@ -41,22 +40,9 @@ func main() {
 	// Get Object service
 	objectSvc := client.Object()
 
-	// Type assertion to access multipart upload methods
-	type MultipartService interface {
-		InitiateMultipartUpload(ctx context.Context, bucketName, objectName string, opts ...object.PutOption) (string, error)
-		UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, partSize int64, opts ...object.PutOption) (types.ObjectPart, error)
-		CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []types.ObjectPart, opts ...object.PutOption) (types.UploadInfo, error)
-		AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error
-	}
-
-	multipartSvc, ok := objectSvc.(MultipartService)
-	if !ok {
-		log.Fatalln("Object service does not support multipart upload")
-	}
-
 	// 1. Initialize multipart upload
 	log.Println("\n=== Initialize multipart upload ===")
-	uploadID, err := multipartSvc.InitiateMultipartUpload(ctx, bucketName, objectName,
+	uploadID, err := objectSvc.InitiateMultipartUpload(ctx, bucketName, objectName,
 		object.WithContentType("text/plain"),
 		object.WithUserMetadata(map[string]string{
 			"upload-type": "multipart",
@@ -72,7 +58,7 @@ func main() {
 	defer func() {
 		if !uploadCompleted {
 			log.Println("\n=== Abort multipart upload (cleanup) ===")
-			err := multipartSvc.AbortMultipartUpload(ctx, bucketName, objectName, uploadID)
+			err := objectSvc.AbortMultipartUpload(ctx, bucketName, objectName, uploadID)
 			if err != nil {
 				log.Printf("Failed to abort multipart upload: %v\n", err)
 			} else {
@@ -100,7 +86,7 @@ func main() {
 
 		log.Printf("Uploading part %d/%d (size: %d bytes)...\n", partNumber, len(partContents), partSize)
 
-		part, err := multipartSvc.UploadPart(ctx, bucketName, objectName, uploadID,
+		part, err := objectSvc.UploadPart(ctx, bucketName, objectName, uploadID,
 			partNumber, partData, partSize)
 		if err != nil {
 			log.Fatalf("Failed to upload part %d: %v\n", partNumber, err)
@@ -112,7 +98,7 @@ func main() {
 
 	// 3. Complete multipart upload
 	log.Println("\n=== Complete multipart upload ===")
-	uploadInfo, err := multipartSvc.CompleteMultipartUpload(ctx, bucketName, objectName, uploadID, parts)
+	uploadInfo, err := objectSvc.CompleteMultipartUpload(ctx, bucketName, objectName, uploadID, parts)
 	if err != nil {
 		log.Fatalln("Failed to complete multipart upload:", err)
 	}
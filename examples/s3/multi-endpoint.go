@@ -0,0 +1,65 @@
+//go:build example
+// +build example
+
+// Example: Multi-endpoint client
+// Demonstrates balancing requests across cluster nodes with failover
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+)
+
+var endpoints = []string{"127.0.0.1:9000", "127.0.0.1:9001", "127.0.0.1:9002"}
+
+const (
+	accessKey = "rustfsadmin"
+	secretKey = "rustfsadmin"
+	bucket    = "mybucket"
+)
+
+func main() {
+	// Create client spreading requests over the nodes with the fewest in flight
+	client, err := rustfs.NewWithEndpoints(endpoints, &rustfs.Options{
+		Credentials:         credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure:              false,
+		LoadBalancing:       rustfs.BalanceLeastOutstanding,
+		HealthCheckInterval: 5 * time.Second,
+		EjectionTime:        30 * time.Second,
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	service := client.Object()
+
+	// Requests to a stopped node fail over to the others
+	for i := 0; i < 10; i++ {
+		objectName := fmt.Sprintf("multi-endpoint/object-%d.txt", i)
+		data := fmt.Sprintf("object %d", i)
+		if _, err := service.Put(ctx, bucket, objectName, strings.NewReader(data), int64(len(data))); err != nil {
+			log.Fatalf("Upload of %s failed: %v\n", objectName, err)
+		}
+	}
+	fmt.Println("✅ Uploaded 10 objects")
+
+	fmt.Println("\nEndpoint status:")
+	for _, status := range client.Endpoints() {
+		state := "available"
+		switch {
+		case !status.EjectedUntil.IsZero():
+			state = "ejected until " + status.EjectedUntil.Format(time.TimeOnly)
+		case !status.Healthy:
+			state = "unhealthy"
+		}
+		fmt.Printf("  %-25s %s (in flight: %d)\n", status.URL.Host, state, status.Outstanding)
+	}
+}
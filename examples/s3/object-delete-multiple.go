@@ -10,6 +10,7 @@ import (
 
 	"github.com/Scorpio69t/rustfs-go"
 	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
 )
 
 const (
@@ -40,17 +41,25 @@ func main() {
 
 	fmt.Printf("Preparing to delete %d objects...\n", len(objectsToDelete))
 
-	// Delete objects in a loop (API currently demonstrates per-object delete)
-	deletedCount := 0
-	for _, objectName := range objectsToDelete {
-		err := service.Delete(ctx, bucket, objectName)
-		if err != nil {
-			fmt.Printf("Warning: failed to delete '%s': %v\n", objectName, err)
+	// Feed keys into the bulk delete channel
+	objectsCh := make(chan types.ObjectToDelete)
+	go func() {
+		defer close(objectsCh)
+		for _, objectName := range objectsToDelete {
+			objectsCh <- types.ObjectToDelete{Key: objectName}
+		}
+	}()
+
+	// Only failures are reported back (requests are sent in quiet mode)
+	failedCount := 0
+	for deleteErr := range service.DeleteMany(ctx, bucket, objectsCh) {
+		failedCount++
+		if deleteErr.Err != nil {
+			fmt.Printf("Warning: failed to delete '%s': %v\n", deleteErr.Key, deleteErr.Err)
 			continue
 		}
-		deletedCount++
-		fmt.Printf("✅ Deleted: %s\n", objectName)
+		fmt.Printf("Warning: failed to delete '%s': %s (%s)\n", deleteErr.Key, deleteErr.Message, deleteErr.Code)
 	}
 
-	fmt.Printf("\nDone: %d succeeded, %d failed\n", deletedCount, len(objectsToDelete)-deletedCount)
+	fmt.Printf("\nDone: %d succeeded, %d failed\n", len(objectsToDelete)-failedCount, failedCount)
 }
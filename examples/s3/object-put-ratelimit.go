@@ -0,0 +1,76 @@
+//go:build example
+// +build example
+
+// Example: Upload object with bandwidth and request-rate limits
+// Demonstrates client-wide limits and changing them during a transfer
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
+)
+
+const (
+	endpoint  = "127.0.0.1:9000"
+	accessKey = "rustfsadmin"
+	secretKey = "rustfsadmin"
+	bucket    = "mybucket"
+)
+
+func main() {
+	// Create client limited to 1 MiB/s upload and 20 writes per second
+	client, err := rustfs.New(endpoint, &rustfs.Options{
+		Credentials:     credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure:          false,
+		UploadBandwidth: 1 << 20,
+		RequestRate:     map[ratelimit.Class]float64{ratelimit.ClassWrite: 20},
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	ctx := context.Background()
+	service := client.Object()
+
+	objectName := "ratelimit-upload.txt"
+
+	// Create test data (~5MB)
+	data := strings.Repeat("This is a rate limit test.......", 160000)
+	dataSize := int64(len(data))
+
+	// Raise the limit after two seconds; the running upload speeds up
+	go func() {
+		time.Sleep(2 * time.Second)
+		client.RateLimits().SetUploadBandwidth(4 << 20)
+		fmt.Println("Upload limit raised to 4 MiB/s")
+	}()
+
+	fmt.Printf("Uploading object '%s' (size: %.2f MB) at 1 MiB/s...\n", objectName, float64(dataSize)/1024/1024)
+	start := time.Now()
+
+	uploadInfo, err := service.Put(
+		ctx,
+		bucket,
+		objectName,
+		strings.NewReader(data),
+		dataSize,
+		object.WithContentType("text/plain; charset=utf-8"),
+	)
+	if err != nil {
+		log.Fatalf("Upload failed: %v\n", err)
+	}
+
+	elapsed := time.Since(start)
+	fmt.Println("✅ Upload successful")
+	fmt.Printf("Object: %s\n", uploadInfo.Key)
+	fmt.Printf("Size: %d bytes in %v (%.2f MB/s)\n", uploadInfo.Size, elapsed.Round(time.Millisecond),
+		float64(uploadInfo.Size)/1024/1024/elapsed.Seconds())
+}
@@ -0,0 +1,17 @@
+// Package rustfs interceptor.go
+package rustfs
+
+import "github.com/Scorpio69t/rustfs-go/internal/core"
+
+// Interceptor hooks into every request attempt the client makes, including
+// retries: before signing with the request metadata and operation name,
+// after signing with the outbound *http.Request, and after the attempt with
+// its *http.Response or error. Register interceptors with Options.Interceptors.
+type Interceptor = core.Interceptor
+
+// InterceptorFuncs is an Interceptor built from optional hook functions
+type InterceptorFuncs = core.InterceptorFuncs
+
+// RequestMetadata describes a request as seen by Interceptor.BeforeSign:
+// bucket, object, query, headers and body
+type RequestMetadata = core.RequestMetadata
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/pkg/breaker"
+)
+
+func TestExecuteCircuitBreakerFailsFast(t *testing.T) {
+	var attempts atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(int(status.Load()))
+		if status.Load() == http.StatusInternalServerError {
+			_, _ = w.Write([]byte(internalErrorBody))
+		}
+	}, fastPolicy(10))
+	executor.breaker = breaker.New(breaker.Config{FailureThreshold: 3, OpenTimeout: 50 * time.Millisecond})
+
+	get := func() error {
+		req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+		resp, err := executor.Execute(context.Background(), req)
+		closeResponse(resp)
+		return err
+	}
+
+	// The third failed attempt opens the circuit and the retry is rejected
+	if err := get(); !errors.IsCircuitOpen(err) {
+		t.Fatalf("Execute() error = %v, want circuit open", err)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("server received %d attempts, want 3", attempts.Load())
+	}
+	if err := get(); !errors.IsCircuitOpen(err) || attempts.Load() != 3 {
+		t.Errorf("Execute() error = %v after %d attempts, want to fail without sending", err, attempts.Load())
+	}
+
+	// A successful trial closes the circuit
+	status.Store(http.StatusNotFound)
+	time.Sleep(60 * time.Millisecond)
+	if err := get(); err != nil {
+		t.Fatalf("trial Execute() error = %v", err)
+	}
+	if state := executor.breaker.State(executor.endpointURL.Host); state != breaker.Closed {
+		t.Errorf("State() = %v, want closed; client errors do not trip the circuit", state)
+	}
+}
+
+func TestExecuteCircuitBreakerAvoidsOpenEndpoints(t *testing.T) {
+	a, b := newTestNode(t), newTestNode(t)
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, a, b)
+	executor.breaker = breaker.New(breaker.Config{FailureThreshold: 1, PerBucket: true})
+
+	// Open the circuit of one bucket on the first endpoint
+	key := executor.breaker.Key(a.url.Host, "bucket")
+	_ = executor.breaker.Allow(key)
+	executor.breaker.Record(key, true)
+
+	for i := 0; i < 4; i++ {
+		closeResponse(getObject(t, executor))
+	}
+	if a.requests.Load() != 0 || b.requests.Load() != 4 {
+		t.Errorf("requests = %d, %d; want all on the second endpoint", a.requests.Load(), b.requests.Load())
+	}
+
+	// Other buckets still use both endpoints
+	for i := 0; i < 2; i++ {
+		req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "other", ObjectName: "key"})
+		resp, err := executor.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		closeResponse(resp)
+	}
+	if a.requests.Load() != 1 {
+		t.Errorf("first endpoint received %d requests for another bucket, want 1", a.requests.Load())
+	}
+}
+
+func TestExecuteCircuitBreakerIgnoresCancelledTrial(t *testing.T) {
+	received := make(chan struct{}, 1)
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-r.Context().Done()
+	}, fastPolicy(1))
+	executor.breaker = breaker.New(breaker.Config{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+	key := executor.endpointURL.Host
+	_ = executor.breaker.Allow(key)
+	executor.breaker.Record(key, true)
+	time.Sleep(15 * time.Millisecond)
+
+	// The caller cancels the trial request before the node answers
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+	req := NewRequest(ctx, http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(ctx, req)
+	closeResponse(resp)
+	if err == nil {
+		t.Fatal("Execute() error = nil, want a cancellation error")
+	}
+	if state := executor.breaker.State(key); state != breaker.HalfOpen {
+		t.Errorf("State() = %v, want half-open after a cancelled trial", state)
+	}
+	if err := executor.breaker.Allow(key); err != nil {
+		t.Errorf("Allow() error = %v, want the trial slot back", err)
+	}
+}
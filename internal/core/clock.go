@@ -0,0 +1,54 @@
+// Package core internal/core/clock.go
+package core
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+)
+
+// skewCodes are the S3 error codes of requests rejected because they were
+// signed at a time too far from the server's clock
+var skewCodes = map[string]bool{
+	string(errors.ErrRequestTimeTooSkewed): true,
+	"RequestExpired":                       true,
+}
+
+// clockResolution is the precision of the Date header; smaller changes of
+// the estimated offset are ignored
+const clockResolution = time.Second
+
+// Now returns the current time on the server's clock: the local time
+// corrected by the offset learned from server responses. Use it for
+// anything signed, such as POST policies.
+func (e *Executor) Now() time.Time {
+	return time.Now().Add(e.ClockOffset())
+}
+
+// ClockOffset returns the estimated server clock minus the local clock
+func (e *Executor) ClockOffset() time.Duration {
+	return time.Duration(e.clockOffset.Load())
+}
+
+// observeServerClock estimates the clock offset from the Date header of a
+// response received at received, reporting whether the offset changed
+func (e *Executor) observeServerClock(resp *http.Response, received time.Time) bool {
+	if resp == nil {
+		return false
+	}
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return false
+	}
+
+	// The header is truncated to the second, so the server clock was
+	// half a second later on average
+	offset := serverTime.Add(clockResolution / 2).Sub(received)
+	current := e.ClockOffset()
+	if diff := offset - current; diff > -clockResolution && diff < clockResolution {
+		return false
+	}
+	e.clockOffset.Store(int64(offset))
+	return true
+}
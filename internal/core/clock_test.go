@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const skewedBody = `<Error><Code>RequestTimeTooSkewed</Code><Message>The difference between the request time and the current time is too large.</Message></Error>`
+
+// skewedServer returns a handler whose clock is ahead by skew, rejecting
+// requests signed more than 15 minutes away from it
+func skewedServer(skew time.Duration, requests *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		serverTime := time.Now().Add(skew)
+		w.Header().Set("Date", serverTime.UTC().Format(http.TimeFormat))
+		signed, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+		if err != nil || (serverTime.Sub(signed) > 15*time.Minute || signed.Sub(serverTime) > 15*time.Minute) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(skewedBody))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func TestExecuteCorrectsClockSkew(t *testing.T) {
+	const skew = -time.Hour
+	var requests atomic.Int32
+	executor := newRetryTestExecutor(t, skewedServer(skew, &requests), fastPolicy(1))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 after re-signing", resp.StatusCode)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("server received %d requests, want 2", requests.Load())
+	}
+	if offset := executor.ClockOffset(); offset < skew-2*time.Second || offset > skew+2*time.Second {
+		t.Errorf("ClockOffset() = %v, want about %v", offset, skew)
+	}
+
+	// Later requests are signed with the corrected time straight away
+	req = NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err = executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+	if requests.Load() != 3 {
+		t.Errorf("server received %d requests, want 3", requests.Load())
+	}
+}
+
+func TestExecuteRetriesClockSkewOnce(t *testing.T) {
+	var requests atomic.Int32
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(skewedBody))
+	}, fastPolicy(1))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want the RequestTimeTooSkewed response", resp.StatusCode)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("server received %d requests, want 2", requests.Load())
+	}
+}
+
+func TestExecuteTracksClockSkew(t *testing.T) {
+	var requests atomic.Int32
+	executor := newRetryTestExecutor(t, skewedServer(10*time.Minute, &requests), fastPolicy(1))
+	executor.trackClockSkew = true
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+	if offset := executor.ClockOffset(); offset < 9*time.Minute || offset > 11*time.Minute {
+		t.Errorf("ClockOffset() = %v, want about 10m from a successful response", offset)
+	}
+}
+
+func TestPresignUsesServerClock(t *testing.T) {
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {}, fastPolicy(1))
+	executor.clockOffset.Store(int64(-2 * time.Hour))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{
+		BucketName: "bucket", ObjectName: "key", PresignURL: true, Expires: 60,
+	})
+	u, _, err := executor.Presign(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Presign() error = %v", err)
+	}
+	query, _ := url.ParseQuery(u.RawQuery)
+	signed, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+	if err != nil {
+		t.Fatalf("X-Amz-Date = %q: %v", query.Get("X-Amz-Date"), err)
+	}
+	if d := time.Until(signed); d > -2*time.Hour+time.Minute || d < -2*time.Hour-time.Minute {
+		t.Errorf("presigned at %v, want two hours ago", signed)
+	}
+}
+
+func TestObserveServerClockIgnoresSmallChanges(t *testing.T) {
+	executor := &Executor{}
+	resp := &http.Response{Header: http.Header{}}
+	now := time.Now()
+
+	resp.Header.Set("Date", now.UTC().Format(http.TimeFormat))
+	if executor.observeServerClock(resp, now) {
+		t.Errorf("observeServerClock() changed the offset within the Date resolution to %v", executor.ClockOffset())
+	}
+	resp.Header.Set("Date", "not a date")
+	if executor.observeServerClock(resp, now) {
+		t.Error("observeServerClock() accepted an invalid Date")
+	}
+	resp.Header.Set("Date", now.Add(-time.Minute).UTC().Format(http.TimeFormat))
+	if !executor.observeServerClock(resp, now) {
+		t.Error("observeServerClock() ignored a one minute offset")
+	}
+}
@@ -0,0 +1,312 @@
+// Package core internal/core/endpoints.go
+package core
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BalancePolicy selects the endpoint of each attempt when an executor has
+// several endpoints
+type BalancePolicy int
+
+const (
+	// BalanceRoundRobin cycles through the available endpoints
+	BalanceRoundRobin BalancePolicy = iota
+	// BalanceLeastOutstanding picks the available endpoint with the fewest
+	// requests in flight, including response bodies still being read
+	BalanceLeastOutstanding
+)
+
+const (
+	// DefaultEjectionTime is how long an endpoint is left out of rotation
+	// after a connection-level error
+	DefaultEjectionTime = 30 * time.Second
+	// DefaultHealthCheckInterval is how often endpoints are probed
+	DefaultHealthCheckInterval = 10 * time.Second
+)
+
+// EndpointStatus describes an endpoint of a multi-endpoint executor
+type EndpointStatus struct {
+	// URL is the endpoint URL
+	URL *url.URL
+	// Available reports whether the endpoint receives requests
+	Available bool
+	// Healthy is the result of the last health probe, true before the first
+	Healthy bool
+	// EjectedUntil is when an endpoint ejected after a connection error
+	// returns to rotation, zero if it is not ejected
+	EjectedUntil time.Time
+	// Outstanding is the number of requests in flight
+	Outstanding int64
+	// LastCheck is the result of the last health probe, nil before the first
+	LastCheck *HealthCheckResult
+}
+
+// endpoint is a member of an endpointPool. A nil endpoint stands for the
+// executor's single endpoint.
+type endpoint struct {
+	url         *url.URL
+	outstanding atomic.Int64
+
+	// Guarded by endpointPool.mu
+	healthy      bool
+	ejectedUntil time.Time
+	lastCheck    *HealthCheckResult
+}
+
+// urlOr returns the endpoint URL, or fallback for a nil endpoint
+func (ep *endpoint) urlOr(fallback *url.URL) *url.URL {
+	if ep == nil {
+		return fallback
+	}
+	return ep.url
+}
+
+// release ends a request to the endpoint
+func (ep *endpoint) release() {
+	if ep != nil {
+		ep.outstanding.Add(-1)
+	}
+}
+
+// track keeps a request outstanding until its response body is closed
+func (ep *endpoint) track(body io.ReadCloser) io.ReadCloser {
+	if ep == nil {
+		return body
+	}
+	if body == nil {
+		ep.release()
+		return nil
+	}
+	return &endpointBody{ReadCloser: body, ep: ep}
+}
+
+// endpointBody releases its endpoint when closed
+type endpointBody struct {
+	io.ReadCloser
+	ep   *endpoint
+	once sync.Once
+}
+
+func (b *endpointBody) Close() error {
+	b.once.Do(b.ep.release)
+	return b.ReadCloser.Close()
+}
+
+// endpointPool balances attempts across endpoints, leaving out those that
+// failed a health probe or were ejected after a connection error. A nil
+// pool stands for a single endpoint.
+type endpointPool struct {
+	policy       BalancePolicy
+	ejectionTime time.Duration
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	next      int
+}
+
+// newEndpointPool returns a pool of urls, or nil for fewer than two
+func newEndpointPool(urls []*url.URL, policy BalancePolicy, ejectionTime time.Duration) *endpointPool {
+	if len(urls) < 2 {
+		return nil
+	}
+	if ejectionTime <= 0 {
+		ejectionTime = DefaultEjectionTime
+	}
+	pool := &endpointPool{policy: policy, ejectionTime: ejectionTime}
+	for _, u := range urls {
+		pool.endpoints = append(pool.endpoints, &endpoint{url: u, healthy: true})
+	}
+	return pool
+}
+
+// pick selects an endpoint, avoiding avoid unless it is the only one
+// available. Endpoints for which usable, if set, returns false are left out
+// like unhealthy ones. When every endpoint is out of rotation all are
+// considered, so that requests are never refused outright.
+func (p *endpointPool) pick(avoid *endpoint, usable func(*url.URL) bool) *endpoint {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	candidates := make([]int, 0, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		if ep.healthy && !now.Before(ep.ejectedUntil) && (usable == nil || usable(ep.url)) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		for i := range p.endpoints {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) > 1 && avoid != nil {
+		for j, i := range candidates {
+			if p.endpoints[i] == avoid {
+				candidates = append(candidates[:j], candidates[j+1:]...)
+				break
+			}
+		}
+	}
+
+	// Start from the round-robin position so that ties rotate
+	best := -1
+	for offset := range p.endpoints {
+		i := (p.next + offset) % len(p.endpoints)
+		if !containsIndex(candidates, i) {
+			continue
+		}
+		if best == -1 {
+			best = i
+			if p.policy != BalanceLeastOutstanding {
+				break
+			}
+			continue
+		}
+		if p.endpoints[i].outstanding.Load() < p.endpoints[best].outstanding.Load() {
+			best = i
+		}
+	}
+	p.next = (best + 1) % len(p.endpoints)
+	return p.endpoints[best]
+}
+
+// acquire picks an endpoint and counts a request to it as outstanding
+func (p *endpointPool) acquire(avoid *endpoint, usable func(*url.URL) bool) *endpoint {
+	ep := p.pick(avoid, usable)
+	if ep != nil {
+		ep.outstanding.Add(1)
+	}
+	return ep
+}
+
+// report records the outcome of an attempt, ejecting the endpoint after a
+// connection-level error
+func (p *endpointPool) report(ep *endpoint, connectionFailed bool) {
+	if p == nil || ep == nil || !connectionFailed {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.ejectedUntil = time.Now().Add(p.ejectionTime)
+}
+
+// probe health checks every endpoint concurrently. Healthy endpoints return
+// to rotation, including ejected ones; unhealthy ones leave it until a
+// later probe succeeds.
+func (p *endpointPool) probe(check func(*url.URL) *HealthCheckResult) {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := check(ep.url)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			ep.lastCheck = result
+			ep.healthy = result.Healthy
+			if result.Healthy {
+				ep.ejectedUntil = time.Time{}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// status returns a snapshot of every endpoint
+func (p *endpointPool) status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		u := *ep.url
+		status := EndpointStatus{
+			URL:         &u,
+			Available:   ep.healthy && !now.Before(ep.ejectedUntil),
+			Healthy:     ep.healthy,
+			Outstanding: ep.outstanding.Load(),
+			LastCheck:   ep.lastCheck,
+		}
+		if now.Before(ep.ejectedUntil) {
+			status.EjectedUntil = ep.ejectedUntil
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, index := range indexes {
+		if index == i {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoints returns the status of each endpoint, or nil for an executor
+// with a single endpoint
+func (e *Executor) Endpoints() []EndpointStatus {
+	if e.endpoints == nil {
+		return nil
+	}
+	return e.endpoints.status()
+}
+
+// ProbeEndpoints health checks every endpoint once, updating which of them
+// receive requests. It does nothing for an executor with a single endpoint.
+func (e *Executor) ProbeEndpoints(opts *HealthCheckOptions) {
+	if e.endpoints == nil {
+		return
+	}
+	e.endpoints.probe(func(u *url.URL) *HealthCheckResult {
+		return e.checkEndpoint(u, opts)
+	})
+}
+
+// StartHealthProbes probes the endpoints every interval until ctx is done
+// or the returned function is called. It does nothing for an executor with
+// a single endpoint.
+func (e *Executor) StartHealthProbes(ctx context.Context, interval time.Duration, opts *HealthCheckOptions) (stop func()) {
+	if e.endpoints == nil {
+		return func() {}
+	}
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				probeOpts := HealthCheckOptions{Context: ctx}
+				if opts != nil {
+					probeOpts = *opts
+					probeOpts.Context = ctx
+				}
+				e.ProbeEndpoints(&probeOpts)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
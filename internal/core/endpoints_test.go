@@ -0,0 +1,209 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// testNode is an endpoint server counting the requests it receives
+type testNode struct {
+	server   *httptest.Server
+	url      *url.URL
+	requests atomic.Int32
+	status   atomic.Int32
+}
+
+func newTestNode(t *testing.T) *testNode {
+	t.Helper()
+	node := &testNode{}
+	node.status.Store(http.StatusOK)
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/" {
+			node.requests.Add(1)
+		}
+		w.WriteHeader(int(node.status.Load()))
+	}))
+	t.Cleanup(node.server.Close)
+	node.url, _ = url.Parse(node.server.URL)
+	return node
+}
+
+// newMultiEndpointTestExecutor returns an executor balancing across nodes
+func newMultiEndpointTestExecutor(policy BalancePolicy, nodes ...*testNode) *Executor {
+	urls := make([]*url.URL, 0, len(nodes))
+	for _, node := range nodes {
+		urls = append(urls, node.url)
+	}
+	return NewExecutor(ExecutorConfig{
+		HTTPClient:   &http.Client{},
+		Endpoints:    urls,
+		Balance:      policy,
+		Credentials:  credentials.NewStaticV4("access-key", "secret-key", ""),
+		Region:       "us-east-1",
+		BucketLookup: int(types.BucketLookupPath),
+		RetryPolicy:  fastPolicy(3),
+	})
+}
+
+func getObject(t *testing.T, executor *Executor) *http.Response {
+	t.Helper()
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return resp
+}
+
+func TestExecuteRoundRobin(t *testing.T) {
+	nodes := []*testNode{newTestNode(t), newTestNode(t), newTestNode(t)}
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, nodes...)
+
+	for i := 0; i < 6; i++ {
+		closeResponse(getObject(t, executor))
+	}
+	for i, node := range nodes {
+		if got := node.requests.Load(); got != 2 {
+			t.Errorf("node %d received %d requests, want 2", i, got)
+		}
+	}
+	if executor.endpointURL != nodes[0].url {
+		t.Errorf("primary endpoint = %v, want the first", executor.endpointURL)
+	}
+}
+
+func TestExecuteLeastOutstanding(t *testing.T) {
+	a, b := newTestNode(t), newTestNode(t)
+	executor := newMultiEndpointTestExecutor(BalanceLeastOutstanding, a, b)
+
+	// A response body left open keeps its endpoint busy
+	held := getObject(t, executor)
+	for i := 0; i < 3; i++ {
+		closeResponse(getObject(t, executor))
+	}
+	if a.requests.Load() != 1 || b.requests.Load() != 3 {
+		t.Errorf("requests = %d, %d; want 1, 3", a.requests.Load(), b.requests.Load())
+	}
+	if outstanding := executor.Endpoints()[0].Outstanding; outstanding != 1 {
+		t.Errorf("outstanding = %d, want 1", outstanding)
+	}
+
+	closeResponse(held)
+	if outstanding := executor.Endpoints()[0].Outstanding; outstanding != 0 {
+		t.Errorf("outstanding after close = %d, want 0", outstanding)
+	}
+}
+
+func TestExecuteFailsOverConnectionErrors(t *testing.T) {
+	down, up := newTestNode(t), newTestNode(t)
+	down.server.Close()
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, down, up)
+
+	// The first attempt goes to the closed node and is retried on the other
+	closeResponse(getObject(t, executor))
+	if up.requests.Load() != 1 {
+		t.Fatalf("healthy node received %d requests, want 1", up.requests.Load())
+	}
+
+	status := executor.Endpoints()
+	if status[0].Available || status[0].EjectedUntil.IsZero() || !status[1].Available {
+		t.Errorf("endpoint status = %+v", status)
+	}
+
+	// Ejected nodes are skipped
+	for i := 0; i < 3; i++ {
+		closeResponse(getObject(t, executor))
+	}
+	if up.requests.Load() != 4 {
+		t.Errorf("healthy node received %d requests, want 4", up.requests.Load())
+	}
+}
+
+func TestExecuteKeepsEndpointOnBodyError(t *testing.T) {
+	a, b := newTestNode(t), newTestNode(t)
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, a, b)
+
+	// The caller's body fails, which says nothing about the endpoint
+	meta := RequestMetadata{
+		BucketName:    "bucket",
+		ObjectName:    "key",
+		ContentBody:   iotest.ErrReader(errors.New("disk read failed")),
+		ContentLength: 4,
+	}
+	req := NewRequest(context.Background(), http.MethodPut, meta)
+	resp, err := executor.Execute(context.Background(), req)
+	closeResponse(resp)
+	if err == nil {
+		t.Fatal("Execute() error = nil, want the body error")
+	}
+	for i, status := range executor.Endpoints() {
+		if !status.Available || !status.EjectedUntil.IsZero() {
+			t.Errorf("endpoint %d status = %+v, want it kept in rotation", i, status)
+		}
+	}
+}
+
+func TestProbeEndpoints(t *testing.T) {
+	a, b := newTestNode(t), newTestNode(t)
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, a, b)
+
+	a.status.Store(http.StatusInternalServerError)
+	executor.ProbeEndpoints(nil)
+	status := executor.Endpoints()
+	if status[0].Healthy || status[0].LastCheck == nil || !status[1].Healthy {
+		t.Fatalf("endpoint status after probe = %+v", status)
+	}
+	for i := 0; i < 2; i++ {
+		closeResponse(getObject(t, executor))
+	}
+	if a.requests.Load() != 0 {
+		t.Errorf("unhealthy node received %d requests", a.requests.Load())
+	}
+
+	// A successful probe returns the node to rotation, even if ejected
+	a.status.Store(http.StatusOK)
+	executor.endpoints.report(executor.endpoints.endpoints[0], true)
+	executor.ProbeEndpoints(nil)
+	if status := executor.Endpoints(); !status[0].Available {
+		t.Errorf("endpoint status after recovery = %+v", status[0])
+	}
+}
+
+func TestPresignPinsEndpoint(t *testing.T) {
+	a, b := newTestNode(t), newTestNode(t)
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, a, b)
+
+	hosts := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{
+			BucketName: "bucket", ObjectName: "key", PresignURL: true, Expires: 60,
+		})
+		u, _, err := executor.Presign(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Presign() error = %v", err)
+		}
+		hosts[u.Host] = true
+	}
+	if !hosts[a.url.Host] || !hosts[b.url.Host] {
+		t.Errorf("presigned hosts = %v, want both endpoints", hosts)
+	}
+}
+
+func TestSingleEndpointHasNoPool(t *testing.T) {
+	node := newTestNode(t)
+	executor := newMultiEndpointTestExecutor(BalanceRoundRobin, node)
+	if executor.Endpoints() != nil {
+		t.Error("Endpoints() should be nil for a single endpoint")
+	}
+	stop := executor.StartHealthProbes(context.Background(), 0, nil)
+	stop()
+}
@@ -2,19 +2,30 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
 	stdErrors "errors"
+	"hash"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/pkg/breaker"
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
 	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/pkg/hedge"
+	"github.com/Scorpio69t/rustfs-go/pkg/metrics"
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
+	"github.com/Scorpio69t/rustfs-go/pkg/retry"
 	"github.com/Scorpio69t/rustfs-go/pkg/signer"
+	"github.com/Scorpio69t/rustfs-go/pkg/tracing"
 	"github.com/Scorpio69t/rustfs-go/types"
 )
 
@@ -23,8 +34,10 @@ type Executor struct {
 	// HTTP client used to perform requests
 	httpClient *http.Client
 
-	// Endpoint information
+	// Endpoint information; with several endpoints, endpointURL is the
+	// first and attempts are balanced across the pool
 	endpointURL *url.URL
+	endpoints   *endpointPool
 
 	// Credentials provider
 	credentials *credentials.Credentials
@@ -49,6 +62,36 @@ type Executor struct {
 
 	// Bucket location cache
 	locationCache LocationCache
+
+	// Send checksum trailers on streamed uploads
+	trailingHeaders bool
+
+	// Decides whether and when failed attempts are retried
+	retryPolicy retry.Policy
+
+	// Hooks run around every attempt
+	interceptors []Interceptor
+
+	// Span tracer and httptrace hooks applied to every attempt
+	tracer      tracing.Tracer
+	clientTrace *httptrace.ClientTrace
+
+	// Receives the metrics of every request
+	metrics metrics.Collector
+
+	// Bandwidth and request-rate limits shared by all requests
+	rateLimits *ratelimit.Limits
+
+	// Rejects attempts to endpoints or buckets that keep failing
+	breaker *breaker.Breaker
+
+	// Hedges slow object reads
+	hedger *hedge.Hedger
+
+	// Server clock minus local clock in nanoseconds, applied when signing,
+	// learned from skew errors or, with trackClockSkew, every response
+	clockOffset    atomic.Int64
+	trackClockSkew bool
 }
 
 // ExecutorConfig configures an Executor
@@ -62,6 +105,45 @@ type ExecutorConfig struct {
 	Accelerate    bool
 	MaxRetries    int
 	LocationCache LocationCache
+
+	// Endpoints balances attempts across several endpoints with Balance,
+	// ejecting an endpoint for EjectionTime after a connection error.
+	// EndpointURL defaults to the first
+	Endpoints    []*url.URL
+	Balance      BalancePolicy
+	EjectionTime time.Duration
+
+	// TrailingHeaders enables aws-chunked uploads with checksum trailers
+	TrailingHeaders bool
+
+	// RetryPolicy decides whether failed attempts are retried.
+	// Defaults to retry.New(MaxRetries)
+	RetryPolicy retry.Policy
+
+	// Interceptors run around every attempt, in order
+	Interceptors []Interceptor
+
+	// Tracer receives a span per call with a child span per attempt
+	Tracer tracing.Tracer
+
+	// ClientTrace hooks are attached to every attempt
+	ClientTrace *httptrace.ClientTrace
+
+	// MetricsCollector receives the metrics of every request
+	MetricsCollector metrics.Collector
+
+	// RateLimits paces request bodies, response bodies and attempts
+	RateLimits *ratelimit.Limits
+
+	// CircuitBreaker fails attempts fast while their circuit is open
+	CircuitBreaker *breaker.Breaker
+
+	// Hedger hedges object GET and HEAD attempts that are slow to answer
+	Hedger *hedge.Hedger
+
+	// TrackClockSkew learns the server clock offset from every response
+	// instead of only from RequestTimeTooSkewed errors
+	TrackClockSkew bool
 }
 
 // NewExecutor creates a new Executor
@@ -70,85 +152,271 @@ func NewExecutor(config ExecutorConfig) *Executor {
 	if maxRetries <= 0 {
 		maxRetries = 10
 	}
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = retry.New(maxRetries)
+	}
+	endpointURL := config.EndpointURL
+	if endpointURL == nil && len(config.Endpoints) > 0 {
+		endpointURL = config.Endpoints[0]
+	}
 
 	return &Executor{
-		httpClient:    config.HTTPClient,
-		endpointURL:   config.EndpointURL,
-		credentials:   config.Credentials,
-		region:        config.Region,
-		secure:        config.Secure,
-		bucketLookup:  config.BucketLookup,
-		accelerate:    config.Accelerate,
-		maxRetries:    maxRetries,
-		locationCache: config.LocationCache,
+		httpClient:      config.HTTPClient,
+		endpointURL:     endpointURL,
+		endpoints:       newEndpointPool(config.Endpoints, config.Balance, config.EjectionTime),
+		credentials:     config.Credentials,
+		region:          config.Region,
+		secure:          config.Secure,
+		bucketLookup:    config.BucketLookup,
+		accelerate:      config.Accelerate,
+		maxRetries:      maxRetries,
+		locationCache:   config.LocationCache,
+		trailingHeaders: config.TrailingHeaders,
+		retryPolicy:     retryPolicy,
+		interceptors:    config.Interceptors,
+		tracer:          config.Tracer,
+		clientTrace:     config.ClientTrace,
+		metrics:         config.MetricsCollector,
+		rateLimits:      config.RateLimits,
+		breaker:         config.CircuitBreaker,
+		hedger:          config.Hedger,
+		trackClockSkew:  config.TrackClockSkew,
 	}
 }
 
 // Execute performs the request with retries and signing
 func (e *Executor) Execute(ctx context.Context, req *Request) (*http.Response, error) {
-	var (
-		resp    *http.Response
-		err     error
-		httpReq *http.Request
-	)
+	operation := operationName(req.Method(), req.metadata)
+	ctx = withRequestInfo(ctx, RequestInfo{
+		Operation:  operation,
+		BucketName: req.metadata.BucketName,
+		ObjectName: req.metadata.ObjectName,
+	})
+
+	ctx, call, owned := e.joinCall(ctx, operation, req.metadata)
+	start := time.Now()
+	var last finalAttempt
+	resp, err := e.execute(ctx, req, operation, call, &last)
+	if owned {
+		call.End(err)
+	}
+	e.recordMetrics(req, operation, start, last, resp, err)
+	return resp, err
+}
 
+// execute runs the attempts of a request until one succeeds or the retry
+// policy gives up, describing the last attempt made in last
+func (e *Executor) execute(ctx context.Context, req *Request, operation string, call *Call, last *finalAttempt) (*http.Response, error) {
 	meta := req.Metadata()
+	redirected, resynced := false, false
+	class := ratelimit.ClassOf(req.Method(), operation)
+	var previous *endpoint
 
-	// Retry loop
-	for attempt := 0; attempt < e.maxRetries; attempt++ {
+	for attempt := 0; ; attempt++ {
 		// Check context cancellation
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
-
-		// Build HTTP request
-		httpReq, err = e.buildHTTPRequest(ctx, req, meta)
-		if err != nil {
+		if err := e.rateLimits.WaitRequest(ctx, class); err != nil {
 			return nil, err
 		}
 
-		// Execute request
-		resp, err = e.httpClient.Do(httpReq)
+		info := retry.Attempt{Operation: operation, Method: req.Method(), Number: attempt}
+		attemptCtx, trace := e.startAttempt(ctx, req.Method(), attempt)
+
+		// Fail over to another endpoint than the previous attempt's,
+		// preferring those whose circuit is not open
+		ep := e.endpoints.acquire(previous, e.circuitUsable(meta.BucketName))
+		previous = ep
+		endpointURL := ep.urlOr(e.endpointURL)
+
+		// Build and sign HTTP request
+		httpReq, err := e.prepareAttempt(attemptCtx, req, meta, operation, endpointURL)
+		if err == nil {
+			err = e.breaker.Allow(e.breaker.Key(endpointURL.Host, meta.BucketName))
+		}
 		if err != nil {
-			if e.shouldRetry(err, attempt) {
-				if !resetRequestBody(&meta) {
-					return nil, err
-				}
-				e.waitForRetry(ctx, attempt)
-				continue
-			}
+			ep.release()
+			info.Err = err
+			trace.end(nil, nil, info)
 			return nil, err
 		}
+		httpReq.Body = e.rateLimits.Upload(ctx, httpReq.Body)
 
-		// Validate response
-		if e.isSuccessStatus(resp.StatusCode, req.metadata.Expect200OKWithError) {
+		// Execute request, hedging slow object reads
+		var resp *http.Response
+		hedged := false
+		if e.hedgeable(req.Method(), meta) {
+			resp, hedged, err = e.doHedged(ctx, httpReq, class)
+		} else {
+			resp, err = e.httpClient.Do(httpReq)
+		}
+		e.endpoints.report(ep, ctx.Err() == nil && retry.ConnectionFailed(err))
+		if e.trackClockSkew {
+			e.observeServerClock(resp, time.Now())
+		}
+		if resp != nil {
+			resp.Body = ep.track(resp.Body)
+		} else {
+			ep.release()
+		}
+		e.afterAttempt(attemptCtx, operation, httpReq, resp, err)
+
+		var region string
+		success := false
+		switch {
+		case err != nil:
+			info.Err = err
+		case e.isSuccessStatus(resp.StatusCode, meta.Expect200OKWithError):
+			info.StatusCode = resp.StatusCode
+			region = resp.Header.Get(bucketRegionHeader)
+			success = true
+		default:
+			region = describeFailure(&info, resp, meta.Expect200OKWithError)
+			success = resp.StatusCode < 300 && info.Code == ""
+		}
+		// Attempts cut short by the caller say nothing about the endpoint
+		if breakerKey := e.breaker.Key(endpointURL.Host, meta.BucketName); ctx.Err() != nil {
+			e.breaker.Release(breakerKey)
+		} else {
+			e.breaker.Record(breakerKey, !success && retry.Retryable(info))
+		}
+		trace.end(httpReq, resp, info)
+		call.observe(info, meta, resp, success)
+		*last = finalAttempt{info: info, attempts: last.attempts + 1, timeToFirstByte: trace.timeToFirstByte(), hedged: hedged}
+
+		if success {
+			e.learnBucketRegion(meta.BucketName, region)
+			e.retryPolicy.Success(info)
+			resp.Body = e.rateLimits.Download(ctx, resp.Body)
 			return resp, nil
 		}
 
-		// Retry if the response warrants it
-		if e.shouldRetryResponse(resp, attempt) {
-			closeResponse(resp)
-			if !resetRequestBody(&meta) {
-				return resp, nil
+		// Re-sign once for the bucket's region when the server reports
+		// that the request went to, or was signed for, the wrong one
+		if err == nil && !redirected {
+			if region, ok := e.regionRedirect(ctx, meta, info, region); ok && replayableBody(meta) && resetRequestBody(&meta) {
+				e.learnBucketRegion(meta.BucketName, region)
+				meta.BucketLocation = region
+				redirected = true
+				closeResponse(resp)
+				attempt--
+				continue
 			}
-			e.waitForRetry(ctx, attempt)
+		}
+
+		// Re-sign once with the server's time when the request was rejected
+		// for clock skew
+		if skewCodes[info.Code] && !resynced && replayableBody(meta) &&
+			(e.observeServerClock(resp, time.Now()) || e.trackClockSkew) && resetRequestBody(&meta) {
+			resynced = true
+			closeResponse(resp)
+			attempt--
 			continue
 		}
 
-		// Return error response (non-retryable)
-		return resp, nil
+		// Retry only bodies that can be replayed, if the policy agrees
+		delay, ok := time.Duration(0), false
+		if replayableBody(meta) {
+			delay, ok = e.retryPolicy.Retry(info)
+		}
+		if !ok || !resetRequestBody(&meta) {
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+		closeResponse(resp)
+		e.waitForRetry(ctx, delay)
 	}
+}
 
-	if err != nil {
-		return nil, err
+// circuitUsable returns the endpoint filter leaving out endpoints whose
+// circuit for bucketName is open, or nil without a circuit breaker
+func (e *Executor) circuitUsable(bucketName string) func(*url.URL) bool {
+	if e.breaker == nil {
+		return nil
+	}
+	return func(u *url.URL) bool {
+		return e.breaker.State(e.breaker.Key(u.Host, bucketName)) != breaker.Open
 	}
+}
 
-	return resp, nil
+// describeFailure records the S3 error code and Retry-After delay of a
+// failed response in info and returns the bucket region it reports, if any.
+// The error body is read ahead and then restored so that callers can still
+// parse it.
+func describeFailure(info *retry.Attempt, resp *http.Response, expect200OKWithError bool) string {
+	info.StatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		info.RetryAfter = retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	region := resp.Header.Get(bucketRegionHeader)
+	if resp.Body == nil || (resp.StatusCode < 300 && !expect200OKWithError) {
+		return region
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	var apiErr errors.APIError
+	if xml.Unmarshal(body, &apiErr) == nil {
+		info.Code = apiErr.ErrorCode
+		if region == "" {
+			region = apiErr.Region
+		}
+	}
+	return region
 }
 
-// buildHTTPRequest constructs and signs the outbound HTTP request
-func (e *Executor) buildHTTPRequest(ctx context.Context, req *Request, meta RequestMetadata) (*http.Request, error) {
-	// Resolve bucket location
+// bucketRegionHeader reports the region of the bucket a request addressed
+const bucketRegionHeader = "x-amz-bucket-region"
+
+// regionRedirect returns the region to re-sign a failed bucket request for,
+// when it was redirected or rejected for using a region other than region
+func (e *Executor) regionRedirect(ctx context.Context, meta RequestMetadata, info retry.Attempt, region string) (string, bool) {
+	if meta.BucketName == "" || region == "" {
+		return "", false
+	}
+	switch {
+	case info.StatusCode == http.StatusMovedPermanently,
+		info.StatusCode == http.StatusTemporaryRedirect,
+		info.Code == "PermanentRedirect",
+		info.Code == "AuthorizationHeaderMalformed",
+		info.Code == "IllegalLocationConstraintException":
+	default:
+		return "", false
+	}
+	if region == e.requestLocation(ctx, meta) {
+		return "", false
+	}
+	return region, true
+}
+
+// learnBucketRegion caches the region reported for a bucket
+func (e *Executor) learnBucketRegion(bucketName, region string) {
+	if e.locationCache == nil || bucketName == "" || region == "" {
+		return
+	}
+	if cached, ok := e.locationCache.Get(bucketName); ok && cached == region {
+		return
+	}
+	e.locationCache.Set(bucketName, region)
+}
+
+// maxErrorBodySize bounds how much of an error body is read to classify it
+const maxErrorBodySize = 1 << 20
+
+// readCloser reads from Reader and closes Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// requestLocation returns the region a request is signed for: the explicit
+// bucket location, the cached one, or the client region
+func (e *Executor) requestLocation(ctx context.Context, meta RequestMetadata) string {
 	location := meta.BucketLocation
 	if location == "" && meta.BucketName != "" {
 		location = e.getBucketLocation(ctx, meta.BucketName)
@@ -156,10 +424,17 @@ func (e *Executor) buildHTTPRequest(ctx context.Context, req *Request, meta Requ
 	if location == "" {
 		location = e.region
 	}
+	return location
+}
+
+// buildHTTPRequest constructs and signs the outbound HTTP request to endpointURL
+func (e *Executor) buildHTTPRequest(ctx context.Context, req *Request, meta RequestMetadata, endpointURL *url.URL) (*http.Request, error) {
+	// Resolve bucket location
+	location := e.requestLocation(ctx, meta)
 
 	// Build target URL
 	useAccelerate := e.accelerate || meta.UseAccelerate
-	targetURL, err := e.makeTargetURL(meta.BucketName, meta.ObjectName, location, meta.QueryValues, useAccelerate)
+	targetURL, err := e.makeTargetURL(endpointURL, meta.BucketName, meta.ObjectName, location, meta.QueryValues, useAccelerate)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +460,11 @@ func (e *Executor) buildHTTPRequest(ctx context.Context, req *Request, meta Requ
 	// Set Content-Length
 	httpReq.ContentLength = meta.ContentLength
 
+	// Set Content-MD5 header
+	if meta.ContentMD5Base64 != "" {
+		httpReq.Header.Set("Content-MD5", meta.ContentMD5Base64)
+	}
+
 	// Set Content-SHA256 header (required for SigV4)
 	if meta.ContentSHA256Hex != "" {
 		httpReq.Header.Set("X-Amz-Content-Sha256", meta.ContentSHA256Hex)
@@ -199,12 +479,14 @@ func (e *Executor) buildHTTPRequest(ctx context.Context, req *Request, meta Requ
 }
 
 // Presign builds and signs the request, returning the presigned URL and signed headers without executing it.
+// With several endpoints the URL is pinned to one chosen by the balancer.
 func (e *Executor) Presign(ctx context.Context, req *Request) (*url.URL, http.Header, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	httpReq, err := e.buildHTTPRequest(ctx, req, req.Metadata())
+	endpointURL := e.endpoints.pick(nil, nil).urlOr(e.endpointURL)
+	httpReq, err := e.buildHTTPRequest(ctx, req, req.Metadata(), endpointURL)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -282,6 +564,7 @@ func (e *Executor) ResolveBucketLocation(ctx context.Context, bucketName string)
 }
 
 // TargetURL returns a resolved target URL for a bucket/object and query.
+// With several endpoints the URL is pinned to one chosen by the balancer.
 func (e *Executor) TargetURL(ctx context.Context, bucketName, objectName string, query url.Values) (*url.URL, error) {
 	location := e.region
 	if bucketName != "" {
@@ -293,7 +576,17 @@ func (e *Executor) TargetURL(ctx context.Context, bucketName, objectName string,
 			location = resolved
 		}
 	}
-	return e.makeTargetURL(bucketName, objectName, location, query, false)
+	endpointURL := e.endpoints.pick(nil, nil).urlOr(e.endpointURL)
+	return e.makeTargetURL(endpointURL, bucketName, objectName, location, query, false)
+}
+
+// replayableBody reports whether the request body can be sent again
+func replayableBody(meta RequestMetadata) bool {
+	if meta.ContentBody == nil {
+		return true
+	}
+	_, ok := meta.ContentBody.(io.Seeker)
+	return ok
 }
 
 // resetRequestBody attempts to rewind the request body for a retry.
@@ -310,10 +603,10 @@ func resetRequestBody(meta *RequestMetadata) bool {
 	return false
 }
 
-// makeTargetURL builds the final request URL
-func (e *Executor) makeTargetURL(bucketName, objectName, location string, queryValues url.Values, useAccelerate bool) (*url.URL, error) {
-	host := e.endpointURL.Host
-	scheme := e.endpointURL.Scheme
+// makeTargetURL builds the final request URL on endpointURL
+func (e *Executor) makeTargetURL(endpointURL *url.URL, bucketName, objectName, location string, queryValues url.Values, useAccelerate bool) (*url.URL, error) {
+	host := endpointURL.Host
+	scheme := endpointURL.Scheme
 
 	// Normalize default ports (strip :80 for HTTP and :443 for HTTPS)
 	// Reason: browsers/curl drop default ports, which would break presigned URLs
@@ -335,7 +628,7 @@ func (e *Executor) makeTargetURL(bucketName, objectName, location string, queryV
 		isVirtualHost := e.isVirtualHostStyleRequest(bucketName)
 
 		if useAccelerate {
-			if !isValidVirtualHostBucket(bucketName, e.endpointURL.Scheme == "https") {
+			if !isValidVirtualHostBucket(bucketName, scheme == "https") {
 				return nil, stdErrors.New("accelerate requires DNS-compliant bucket names")
 			}
 			acceleratedHost, err := accelerateHost(host)
@@ -479,17 +772,57 @@ func (e *Executor) signRequest(req *http.Request, meta RequestMetadata, location
 	// Handle presign
 	if meta.PresignURL {
 		expires := time.Duration(meta.Expires) * time.Second
-		sn := signer.NewSigner(convertSignerType(creds.SignerType))
+		sn := signer.NewSignerWithClock(convertSignerType(creds.SignerType), e.Now)
 		sn.Presign(req, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, region, expires)
 		return nil
 	}
 
+	// Stream non-seekable payloads as aws-chunked, with checksum trailers
+	// when enabled or chunk signatures for SignatureV4Streaming credentials
+	if dataLen, ok := streamablePayload(req, meta); ok && creds.SignerType != credentials.SignatureV2 {
+		if trailers := trailerHashes(meta.Trailer); (e.trailingHeaders || meta.AddCRC) && len(trailers) > 0 {
+			signer.PrepareTrailerRequest(req, dataLen, trailers)
+		} else if creds.SignerType == credentials.SignatureV4Streaming {
+			signer.SignV4StreamingAt(req, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, region, dataLen, e.Now())
+			return nil
+		}
+	}
+
 	// Sign standard requests
-	sn := signer.NewSigner(convertSignerType(creds.SignerType))
+	sn := signer.NewSignerWithClock(convertSignerType(creds.SignerType), e.Now)
 	sn.Sign(req, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, region)
 	return nil
 }
 
+// streamablePayload reports whether the request body should be sent as
+// aws-chunked: a PUT of a known, non-empty length whose body cannot be
+// rewound and whose payload hash was not computed up front
+func streamablePayload(req *http.Request, meta RequestMetadata) (int64, bool) {
+	if !meta.StreamSHA256 || req.Method != http.MethodPut || req.Body == nil || meta.ContentLength <= 0 {
+		return 0, false
+	}
+	if req.Header.Get("X-Amz-Content-Sha256") != "" {
+		return 0, false
+	}
+	if _, ok := meta.ContentBody.(io.Seeker); ok {
+		return 0, false
+	}
+	return meta.ContentLength, true
+}
+
+// trailerHashes creates fresh hashes for the checksum trailers named in trailer
+func trailerHashes(trailer http.Header) map[string]hash.Hash {
+	hashes := make(map[string]hash.Hash, len(trailer))
+	for name := range trailer {
+		algorithm, ok := checksum.FromHeaderKey(name)
+		if !ok {
+			continue
+		}
+		hashes[strings.ToLower(name)] = checksum.New(algorithm)
+	}
+	return hashes
+}
+
 // convertSignerType maps credentials.SignatureType to signer.SignerType
 func convertSignerType(st credentials.SignatureType) signer.SignerType {
 	switch st {
@@ -512,76 +845,17 @@ func (e *Executor) getBucketLocation(ctx context.Context, bucketName string) str
 	return e.region
 }
 
-// shouldRetry decides whether an error should be retried
-func (e *Executor) shouldRetry(err error, attempt int) bool {
-	if attempt >= e.maxRetries-1 {
-		return false
-	}
-
-	// Check error string patterns
-	if err == nil {
-		return false
-	}
-
-	// Network error patterns
-	errStr := err.Error()
-
-	// Retry on connection refused/reset/timeouts and temporary failures
-	if strings.Contains(errStr, "connection refused") ||
-		strings.Contains(errStr, "connection reset") ||
-		strings.Contains(errStr, "broken pipe") ||
-		strings.Contains(errStr, "no such host") ||
-		strings.Contains(errStr, "TLS handshake timeout") ||
-		strings.Contains(errStr, "i/o timeout") ||
-		strings.Contains(errStr, "net/http: request canceled") ||
-		strings.Contains(errStr, "context deadline exceeded") {
-		return true
-	}
-
-	// Inspect url.Error
-	if urlErr, ok := err.(*url.Error); ok {
-		if urlErr.Timeout() {
-			return true
-		}
-		// Recursively inspect wrapped error
-		return e.shouldRetry(urlErr.Err, attempt)
-	}
-
-	// Inspect net.Error
-	if netErr, ok := err.(net.Error); ok {
-		return netErr.Timeout()
-	}
-
-	return false
-}
-
-// shouldRetryResponse decides whether to retry based on HTTP response
-func (e *Executor) shouldRetryResponse(resp *http.Response, attempt int) bool {
-	if attempt >= e.maxRetries-1 {
-		return false
-	}
-	// Retry on 5xx
-	if resp.StatusCode >= 500 {
-		return true
-	}
-	// 429 Too Many Requests
-	if resp.StatusCode == 429 {
-		return true
-	}
-	return false
-}
-
-// waitForRetry pauses using exponential backoff
-func (e *Executor) waitForRetry(ctx context.Context, attempt int) {
-	// Exponential backoff
-	delay := time.Duration(1<<uint(attempt)) * 100 * time.Millisecond
-	if delay > 10*time.Second {
-		delay = 10 * time.Second
+// waitForRetry pauses for delay or until ctx is done
+func (e *Executor) waitForRetry(ctx context.Context, delay time.Duration) {
+	if delay <= 0 {
+		return
 	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 
 	select {
 	case <-ctx.Done():
-	case <-time.After(delay):
+	case <-timer.C:
 	}
 }
 
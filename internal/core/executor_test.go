@@ -3,7 +3,6 @@ package core
 
 import (
 	"context"
-	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -184,7 +183,7 @@ func TestMakeTargetURL(t *testing.T) {
 				bucketLookup: tt.bucketLookup,
 			}
 
-			got, err := executor.makeTargetURL(tt.bucketName, tt.objectName, "", tt.queryValues, tt.useAccelerate)
+			got, err := executor.makeTargetURL(endpointURL, tt.bucketName, tt.objectName, "", tt.queryValues, tt.useAccelerate)
 			if err != nil {
 				t.Fatalf("makeTargetURL() error = %v", err)
 			}
@@ -333,133 +332,6 @@ func TestIsValidVirtualHostBucket(t *testing.T) {
 	}
 }
 
-func TestShouldRetry(t *testing.T) {
-	executor := &Executor{
-		maxRetries: 3,
-	}
-
-	tests := []struct {
-		name    string
-		err     error
-		attempt int
-		want    bool
-	}{
-		{
-			name:    "Nil error",
-			err:     nil,
-			attempt: 0,
-			want:    false,
-		},
-		{
-			name:    "Max attempts reached",
-			err:     &url.Error{Op: "Get", Err: io.EOF},
-			attempt: 2,
-			want:    false,
-		},
-		{
-			name:    "Connection refused",
-			err:     &url.Error{Op: "Get", Err: &testNetError{msg: "connection refused", temp: true}},
-			attempt: 0,
-			want:    true,
-		},
-		{
-			name:    "Timeout error",
-			err:     &testNetError{msg: "i/o timeout", timeout: true},
-			attempt: 0,
-			want:    true,
-		},
-		{
-			name:    "Net error timeout",
-			err:     &testNetError{msg: "net error", timeout: true},
-			attempt: 0,
-			want:    true,
-		},
-		{
-			name:    "Non-retryable error",
-			err:     io.EOF,
-			attempt: 0,
-			want:    false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := executor.shouldRetry(tt.err, tt.attempt)
-			if got != tt.want {
-				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestShouldRetryResponse(t *testing.T) {
-	executor := &Executor{
-		maxRetries: 3,
-	}
-
-	tests := []struct {
-		name       string
-		statusCode int
-		attempt    int
-		want       bool
-	}{
-		{
-			name:       "500 Internal Server Error",
-			statusCode: 500,
-			attempt:    0,
-			want:       true,
-		},
-		{
-			name:       "502 Bad Gateway",
-			statusCode: 502,
-			attempt:    0,
-			want:       true,
-		},
-		{
-			name:       "503 Service Unavailable",
-			statusCode: 503,
-			attempt:    0,
-			want:       true,
-		},
-		{
-			name:       "429 Too Many Requests",
-			statusCode: 429,
-			attempt:    0,
-			want:       true,
-		},
-		{
-			name:       "200 OK",
-			statusCode: 200,
-			attempt:    0,
-			want:       false,
-		},
-		{
-			name:       "404 Not Found",
-			statusCode: 404,
-			attempt:    0,
-			want:       false,
-		},
-		{
-			name:       "Max attempts reached",
-			statusCode: 500,
-			attempt:    2,
-			want:       false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp := &http.Response{
-				StatusCode: tt.statusCode,
-			}
-			got := executor.shouldRetryResponse(resp, tt.attempt)
-			if got != tt.want {
-				t.Errorf("shouldRetryResponse() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestExecuteSuccess(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -589,17 +461,6 @@ func TestEncodePath(t *testing.T) {
 	}
 }
 
-// testNetError simulates network error
-type testNetError struct {
-	msg     string
-	temp    bool
-	timeout bool
-}
-
-func (e *testNetError) Error() string   { return e.msg }
-func (e *testNetError) Temporary() bool { return e.temp }
-func (e *testNetError) Timeout() bool   { return e.timeout }
-
 func BenchmarkMakeTargetURL(b *testing.B) {
 	endpointURL, _ := url.Parse("https://s3.amazonaws.com")
 	executor := &Executor{
@@ -608,7 +469,7 @@ func BenchmarkMakeTargetURL(b *testing.B) {
 	}
 
 	for i := 0; i < b.N; i++ {
-		_, _ = executor.makeTargetURL("my-bucket", "my-object.txt", "us-east-1", nil, false)
+		_, _ = executor.makeTargetURL(endpointURL, "my-bucket", "my-object.txt", "us-east-1", nil, false)
 	}
 }
 
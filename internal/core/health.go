@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -47,17 +48,24 @@ type HealthCheckOptions struct {
 // HealthCheck performs health check
 // Sends a simple HEAD request to the endpoint to verify connectivity
 func (e *Executor) HealthCheck(opts *HealthCheckOptions) *HealthCheckResult {
+	return e.checkEndpoint(e.endpointURL, opts)
+}
+
+// checkEndpoint performs the health check of one endpoint
+func (e *Executor) checkEndpoint(endpointURL *url.URL, opts *HealthCheckOptions) *HealthCheckResult {
 	result := &HealthCheckResult{
-		Endpoint:  e.endpointURL.String(),
+		Endpoint:  endpointURL.String(),
 		Region:    e.region,
 		CheckedAt: time.Now(),
 		Healthy:   false,
 	}
 
-	// Set default options
-	if opts == nil {
-		opts = &HealthCheckOptions{}
+	// Set default options on a copy, as endpoints may be checked concurrently
+	checkOpts := HealthCheckOptions{}
+	if opts != nil {
+		checkOpts = *opts
 	}
+	opts = &checkOpts
 	if opts.Timeout == 0 {
 		opts.Timeout = 5 * time.Second
 	}
@@ -73,10 +81,10 @@ func (e *Executor) HealthCheck(opts *HealthCheckOptions) *HealthCheckResult {
 	var reqURL string
 	if opts.BucketName != "" {
 		// Check specific bucket
-		reqURL = e.endpointURL.String() + "/" + opts.BucketName
+		reqURL = endpointURL.String() + "/" + opts.BucketName
 	} else {
 		// Check root endpoint
-		reqURL = e.endpointURL.String() + "/"
+		reqURL = endpointURL.String() + "/"
 	}
 
 	// Try HEAD request first
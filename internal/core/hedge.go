@@ -0,0 +1,114 @@
+// Package core internal/core/hedge.go
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
+)
+
+// hedgeable reports whether an attempt may be hedged: object reads
+// without a body, when the executor has a hedger
+func (e *Executor) hedgeable(method string, meta RequestMetadata) bool {
+	return e.hedger != nil && (method == http.MethodGet || method == http.MethodHead) &&
+		meta.ObjectName != "" && meta.ContentBody == nil
+}
+
+// hedgeResult is the outcome of one of the requests of a hedged attempt
+type hedgeResult struct {
+	resp   *http.Response
+	err    error
+	hedge  bool
+	cancel context.CancelFunc
+}
+
+// doHedged sends req and, if no headers have arrived within the hedge
+// delay and the rate limit allows another request right away, an identical
+// request to the same endpoint. The first answer is returned and the other
+// request cancelled. It reports whether the hedge was sent.
+func (e *Executor) doHedged(ctx context.Context, req *http.Request, class ratelimit.Class) (*http.Response, bool, error) {
+	results := make(chan hedgeResult, 2)
+	send := func(r *http.Request, hedge bool, cancel context.CancelFunc) {
+		resp, err := e.httpClient.Do(r)
+		results <- hedgeResult{resp: resp, err: err, hedge: hedge, cancel: cancel}
+	}
+
+	start := time.Now()
+	firstCtx, cancelFirst := context.WithCancel(req.Context())
+	go send(req.WithContext(firstCtx), false, cancelFirst)
+
+	timer := time.NewTimer(e.hedger.Delay())
+	defer timer.Stop()
+
+	cancelHedge := context.CancelFunc(func() {})
+	pending, hedged := 1, false
+	timerC := timer.C
+	var result hedgeResult
+wait:
+	for {
+		select {
+		case <-timerC:
+			timerC = nil
+			if !e.rateLimits.AllowRequest(class) {
+				continue
+			}
+			// The hedge gets the client's trace hooks but not the attempt's
+			// trace, which the first request is recording
+			hedgeCtx := ctx
+			if e.clientTrace != nil {
+				hedgeCtx = httptrace.WithClientTrace(hedgeCtx, e.clientTrace)
+			}
+			hedgeCtx, cancelHedge = context.WithCancel(hedgeCtx)
+			go send(req.Clone(hedgeCtx), true, cancelHedge)
+			pending++
+			hedged = true
+		case result = <-results:
+			pending--
+			if result.err == nil || pending == 0 {
+				break wait
+			}
+			// Wait for the other request
+			result.cancel()
+		}
+	}
+
+	// Cancel the losing request and release its response
+	if pending > 0 {
+		if result.hedge {
+			cancelFirst()
+		} else {
+			cancelHedge()
+		}
+		go func() {
+			loser := <-results
+			closeResponse(loser.resp)
+			loser.cancel()
+		}()
+	}
+
+	e.hedger.Record(hedged, result.hedge)
+	if result.err != nil {
+		result.cancel()
+		return nil, hedged, result.err
+	}
+	e.hedger.Observe(time.Since(start))
+	result.resp.Body = &cancelOnClose{ReadCloser: result.resp.Body, cancel: result.cancel}
+	return result.resp, hedged, nil
+}
+
+// cancelOnClose cancels the context of a hedged request's winner when its
+// body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/hedge"
+	"github.com/Scorpio69t/rustfs-go/pkg/metrics"
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
+)
+
+// newHedgeTestExecutor returns an executor hedging after 20ms, whose server
+// answers the first request of each object slowly
+func newHedgeTestExecutor(t *testing.T, limits *ratelimit.Limits) (*Executor, *atomic.Int32, *metrics.Memory) {
+	t.Helper()
+	var requests atomic.Int32
+	release := make(chan struct{})
+	collector := metrics.NewMemory()
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1)%2 == 1 {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				return
+			case <-time.After(300 * time.Millisecond):
+			}
+			w.Header().Set("x-served-by", "first")
+		} else {
+			w.Header().Set("x-served-by", "hedge")
+		}
+		_, _ = w.Write([]byte("data"))
+	}, fastPolicy(3), withHedger(hedge.New(hedge.Config{InitialDelay: 20 * time.Millisecond})), withRateLimits(limits), withMetrics(collector))
+	// Cleanups run last first, so slow handlers return before the server closes
+	t.Cleanup(func() { close(release) })
+	return executor, &requests, collector
+}
+
+// withHedger makes the executor hedge reads with hedger
+func withHedger(hedger *hedge.Hedger) func(*ExecutorConfig) {
+	return func(config *ExecutorConfig) {
+		config.Hedger = hedger
+	}
+}
+
+func TestExecuteHedgesSlowReads(t *testing.T) {
+	executor, requests, collector := newHedgeTestExecutor(t, nil)
+
+	start := time.Now()
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	closeResponse(resp)
+
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("hedged read took %v, want well under the slow response", elapsed)
+	}
+	if got := resp.Header.Get("x-served-by"); got != "hedge" || string(body) != "data" {
+		t.Errorf("answer served by %q with body %q, want the hedge", got, body)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("server received %d requests, want 2", requests.Load())
+	}
+
+	stats := executor.hedger.Stats()
+	if stats.Requests != 1 || stats.Hedged != 1 || stats.Won != 1 {
+		t.Errorf("hedge stats = %+v", stats)
+	}
+	if hedged := collector.Snapshot().Aggregate(nil).Hedged; hedged != 1 {
+		t.Errorf("metrics report %d hedged requests, want 1", hedged)
+	}
+}
+
+func TestExecuteHedgingRespectsLimitsAndMethods(t *testing.T) {
+	limits := ratelimit.New(ratelimit.Config{RequestsPerSecond: map[ratelimit.Class]float64{ratelimit.ClassRead: 1}})
+	executor, requests, _ := newHedgeTestExecutor(t, limits)
+
+	// The only read token goes to the first request, so no hedge is sent
+	req := NewRequest(context.Background(), http.MethodHead, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+	if requests.Load() != 1 || resp.Header.Get("x-served-by") != "first" {
+		t.Errorf("server received %d requests, want 1 without a hedge", requests.Load())
+	}
+	if stats := executor.hedger.Stats(); stats.Requests != 1 || stats.Hedged != 0 {
+		t.Errorf("hedge stats = %+v", stats)
+	}
+
+	// Writes are never hedged
+	req = NewRequest(context.Background(), http.MethodPut, RequestMetadata{
+		BucketName: "bucket", ObjectName: "key", ContentBody: strings.NewReader("data"), ContentLength: 4,
+	})
+	resp, err = executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+	if stats := executor.hedger.Stats(); stats.Requests != 1 {
+		t.Errorf("PUT was considered for hedging: %+v", stats)
+	}
+}
@@ -0,0 +1,97 @@
+// Package core internal/core/interceptor.go
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Interceptor hooks into every attempt the executor makes, including retries.
+// Hooks run in registration order; an error returned by BeforeSign or
+// AfterSign aborts the request with that error.
+type Interceptor interface {
+	// BeforeSign is called before an attempt is built and signed. Changes to
+	// meta, such as added headers, apply to that attempt only.
+	BeforeSign(ctx context.Context, operation string, meta *RequestMetadata) error
+
+	// AfterSign is called with the signed request before it is sent.
+	// Headers changed here are not covered by the signature.
+	AfterSign(ctx context.Context, operation string, req *http.Request) error
+
+	// AfterAttempt is called once the attempt completes, with its response or
+	// transport error. It must not consume or close the response body.
+	AfterAttempt(ctx context.Context, operation string, req *http.Request, resp *http.Response, err error)
+}
+
+// InterceptorFuncs is an Interceptor built from optional hook functions
+type InterceptorFuncs struct {
+	BeforeSignFunc   func(ctx context.Context, operation string, meta *RequestMetadata) error
+	AfterSignFunc    func(ctx context.Context, operation string, req *http.Request) error
+	AfterAttemptFunc func(ctx context.Context, operation string, req *http.Request, resp *http.Response, err error)
+}
+
+// BeforeSign implements Interceptor
+func (f InterceptorFuncs) BeforeSign(ctx context.Context, operation string, meta *RequestMetadata) error {
+	if f.BeforeSignFunc == nil {
+		return nil
+	}
+	return f.BeforeSignFunc(ctx, operation, meta)
+}
+
+// AfterSign implements Interceptor
+func (f InterceptorFuncs) AfterSign(ctx context.Context, operation string, req *http.Request) error {
+	if f.AfterSignFunc == nil {
+		return nil
+	}
+	return f.AfterSignFunc(ctx, operation, req)
+}
+
+// AfterAttempt implements Interceptor
+func (f InterceptorFuncs) AfterAttempt(ctx context.Context, operation string, req *http.Request, resp *http.Response, err error) {
+	if f.AfterAttemptFunc != nil {
+		f.AfterAttemptFunc(ctx, operation, req, resp, err)
+	}
+}
+
+// prepareAttempt builds and signs one attempt, running the interceptors
+// around signing
+func (e *Executor) prepareAttempt(ctx context.Context, req *Request, meta RequestMetadata, operation string, endpointURL *url.URL) (*http.Request, error) {
+	if len(e.interceptors) > 0 {
+		meta.CustomHeader = meta.CustomHeader.Clone()
+		if meta.CustomHeader == nil {
+			meta.CustomHeader = make(http.Header)
+		}
+		if meta.QueryValues != nil {
+			query := make(url.Values, len(meta.QueryValues))
+			for k, v := range meta.QueryValues {
+				query[k] = append([]string(nil), v...)
+			}
+			meta.QueryValues = query
+		}
+		for _, interceptor := range e.interceptors {
+			if err := interceptor.BeforeSign(ctx, operation, &meta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	httpReq, err := e.buildHTTPRequest(ctx, req, meta, endpointURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, interceptor := range e.interceptors {
+		if err := interceptor.AfterSign(ctx, operation, httpReq); err != nil {
+			return nil, err
+		}
+	}
+	return httpReq, nil
+}
+
+// afterAttempt runs the interceptors' AfterAttempt hooks
+func (e *Executor) afterAttempt(ctx context.Context, operation string, req *http.Request, resp *http.Response, err error) {
+	for _, interceptor := range e.interceptors {
+		interceptor.AfterAttempt(ctx, operation, req, resp, err)
+	}
+}
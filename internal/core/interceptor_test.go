@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/retry"
+)
+
+// recordingInterceptor records the hooks it sees and tags requests with a tenant header
+type recordingInterceptor struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingInterceptor) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingInterceptor) BeforeSign(_ context.Context, operation string, meta *RequestMetadata) error {
+	r.record("before:" + operation + ":" + meta.BucketName + "/" + meta.ObjectName)
+	meta.CustomHeader.Add("X-Tenant", "team-a")
+	return nil
+}
+
+func (r *recordingInterceptor) AfterSign(_ context.Context, _ string, req *http.Request) error {
+	if strings.Contains(req.Header.Get("Authorization"), "x-tenant") {
+		r.record("signed")
+	}
+	return nil
+}
+
+func (r *recordingInterceptor) AfterAttempt(_ context.Context, _ string, _ *http.Request, resp *http.Response, err error) {
+	if err != nil {
+		r.record("error")
+		return
+	}
+	r.record(http.StatusText(resp.StatusCode))
+}
+
+func TestExecuteRunsInterceptorsOnEveryAttempt(t *testing.T) {
+	var tenants []string
+	attempts := 0
+	interceptor := &recordingInterceptor{}
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		tenants = append(tenants, strings.Join(r.Header.Values("X-Tenant"), ","))
+		if attempts++; attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(3))
+	executor.interceptors = []Interceptor{interceptor}
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "object"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+
+	want := []string{
+		"before:GetObject:bucket/object", "signed", "Service Unavailable",
+		"before:GetObject:bucket/object", "signed", "OK",
+	}
+	if strings.Join(interceptor.events, " ") != strings.Join(want, " ") {
+		t.Errorf("events = %v, want %v", interceptor.events, want)
+	}
+	// Headers added before signing do not accumulate across retries
+	if strings.Join(tenants, " ") != "team-a team-a" {
+		t.Errorf("X-Tenant headers = %q", tenants)
+	}
+}
+
+func TestExecuteInterceptorAbortsRequest(t *testing.T) {
+	sent := false
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+		w.WriteHeader(http.StatusOK)
+	}, retry.New(1))
+
+	denied := errors.New("tenant quota exceeded")
+	executor.interceptors = []Interceptor{InterceptorFuncs{
+		AfterSignFunc: func(context.Context, string, *http.Request) error { return denied },
+	}}
+
+	req := NewRequest(context.Background(), http.MethodPut, RequestMetadata{BucketName: "bucket"})
+	if _, err := executor.Execute(context.Background(), req); !errors.Is(err, denied) {
+		t.Fatalf("Execute() error = %v, want %v", err, denied)
+	}
+	if sent {
+		t.Error("request was sent despite the interceptor error")
+	}
+}
@@ -0,0 +1,46 @@
+// Package core internal/core/metrics.go
+package core
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/metrics"
+	"github.com/Scorpio69t/rustfs-go/pkg/retry"
+)
+
+// finalAttempt describes the last attempt of a request. attempts counts
+// every request sent, including those re-signed for another region or the
+// server's clock, which keep the retry number of the attempt they repeat.
+type finalAttempt struct {
+	info            retry.Attempt
+	attempts        int
+	timeToFirstByte time.Duration
+	hedged          bool
+}
+
+// recordMetrics reports a completed request to the metrics collector
+func (e *Executor) recordMetrics(req *Request, operation string, start time.Time, last finalAttempt, resp *http.Response, err error) {
+	if e.metrics == nil {
+		return
+	}
+
+	record := metrics.Request{
+		Operation:       operation,
+		Method:          req.Method(),
+		Bucket:          req.metadata.BucketName,
+		StatusCode:      last.info.StatusCode,
+		ErrorCode:       last.info.Code,
+		Err:             err,
+		Attempts:        last.attempts,
+		RequestBytes:    max(req.metadata.ContentLength, 0),
+		Duration:        time.Since(start),
+		TimeToFirstByte: last.timeToFirstByte,
+		Hedged:          last.hedged,
+	}
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+		record.ResponseBytes = max(resp.ContentLength, 0)
+	}
+	e.metrics.RecordRequest(record)
+}
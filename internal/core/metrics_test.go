@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/internal/cache"
+	"github.com/Scorpio69t/rustfs-go/pkg/metrics"
+)
+
+// withMetrics makes the executor record metrics to collector
+func withMetrics(collector metrics.Collector) func(*ExecutorConfig) {
+	return func(config *ExecutorConfig) {
+		config.MetricsCollector = collector
+	}
+}
+
+func TestExecuteRecordsMetrics(t *testing.T) {
+	var attempts atomic.Int32
+	collector := metrics.NewMemory()
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`<Error><Code>SlowDown</Code></Error>`))
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(3), withMetrics(collector))
+
+	for _, req := range []*Request{
+		NewRequest(context.Background(), http.MethodPut, RequestMetadata{
+			BucketName: "bucket", ObjectName: "key", ContentBody: strings.NewReader("payload"), ContentLength: 7,
+		}),
+		NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "missing"}),
+	} {
+		resp, err := executor.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		closeResponse(resp)
+	}
+
+	snapshot := collector.Snapshot()
+	put := snapshot.Aggregate(func(k metrics.Key) bool { return k.Operation == "PutObject" })
+	if put.Requests != 1 || put.Retries != 1 || put.RequestBytes != 7 || put.Failed != 0 {
+		t.Errorf("PutObject stats = %+v", put)
+	}
+	if put.TimeToFirstByte.Count != 1 || put.Latency.Max <= 0 {
+		t.Errorf("PutObject latencies = %+v / %+v", put.Latency, put.TimeToFirstByte)
+	}
+
+	get := snapshot.Aggregate(func(k metrics.Key) bool {
+		return k.Bucket == "bucket" && k.StatusClass == "4xx" && k.ErrorCode == "NoSuchKey"
+	})
+	if get.Requests != 1 || get.Failed != 1 {
+		t.Errorf("GetObject stats = %+v", get)
+	}
+}
+
+func TestExecuteMetricsCountRedirectedAttempts(t *testing.T) {
+	collector := metrics.NewMemory()
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if signedRegion(r) != "site-b" {
+			w.Header().Set(bucketRegionHeader, "site-b")
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(1), withMetrics(collector), withLocationCache(cache.NewLocationCache(0)))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+
+	// The request re-signed for the bucket's region is a second attempt
+	stats := collector.Snapshot().Aggregate(nil)
+	if stats.Requests != 1 || stats.Retries != 1 {
+		t.Errorf("stats = %+v, want one request retried once", stats)
+	}
+}
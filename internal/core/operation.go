@@ -0,0 +1,150 @@
+// Package core internal/core/operation.go
+package core
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// RequestInfo describes the S3 call an HTTP request was sent for
+type RequestInfo struct {
+	// Operation is the S3 operation, e.g. PutObject
+	Operation  string
+	BucketName string
+	ObjectName string
+}
+
+type requestInfoKey struct{}
+
+// withRequestInfo returns ctx carrying info for the transport
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the S3 call of an HTTP request sent by the
+// executor, from the request's context. Transports use it to tell
+// operations apart without parsing URLs.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// postOperations maps the subresource of a POST request to its operation
+var postOperations = map[string]string{
+	"uploads":  "CreateMultipartUpload",
+	"uploadId": "CompleteMultipartUpload",
+	"delete":   "DeleteObjects",
+	"restore":  "RestoreObject",
+	"select":   "SelectObjectContent",
+}
+
+// operationName returns the S3 operation a request performs, taking
+// meta.Operation when set. Other subresource requests are named after the
+// method, target and subresource, e.g. GetBucketVersioning.
+func operationName(method string, meta RequestMetadata) string {
+	if meta.Operation != "" {
+		return meta.Operation
+	}
+
+	query := meta.QueryValues
+	has := func(key string) bool {
+		_, ok := query[key]
+		return ok
+	}
+
+	if method == http.MethodPost {
+		for _, key := range []string{"uploads", "uploadId", "delete", "restore", "select"} {
+			if has(key) {
+				return postOperations[key]
+			}
+		}
+	}
+
+	if meta.BucketName == "" {
+		return "ListBuckets"
+	}
+
+	if meta.ObjectName != "" {
+		switch {
+		case method == http.MethodPut && has("partNumber") && meta.CustomHeader.Get("x-amz-copy-source") != "":
+			return "UploadPartCopy"
+		case method == http.MethodPut && has("partNumber"):
+			return "UploadPart"
+		case method == http.MethodGet && has("uploadId"):
+			return "ListParts"
+		case method == http.MethodDelete && has("uploadId"):
+			return "AbortMultipartUpload"
+		}
+	} else {
+		switch {
+		case method == http.MethodGet && has("uploads"):
+			return "ListMultipartUploads"
+		case method == http.MethodGet && has("versions"):
+			return "ListObjectVersions"
+		}
+	}
+
+	target := "Object"
+	if meta.ObjectName == "" {
+		target = "Bucket"
+	}
+	if sub := subresource(query); sub != "" {
+		return methodVerb(method) + target + sub
+	}
+
+	switch {
+	case meta.ObjectName == "" && method == http.MethodGet:
+		if query.Get("list-type") == "2" {
+			return "ListObjectsV2"
+		}
+		return "ListObjects"
+	case meta.ObjectName == "" && method == http.MethodPut:
+		return "CreateBucket"
+	case method == http.MethodPut && meta.CustomHeader.Get("x-amz-copy-source") != "":
+		return "CopyObject"
+	case method == http.MethodPut && meta.CustomHeader.Get("x-amz-write-offset-bytes") != "":
+		return "AppendObject"
+	}
+	return methodVerb(method) + target
+}
+
+// subresource returns the first value-less query key in CamelCase,
+// e.g. "object-lock" becomes "ObjectLock"
+func subresource(query map[string][]string) string {
+	var name string
+	for key, values := range query {
+		if len(values) != 1 || values[0] != "" || strings.HasPrefix(key, "x-") {
+			continue
+		}
+		if name == "" || key < name {
+			name = key
+		}
+	}
+
+	var b strings.Builder
+	for _, word := range strings.Split(name, "-") {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// methodVerb returns the operation verb of an HTTP method
+func methodVerb(method string) string {
+	switch method {
+	case http.MethodGet:
+		return "Get"
+	case http.MethodPut:
+		return "Put"
+	case http.MethodDelete:
+		return "Delete"
+	case http.MethodHead:
+		return "Head"
+	case http.MethodPost:
+		return "Post"
+	default:
+		return method
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		method string
+		meta   RequestMetadata
+		want   string
+	}{
+		{http.MethodGet, RequestMetadata{}, "ListBuckets"},
+		{http.MethodPut, RequestMetadata{BucketName: "b"}, "CreateBucket"},
+		{http.MethodGet, RequestMetadata{BucketName: "b", QueryValues: url.Values{"list-type": {"2"}}}, "ListObjectsV2"},
+		{http.MethodGet, RequestMetadata{BucketName: "b", QueryValues: url.Values{"location": {""}}}, "GetBucketLocation"},
+		{http.MethodPut, RequestMetadata{BucketName: "b", QueryValues: url.Values{"object-lock": {""}}}, "PutBucketObjectLock"},
+		{http.MethodGet, RequestMetadata{BucketName: "b", ObjectName: "o"}, "GetObject"},
+		{http.MethodHead, RequestMetadata{BucketName: "b", ObjectName: "o"}, "HeadObject"},
+		{http.MethodPut, RequestMetadata{BucketName: "b", ObjectName: "o", CustomHeader: http.Header{"X-Amz-Copy-Source": {"/s/k"}}}, "CopyObject"},
+		{http.MethodPut, RequestMetadata{BucketName: "b", ObjectName: "o", CustomHeader: http.Header{"X-Amz-Write-Offset-Bytes": {"10"}}}, "AppendObject"},
+		{http.MethodPut, RequestMetadata{BucketName: "b", ObjectName: "o", QueryValues: url.Values{"partNumber": {"1"}, "uploadId": {"u"}}}, "UploadPart"},
+		{http.MethodPost, RequestMetadata{BucketName: "b", ObjectName: "o", QueryValues: url.Values{"uploads": {""}}}, "CreateMultipartUpload"},
+		{http.MethodPost, RequestMetadata{BucketName: "b", ObjectName: "o", QueryValues: url.Values{"uploadId": {"u"}}}, "CompleteMultipartUpload"},
+		{http.MethodDelete, RequestMetadata{BucketName: "b", ObjectName: "o", QueryValues: url.Values{"uploadId": {"u"}}}, "AbortMultipartUpload"},
+		{http.MethodPost, RequestMetadata{BucketName: "b", QueryValues: url.Values{"delete": {""}}}, "DeleteObjects"},
+		{http.MethodGet, RequestMetadata{BucketName: "b", ObjectName: "o", QueryValues: url.Values{"tagging": {""}, "versionId": {"v"}}}, "GetObjectTagging"},
+		{http.MethodGet, RequestMetadata{Operation: "Custom", BucketName: "b"}, "Custom"},
+	}
+
+	for _, tt := range tests {
+		if got := operationName(tt.method, tt.meta); got != tt.want {
+			t.Errorf("operationName(%s, %+v) = %q, want %q", tt.method, tt.meta, got, tt.want)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequestInfoFromContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var got RequestInfo
+	var ok bool
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got, ok = RequestInfoFromContext(req.Context())
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	serverURL, _ := url.Parse(server.URL)
+	executor := NewExecutor(ExecutorConfig{
+		HTTPClient:   client,
+		EndpointURL:  serverURL,
+		Credentials:  credentials.NewStaticV4("access-key", "secret-key", ""),
+		BucketLookup: int(types.BucketLookupPath),
+	})
+
+	req := NewRequest(context.Background(), http.MethodHead, RequestMetadata{BucketName: "bucket", ObjectName: "dir/key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	resp.Body.Close()
+
+	want := RequestInfo{Operation: "HeadObject", BucketName: "bucket", ObjectName: "dir/key"}
+	if !ok || got != want {
+		t.Errorf("RequestInfoFromContext() = %+v, %v, want %+v", got, ok, want)
+	}
+	if _, ok := RequestInfoFromContext(context.Background()); ok {
+		t.Error("RequestInfoFromContext() of a bare context reported info")
+	}
+}
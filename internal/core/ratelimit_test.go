@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
+)
+
+// withRateLimits makes the executor apply limits
+func withRateLimits(limits *ratelimit.Limits) func(*ExecutorConfig) {
+	return func(config *ExecutorConfig) {
+		config.RateLimits = limits
+	}
+}
+
+func TestExecuteLimitsBandwidth(t *testing.T) {
+	payload := strings.Repeat("x", 13000)
+	var received int
+	limits := ratelimit.New(ratelimit.Config{UploadBytesPerSecond: 10000, DownloadBytesPerSecond: 10000})
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			received = len(body)
+			return
+		}
+		_, _ = w.Write([]byte(payload))
+	}, fastPolicy(3), withRateLimits(limits))
+
+	// Each direction has 10000 bytes of burst and 3000 bytes to wait for
+	start := time.Now()
+	req := NewRequest(context.Background(), http.MethodPut, RequestMetadata{
+		BucketName: "bucket", ObjectName: "key", ContentBody: strings.NewReader(payload), ContentLength: int64(len(payload)),
+	})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PUT error = %v", err)
+	}
+	closeResponse(resp)
+	if elapsed := time.Since(start); received != len(payload) || elapsed < 250*time.Millisecond {
+		t.Errorf("upload of %d bytes took %v, want about 300ms", received, elapsed)
+	}
+
+	start = time.Now()
+	req = NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err = executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	closeResponse(resp)
+	if elapsed := time.Since(start); len(body) != len(payload) || elapsed < 250*time.Millisecond {
+		t.Errorf("download of %d bytes took %v, want about 300ms", len(body), elapsed)
+	}
+}
+
+func TestExecuteLimitsRequestRate(t *testing.T) {
+	limits := ratelimit.New(ratelimit.Config{RequestsPerSecond: map[ratelimit.Class]float64{ratelimit.ClassDelete: 20}})
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, fastPolicy(3), withRateLimits(limits))
+
+	run := func(method string, n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			req := NewRequest(context.Background(), method, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+			resp, err := executor.Execute(context.Background(), req)
+			if err != nil {
+				t.Fatalf("%s error = %v", method, err)
+			}
+			closeResponse(resp)
+		}
+		return time.Since(start)
+	}
+
+	// 20 deletes are in the burst, the next 5 wait 50ms each
+	if elapsed := run(http.MethodDelete, 25); elapsed < 200*time.Millisecond {
+		t.Errorf("25 deletes at 20/s took %v, want about 250ms", elapsed)
+	}
+	// Other classes are not limited
+	if elapsed := run(http.MethodGet, 25); elapsed > 200*time.Millisecond {
+		t.Errorf("25 unlimited reads took %v", elapsed)
+	}
+}
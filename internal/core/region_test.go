@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/internal/cache"
+)
+
+// signedRegion returns the region in a SigV4 Authorization header
+func signedRegion(r *http.Request) string {
+	_, credential, _ := strings.Cut(r.Header.Get("Authorization"), "Credential=")
+	scope := strings.Split(credential, "/")
+	if len(scope) < 3 {
+		return ""
+	}
+	return scope[2]
+}
+
+// withLocationCache makes the executor cache bucket regions in locationCache
+func withLocationCache(locationCache *cache.LocationCache) func(*ExecutorConfig) {
+	return func(config *ExecutorConfig) {
+		config.LocationCache = locationCache
+	}
+}
+
+func TestExecuteLearnsRegionFromErrorBody(t *testing.T) {
+	var attempts atomic.Int32
+	locationCache := cache.NewLocationCache(0)
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if region := signedRegion(r); region != "site-b" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`<Error><Code>AuthorizationHeaderMalformed</Code>` +
+				`<Message>the region '` + region + `' is wrong; expecting 'site-b'</Message><Region>site-b</Region></Error>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(1), withLocationCache(locationCache))
+
+	put := func() {
+		t.Helper()
+		req := NewRequest(context.Background(), http.MethodPut, RequestMetadata{
+			BucketName:    "bucket",
+			ObjectName:    "object",
+			ContentBody:   strings.NewReader("data"),
+			ContentLength: 4,
+		})
+		resp, err := executor.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		defer closeResponse(resp)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("StatusCode = %d, want 200", resp.StatusCode)
+		}
+	}
+
+	// The first request is re-signed for the reported region, even with retries disabled
+	put()
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+	if location, _ := locationCache.Get("bucket"); location != "site-b" {
+		t.Errorf("cached location = %q, want site-b", location)
+	}
+
+	// Later requests use the cached region straight away
+	put()
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestExecuteFollowsRegionRedirect(t *testing.T) {
+	var attempts atomic.Int32
+	locationCache := cache.NewLocationCache(0)
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("x-amz-bucket-region", "site-c")
+		if signedRegion(r) != "site-c" {
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(1), withLocationCache(locationCache))
+
+	req := NewRequest(context.Background(), http.MethodHead, RequestMetadata{BucketName: "bucket", ObjectName: "object"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	defer closeResponse(resp)
+
+	if resp.StatusCode != http.StatusOK || attempts.Load() != 2 {
+		t.Errorf("StatusCode = %d after %d attempts, want 200 after 2", resp.StatusCode, attempts.Load())
+	}
+	if location, _ := locationCache.Get("bucket"); location != "site-c" {
+		t.Errorf("cached location = %q, want site-c", location)
+	}
+}
+
+func TestExecuteRegionRedirectLoop(t *testing.T) {
+	var attempts atomic.Int32
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		// A misbehaving server that always claims another region
+		attempts.Add(1)
+		w.Header().Set("x-amz-bucket-region", "region-"+string(rune('a'+attempts.Load())))
+		w.WriteHeader(http.StatusMovedPermanently)
+	}, fastPolicy(1), withLocationCache(cache.NewLocationCache(0)))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "object"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	defer closeResponse(resp)
+
+	if resp.StatusCode != http.StatusMovedPermanently || attempts.Load() != 2 {
+		t.Errorf("StatusCode = %d after %d attempts, want 301 after 2", resp.StatusCode, attempts.Load())
+	}
+}
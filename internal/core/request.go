@@ -10,6 +10,10 @@ import (
 
 // RequestMetadata holds request metadata
 type RequestMetadata struct {
+	// Operation names the S3 API call, e.g. CompleteMultipartUpload.
+	// Derived from the method, query and headers when empty
+	Operation string
+
 	// Bucket and object
 	BucketName string
 	ObjectName string
@@ -29,6 +33,7 @@ type RequestMetadata struct {
 	ContentSHA256Hex string
 
 	// Signing options
+	// StreamSHA256 allows a non-seekable PUT body to be sent as aws-chunked
 	StreamSHA256 bool
 	PresignURL   bool
 	Expires      int64
@@ -40,8 +45,10 @@ type RequestMetadata struct {
 	BucketLocation string
 
 	// Trailer (for streaming signature)
+	// Keys name the checksum trailers (e.g. x-amz-checksum-crc32c) computed while streaming
 	Trailer http.Header
-	AddCRC  bool
+	// AddCRC sends the trailers even when the client does not enable trailing headers
+	AddCRC bool
 
 	// Special handling
 	Expect200OKWithError bool
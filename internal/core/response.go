@@ -59,6 +59,16 @@ func (p *ResponseParser) ParseObjectInfo(resp *http.Response, bucketName, object
 		}
 	}
 
+	// Parse Expires
+	if expires := header.Get("Expires"); expires != "" {
+		if t, err := time.Parse(http.TimeFormat, expires); err == nil {
+			info.Expires = t
+		}
+	}
+
+	// Keep the object headers, such as Content-Encoding and Cache-Control
+	info.Metadata = header.Clone()
+
 	// Parse version info
 	info.VersionID = header.Get("x-amz-version-id")
 	info.IsDeleteMarker = header.Get("x-amz-delete-marker") == "true"
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/pkg/retry"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// newRetryTestExecutor returns an executor for handler using policy, with
+// opts adjusting the configuration
+func newRetryTestExecutor(t *testing.T, handler http.HandlerFunc, policy retry.Policy, opts ...func(*ExecutorConfig)) *Executor {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	endpointURL, _ := url.Parse(server.URL)
+	config := ExecutorConfig{
+		HTTPClient:   server.Client(),
+		EndpointURL:  endpointURL,
+		Credentials:  credentials.NewStaticV4("access-key", "secret-key", ""),
+		Region:       "us-east-1",
+		BucketLookup: int(types.BucketLookupPath),
+		MaxRetries:   5,
+		RetryPolicy:  policy,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewExecutor(config)
+}
+
+// fastPolicy is a Standard policy without backoff delays
+func fastPolicy(maxAttempts int) *retry.Standard {
+	policy := retry.New(maxAttempts)
+	policy.BaseDelay = 0
+	return policy
+}
+
+const internalErrorBody = `<Error><Code>InternalError</Code><Message>We encountered an internal error.</Message></Error>`
+
+func TestExecuteDoesNotRetryNonIdempotentPost(t *testing.T) {
+	var attempts atomic.Int32
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(internalErrorBody))
+	}, fastPolicy(5))
+
+	req := NewRequest(context.Background(), http.MethodPost, RequestMetadata{
+		BucketName:  "bucket",
+		ObjectName:  "object",
+		QueryValues: url.Values{"uploadId": {"upload-1"}},
+	})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	defer closeResponse(resp)
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+	// The error body read for classification is still available to the caller
+	apiErr := errors.ToAPIError(errors.ParseErrorResponse(resp, "bucket", "object"))
+	if apiErr == nil || apiErr.ErrorCode != "InternalError" {
+		t.Errorf("parsed error = %v, want InternalError", apiErr)
+	}
+}
+
+func TestExecuteRetriesErrorCodes(t *testing.T) {
+	var attempts atomic.Int32
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			// RequestTimeout is retryable even though 400 is not
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`<Error><Code>RequestTimeout</Code></Error>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(5))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "object"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	defer closeResponse(resp)
+
+	if resp.StatusCode != http.StatusOK || attempts.Load() != 2 {
+		t.Errorf("StatusCode = %d after %d attempts, want 200 after 2", resp.StatusCode, attempts.Load())
+	}
+}
+
+func TestExecuteHonoursRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	var first time.Time
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`<Error><Code>SlowDown</Code></Error>`))
+			return
+		}
+		if waited := time.Since(first); waited < 900*time.Millisecond {
+			t.Errorf("retried after %v, want about 1s", waited)
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(5))
+
+	// Throttled requests are retried even for non-idempotent operations
+	req := NewRequest(context.Background(), http.MethodPost, RequestMetadata{
+		BucketName:  "bucket",
+		ObjectName:  "object",
+		QueryValues: url.Values{"uploads": {""}},
+	})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	defer closeResponse(resp)
+
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want 2", attempts.Load())
+	}
+}
+
+func TestExecuteRetryBudget(t *testing.T) {
+	var attempts atomic.Int32
+	policy := fastPolicy(10)
+	policy.Budget = retry.NewBudget(15, 5)
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, policy)
+
+	// The budget affords three retries in total across requests
+	for i := 0; i < 2; i++ {
+		req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "object"})
+		resp, err := executor.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		closeResponse(resp)
+	}
+
+	if got := attempts.Load(); got != 5 {
+		t.Errorf("attempts = %d, want 5 (2 requests + 3 budgeted retries)", got)
+	}
+}
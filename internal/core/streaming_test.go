@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// chunkedUpload is an aws-chunked request body decoded by the test server
+type chunkedUpload struct {
+	header     http.Header
+	data       []byte
+	chunks     int
+	signatures int
+	trailers   map[string]string
+}
+
+// decodeChunked parses an aws-chunked body, checking its length against Content-Length
+func decodeChunked(r *http.Request) (*chunkedUpload, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) != r.ContentLength {
+		return nil, fmt.Errorf("body is %d bytes, Content-Length %d", len(body), r.ContentLength)
+	}
+
+	upload := &chunkedUpload{header: r.Header, trailers: map[string]string{}}
+	br := bufio.NewReader(strings.NewReader(string(body)))
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\r\n")
+		sizeHex, signature, _ := strings.Cut(line, ";")
+		if signature != "" {
+			upload.signatures++
+		}
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		upload.chunks++
+		if size == 0 {
+			break
+		}
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return nil, err
+		}
+		upload.data = append(upload.data, chunk[:size]...)
+	}
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line = strings.TrimSuffix(line, "\r\n"); line == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ":")
+		upload.trailers[name] = value
+	}
+	return upload, nil
+}
+
+// newStreamingTestExecutor returns an executor whose server decodes aws-chunked uploads
+func newStreamingTestExecutor(t *testing.T, signerType credentials.SignatureType, trailing bool, uploads chan<- *chunkedUpload) *Executor {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			_, _ = io.Copy(io.Discard, r.Body)
+			uploads <- &chunkedUpload{header: r.Header}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		upload, err := decodeChunked(r)
+		if err != nil {
+			t.Errorf("decode aws-chunked body: %v", err)
+			upload = &chunkedUpload{header: r.Header}
+		}
+		uploads <- upload
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	endpointURL, _ := url.Parse(server.URL)
+	return NewExecutor(ExecutorConfig{
+		HTTPClient:      server.Client(),
+		EndpointURL:     endpointURL,
+		Credentials:     credentials.NewStatic("access-key", "secret-key", "", signerType),
+		Region:          "us-east-1",
+		BucketLookup:    int(types.BucketLookupPath),
+		MaxRetries:      1,
+		TrailingHeaders: trailing,
+	})
+}
+
+func executeStreamingPut(t *testing.T, executor *Executor, body io.Reader, size int64, trailer http.Header) {
+	t.Helper()
+	req := NewRequest(context.Background(), http.MethodPut, RequestMetadata{
+		BucketName:    "bucket",
+		ObjectName:    "object",
+		ContentBody:   body,
+		ContentLength: size,
+		CustomHeader:  http.Header{"Content-Encoding": {"gzip"}},
+		StreamSHA256:  true,
+		Trailer:       trailer,
+	})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestExecuteStreamingSignedUpload(t *testing.T) {
+	uploads := make(chan *chunkedUpload, 1)
+	executor := newStreamingTestExecutor(t, credentials.SignatureV4Streaming, false, uploads)
+
+	// An exact multiple of the chunk size must end with a single empty chunk
+	data := strings.Repeat("a", 2*64*1024)
+	executeStreamingPut(t, executor, io.LimitReader(strings.NewReader(data), int64(len(data))), int64(len(data)), nil)
+
+	upload := <-uploads
+	if got := upload.header.Get("X-Amz-Content-Sha256"); got != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		t.Fatalf("X-Amz-Content-Sha256 = %q", got)
+	}
+	if got := upload.header.Get("Content-Encoding"); got != "aws-chunked,gzip" {
+		t.Errorf("Content-Encoding = %q", got)
+	}
+	if got := upload.header.Get("X-Amz-Decoded-Content-Length"); got != strconv.Itoa(len(data)) {
+		t.Errorf("X-Amz-Decoded-Content-Length = %q", got)
+	}
+	if string(upload.data) != data {
+		t.Fatalf("decoded %d bytes, want %d", len(upload.data), len(data))
+	}
+	if upload.chunks != 3 || upload.signatures != 3 {
+		t.Errorf("chunks = %d, signatures = %d, want 3 signed chunks", upload.chunks, upload.signatures)
+	}
+}
+
+func TestExecuteStreamingTrailerUpload(t *testing.T) {
+	uploads := make(chan *chunkedUpload, 1)
+	executor := newStreamingTestExecutor(t, credentials.SignatureV4Streaming, true, uploads)
+
+	data := strings.Repeat("trailer", 20000)
+	trailer := http.Header{"x-amz-checksum-crc64nvme": nil}
+	executeStreamingPut(t, executor, io.LimitReader(strings.NewReader(data), int64(len(data))), int64(len(data)), trailer)
+
+	upload := <-uploads
+	if got := upload.header.Get("X-Amz-Content-Sha256"); got != "STREAMING-UNSIGNED-PAYLOAD-TRAILER" {
+		t.Fatalf("X-Amz-Content-Sha256 = %q", got)
+	}
+	if got := upload.header.Get("X-Amz-Trailer"); got != "x-amz-checksum-crc64nvme" {
+		t.Errorf("X-Amz-Trailer = %q", got)
+	}
+	if upload.signatures != 0 {
+		t.Errorf("unsigned trailer upload has %d chunk signatures", upload.signatures)
+	}
+	if string(upload.data) != data {
+		t.Fatalf("decoded %d bytes, want %d", len(upload.data), len(data))
+	}
+
+	h := checksum.New(types.ChecksumCRC64NVME)
+	h.Write([]byte(data))
+	if got, want := upload.trailers["x-amz-checksum-crc64nvme"], checksum.Encode(h.Sum(nil)); got != want {
+		t.Errorf("crc64nvme trailer = %q, want %q", got, want)
+	}
+}
+
+func TestExecuteStreamingSkipsSeekableBodies(t *testing.T) {
+	uploads := make(chan *chunkedUpload, 1)
+	executor := newStreamingTestExecutor(t, credentials.SignatureV4Streaming, true, uploads)
+
+	executeStreamingPut(t, executor, strings.NewReader("seekable"), 8, http.Header{"x-amz-checksum-crc32c": nil})
+
+	upload := <-uploads
+	if got := upload.header.Get("X-Amz-Content-Sha256"); got != "UNSIGNED-PAYLOAD" {
+		t.Errorf("X-Amz-Content-Sha256 = %q, want UNSIGNED-PAYLOAD", got)
+	}
+}
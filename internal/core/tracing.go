@@ -0,0 +1,213 @@
+// Package core internal/core/tracing.go
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/internal/transport"
+	"github.com/Scorpio69t/rustfs-go/pkg/retry"
+	"github.com/Scorpio69t/rustfs-go/pkg/tracing"
+)
+
+// callKey is the context key of the traced call a request belongs to
+type callKey struct{}
+
+// Call is a traced SDK call spanning one or more requests. A nil Call is
+// valid and does nothing.
+type Call struct {
+	span tracing.Span
+	// auto marks calls opened by Execute for a single request, which fail
+	// on HTTP error responses as well as on errors
+	auto bool
+
+	mu        sync.Mutex
+	retries   int
+	sent      int64
+	received  int64
+	status    int
+	code      string
+	requestID string
+}
+
+// StartCall opens the span of a logical SDK call such as Object.Put.
+// Requests executed with the returned context are traced as part of it.
+// Without a tracer the context is returned unchanged with a nil Call.
+func (e *Executor) StartCall(ctx context.Context, name, bucketName, objectName string) (context.Context, *Call) {
+	if e.tracer == nil {
+		return ctx, nil
+	}
+	attrs := make([]tracing.Attribute, 0, 2)
+	if bucketName != "" {
+		attrs = append(attrs, tracing.String(tracing.AttrBucket, bucketName))
+	}
+	if objectName != "" {
+		attrs = append(attrs, tracing.String(tracing.AttrKey, objectName))
+	}
+	ctx, span := e.tracer.Start(ctx, name, attrs...)
+	call := &Call{span: span}
+	return context.WithValue(ctx, callKey{}, call), call
+}
+
+// joinCall returns the call open in ctx, or opens one named after the
+// operation for requests sent outside an SDK call, such as bucket location
+// lookups, reporting whether the caller owns and must end it
+func (e *Executor) joinCall(ctx context.Context, operation string, meta RequestMetadata) (context.Context, *Call, bool) {
+	if e.tracer == nil {
+		return ctx, nil, false
+	}
+	if call, ok := ctx.Value(callKey{}).(*Call); ok {
+		return ctx, call, false
+	}
+	ctx, call := e.StartCall(ctx, operation, meta.BucketName, meta.ObjectName)
+	call.auto = true
+	call.span.SetAttributes(tracing.String(tracing.AttrOperation, operation))
+	return ctx, call, true
+}
+
+// observe records the outcome of an attempt made during the call
+func (c *Call) observe(info retry.Attempt, meta RequestMetadata, resp *http.Response, success bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if info.Number > 0 {
+		c.retries++
+	}
+	c.status = info.StatusCode
+	c.code = info.Code
+	c.requestID = ""
+	if resp != nil {
+		c.requestID = resp.Header.Get("x-amz-request-id")
+	}
+	if success {
+		if meta.ContentLength > 0 {
+			c.sent += meta.ContentLength
+		}
+		if resp.ContentLength > 0 {
+			c.received += resp.ContentLength
+		}
+	}
+}
+
+// End completes the call, recording err, the retry count and bytes
+// transferred
+func (c *Call) End(err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status, code, requestID := c.status, c.code, c.requestID
+	if apiErr := errors.ToAPIError(err); apiErr != nil {
+		status, code = apiErr.ErrorStatusCode, apiErr.ErrorCode
+		if apiErr.ErrorRequestID != "" {
+			requestID = apiErr.ErrorRequestID
+		}
+	}
+	if err == nil && c.auto && status >= http.StatusBadRequest {
+		err = &errors.APIError{
+			ErrorCode:       code,
+			ErrorMessage:    http.StatusText(status),
+			ErrorStatusCode: status,
+			ErrorRequestID:  requestID,
+		}
+	}
+
+	attrs := []tracing.Attribute{
+		tracing.Int(tracing.AttrRetryCount, c.retries),
+		tracing.Int64(tracing.AttrBytesSent, c.sent),
+		tracing.Int64(tracing.AttrBytesReceived, c.received),
+	}
+	attrs = appendResultAttributes(attrs, status, code, requestID)
+	c.span.SetAttributes(attrs...)
+	if err != nil {
+		c.span.RecordError(err)
+	}
+	c.span.End()
+}
+
+// attemptTrace records the timings of one HTTP attempt and, with a tracer,
+// its span. A nil attemptTrace does nothing.
+type attemptTrace struct {
+	span  tracing.Span
+	info  *transport.TraceInfo
+	start time.Time
+}
+
+// startAttempt returns the context of an attempt, carrying the client's
+// httptrace hooks and, when tracing or collecting metrics, the attempt trace
+func (e *Executor) startAttempt(ctx context.Context, method string, number int) (context.Context, *attemptTrace) {
+	if e.clientTrace != nil {
+		ctx = httptrace.WithClientTrace(ctx, e.clientTrace)
+	}
+	if e.tracer == nil && e.metrics == nil {
+		return ctx, nil
+	}
+
+	trace := &attemptTrace{start: time.Now()}
+	if e.tracer != nil {
+		ctx, trace.span = e.tracer.Start(ctx, method,
+			tracing.String(tracing.AttrMethod, method),
+			tracing.Int(tracing.AttrResendCount, number))
+	}
+	ctx, trace.info = transport.WithTraceInfo(ctx)
+	return ctx, trace
+}
+
+// timeToFirstByte returns the time from starting the attempt to the first
+// response byte, or zero if none arrived
+func (a *attemptTrace) timeToFirstByte() time.Duration {
+	if a == nil || a.info.GotFirstResponse.IsZero() {
+		return 0
+	}
+	return a.info.GotFirstResponse.Sub(a.start)
+}
+
+// end completes the attempt span with the request, response and connection timings
+func (a *attemptTrace) end(req *http.Request, resp *http.Response, info retry.Attempt) {
+	if a == nil || a.span == nil {
+		return
+	}
+
+	var attrs []tracing.Attribute
+	if req != nil {
+		target := url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: req.URL.Path, RawPath: req.URL.RawPath}
+		attrs = append(attrs, tracing.String(tracing.AttrURL, target.String()))
+	}
+	requestID := ""
+	if resp != nil {
+		requestID = resp.Header.Get("x-amz-request-id")
+	}
+	attrs = appendResultAttributes(attrs, info.StatusCode, info.Code, requestID)
+	for name, timing := range a.info.GetTimings() {
+		attrs = append(attrs, tracing.Milliseconds(tracing.AttrTimingPrefix+name+"_ms", timing))
+	}
+	a.span.SetAttributes(attrs...)
+	if info.Err != nil {
+		a.span.RecordError(info.Err)
+	}
+	a.span.End()
+}
+
+// appendResultAttributes appends the status, error code and request ID that are set
+func appendResultAttributes(attrs []tracing.Attribute, status int, code, requestID string) []tracing.Attribute {
+	if status != 0 {
+		attrs = append(attrs, tracing.Int(tracing.AttrStatusCode, status))
+	}
+	if code != "" {
+		attrs = append(attrs, tracing.String(tracing.AttrErrorCode, code))
+	}
+	if requestID != "" {
+		attrs = append(attrs, tracing.String(tracing.AttrRequestID, requestID))
+	}
+	return attrs
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/tracing"
+)
+
+// withTracer makes the executor report to recorder
+func withTracer(recorder *tracing.Recorder) func(*ExecutorConfig) {
+	return func(config *ExecutorConfig) {
+		config.Tracer = recorder
+	}
+}
+
+func TestExecuteTracesCallAndAttempts(t *testing.T) {
+	var attempts atomic.Int32
+	var gotConn atomic.Int32
+	recorder := tracing.NewRecorder()
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-amz-request-id", "req-"+r.Method)
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`<Error><Code>SlowDown</Code></Error>`))
+			return
+		}
+		w.Header().Set("Content-Length", "5")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("hello"))
+	}, fastPolicy(3), withTracer(recorder), func(config *ExecutorConfig) {
+		config.ClientTrace = &httptrace.ClientTrace{GotConn: func(httptrace.GotConnInfo) { gotConn.Add(1) }}
+	})
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+
+	spans := recorder.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 2 attempts and 1 call", len(spans))
+	}
+	first, second, call := spans[0], spans[1], spans[2]
+
+	if call.Name != "GetObject" || call.Parent != nil || call.Err != nil {
+		t.Errorf("call span = %s (parent %v, err %v)", call.Name, call.Parent, call.Err)
+	}
+	wantCall := map[string]any{
+		tracing.AttrOperation:     "GetObject",
+		tracing.AttrBucket:        "bucket",
+		tracing.AttrKey:           "key",
+		tracing.AttrRetryCount:    int64(1),
+		tracing.AttrStatusCode:    int64(200),
+		tracing.AttrRequestID:     "req-GET",
+		tracing.AttrBytesReceived: int64(5),
+	}
+	for key, want := range wantCall {
+		if got := call.Attributes[key]; got != want {
+			t.Errorf("call %s = %v, want %v", key, got, want)
+		}
+	}
+
+	for i, attempt := range []*tracing.RecordedSpan{first, second} {
+		if attempt.Name != http.MethodGet || attempt.Parent != call {
+			t.Errorf("attempt %d span = %s, parent %v", i, attempt.Name, attempt.Parent)
+		}
+		if got := attempt.Attributes[tracing.AttrResendCount]; got != int64(i) {
+			t.Errorf("attempt %d resend count = %v", i, got)
+		}
+	}
+	if first.Attributes[tracing.AttrErrorCode] != "SlowDown" || first.Attributes[tracing.AttrStatusCode] != int64(503) {
+		t.Errorf("first attempt attributes = %v", first.Attributes)
+	}
+	if _, ok := first.Attributes[tracing.AttrTimingPrefix+"tcp_connect_ms"]; !ok {
+		t.Errorf("first attempt has no connection timings: %v", first.Attributes)
+	}
+	if gotConn.Load() != 2 {
+		t.Errorf("client trace GotConn called %d times, want 2", gotConn.Load())
+	}
+}
+
+func TestStartCallGroupsRequests(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, fastPolicy(3), withTracer(recorder))
+
+	ctx, call := executor.StartCall(context.Background(), "Object.Put", "bucket", "key")
+	for _, method := range []string{http.MethodHead, http.MethodPut} {
+		req := NewRequest(ctx, method, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+		resp, err := executor.Execute(ctx, req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		closeResponse(resp)
+	}
+	call.End(nil)
+
+	spans := recorder.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 2 attempts and 1 call", len(spans))
+	}
+	root := spans[2]
+	if root.Name != "Object.Put" || root.Err != nil {
+		t.Errorf("call span = %s, err %v", root.Name, root.Err)
+	}
+	for _, span := range spans[:2] {
+		if span.Parent != root {
+			t.Errorf("%s span is not a child of the call", span.Name)
+		}
+	}
+}
+
+func TestExecuteTracesErrorResponses(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	executor := newRetryTestExecutor(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><RequestId>req-1</RequestId></Error>`))
+	}, fastPolicy(3), withTracer(recorder))
+
+	req := NewRequest(context.Background(), http.MethodGet, RequestMetadata{BucketName: "bucket", ObjectName: "key"})
+	resp, err := executor.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	closeResponse(resp)
+
+	spans := recorder.Spans()
+	call := spans[len(spans)-1]
+	if call.Err == nil || call.Attributes[tracing.AttrErrorCode] != "NoSuchKey" {
+		t.Errorf("call span err = %v, attributes = %v", call.Err, call.Attributes)
+	}
+}
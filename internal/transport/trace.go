@@ -50,9 +50,20 @@ func NewTraceContext(ctx context.Context, hook TraceHook) context.Context {
 		return ctx
 	}
 
+	return httptrace.WithClientTrace(ctx, newClientTrace(&TraceInfo{}, hook))
+}
+
+// WithTraceInfo returns a context that records the HTTP trace of a request
+// into the returned TraceInfo, which is complete once the response arrives
+func WithTraceInfo(ctx context.Context) (context.Context, *TraceInfo) {
 	trace := &TraceInfo{}
+	return httptrace.WithClientTrace(ctx, newClientTrace(trace, nil)), trace
+}
 
-	clientTrace := &httptrace.ClientTrace{
+// newClientTrace returns a ClientTrace recording into trace, calling hook
+// once a connection is obtained
+func newClientTrace(trace *TraceInfo, hook TraceHook) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
 		// DNS
 		DNSStart: func(info httptrace.DNSStartInfo) {
 			trace.DNSStart = time.Now()
@@ -116,8 +127,6 @@ func NewTraceContext(ctx context.Context, hook TraceHook) context.Context {
 			}
 		},
 	}
-
-	return httptrace.WithClientTrace(ctx, clientTrace)
 }
 
 // GetTimings returns durations for each stage
@@ -12,7 +12,10 @@ import (
 )
 
 // SetACL sets the ACL for an object.
-func (s *objectService) SetACL(ctx context.Context, bucketName, objectName string, policy acl.ACL) error {
+func (s *objectService) SetACL(ctx context.Context, bucketName, objectName string, policy acl.ACL) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.SetACL", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -56,7 +59,10 @@ func (s *objectService) SetACL(ctx context.Context, bucketName, objectName strin
 }
 
 // GetACL retrieves the ACL for an object.
-func (s *objectService) GetACL(ctx context.Context, bucketName, objectName string) (acl.ACL, error) {
+func (s *objectService) GetACL(ctx context.Context, bucketName, objectName string) (_ acl.ACL, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.GetACL", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return acl.ACL{}, err
 	}
@@ -505,8 +505,9 @@ func TestAbortMultipartUpload(t *testing.T) {
 	}
 }
 
-// createAdvancedTestService creates a service instance for testing
-func createAdvancedTestService(t *testing.T, server *httptest.Server) *objectService {
+// createAdvancedTestService creates a service instance for testing, with
+// opts adjusting the executor configuration
+func createAdvancedTestService(t *testing.T, server *httptest.Server, opts ...func(*core.ExecutorConfig)) *objectService {
 	t.Helper()
 
 	serverURL, err := url.Parse(server.URL)
@@ -517,14 +518,18 @@ func createAdvancedTestService(t *testing.T, server *httptest.Server) *objectSer
 	creds := credentials.NewStaticV4("access-key", "secret-key", "")
 	locationCache := cache.NewLocationCache(0)
 
-	executor := core.NewExecutor(core.ExecutorConfig{
+	config := core.ExecutorConfig{
 		HTTPClient:   server.Client(),
 		EndpointURL:  serverURL,
 		Credentials:  creds,
 		Region:       "us-east-1",
 		BucketLookup: int(types.BucketLookupPath),
 		MaxRetries:   1,
-	})
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	executor := core.NewExecutor(config)
 
 	return &objectService{
 		executor:      executor,
@@ -13,7 +13,10 @@ import (
 )
 
 // Append appends data to an existing object at the provided offset.
-func (s *objectService) Append(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, offset int64, opts ...PutOption) (types.UploadInfo, error) {
+func (s *objectService) Append(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, offset int64, opts ...PutOption) (_ types.UploadInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Append", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return types.UploadInfo{}, err
 	}
@@ -27,7 +30,7 @@ func (s *objectService) Append(ctx context.Context, bucketName, objectName strin
 		return types.UploadInfo{}, fmt.Errorf("object size must be non-negative")
 	}
 	if offset < 0 {
-		info, err := s.Stat(ctx, bucketName, objectName)
+		info, err := s.stat(ctx, bucketName, objectName)
 		if err != nil {
 			return types.UploadInfo{}, err
 		}
@@ -1,7 +1,17 @@
 // Package object object/checksum.go
 package object
 
-import "github.com/Scorpio69t/rustfs-go/internal/core"
+import (
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
 
 func applyChecksumHeaders(meta *core.RequestMetadata, options PutOptions) {
 	if options.ChecksumMode != "" {
@@ -11,3 +21,233 @@ func applyChecksumHeaders(meta *core.RequestMetadata, options PutOptions) {
 		meta.CustomHeader.Set("x-amz-checksum-algorithm", options.ChecksumAlgorithm)
 	}
 }
+
+// applyStreamingOptions lets the executor send non-seekable bodies as
+// aws-chunked. The trailer uses the requested checksum algorithm, or CRC32C;
+// it is always sent when an algorithm was requested, and otherwise only
+// when the client enables trailing headers.
+func applyStreamingOptions(meta *core.RequestMetadata, options PutOptions) {
+	meta.StreamSHA256 = true
+
+	algorithm, err := checksum.Parse(options.ChecksumAlgorithm)
+	if err != nil || algorithm == types.ChecksumNone {
+		algorithm = types.ChecksumCRC32C
+	} else {
+		meta.AddCRC = true
+	}
+	meta.Trailer = http.Header{checksum.HeaderKey(algorithm): nil}
+}
+
+// applyChecksumValue computes the requested checksum of a seekable body and
+// sends it as x-amz-checksum-<algorithm>, returning the encoded value.
+// Non-seekable bodies are left to the streamed checksum trailer.
+func applyChecksumValue(meta *core.RequestMetadata, reader io.Reader, size int64, options PutOptions) (types.ChecksumType, string, error) {
+	algorithm, err := checksum.Parse(options.ChecksumAlgorithm)
+	if err != nil || algorithm == types.ChecksumNone {
+		return algorithm, "", err
+	}
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok {
+		return algorithm, "", nil
+	}
+
+	value, err := computeChecksum(seeker, size, algorithm)
+	if err != nil {
+		return algorithm, "", err
+	}
+	meta.CustomHeader.Set(checksum.HeaderKey(algorithm), value)
+	return algorithm, value, nil
+}
+
+// computeChecksum hashes size bytes of reader (all of it if size < 0) and
+// seeks back to where it started
+func computeChecksum(reader io.ReadSeeker, size int64, algorithm types.ChecksumType) (string, error) {
+	start, err := reader.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+
+	h := checksum.New(algorithm)
+	if size >= 0 {
+		_, err = io.CopyN(h, reader, size)
+	} else {
+		_, err = io.Copy(h, reader)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := reader.Seek(start, io.SeekStart); err != nil {
+		return "", err
+	}
+	return checksum.Encode(h.Sum(nil)), nil
+}
+
+// partChecksumField returns the checksum field of part for algorithm
+func partChecksumField(part *types.ObjectPart, algorithm types.ChecksumType) *string {
+	switch algorithm {
+	case types.ChecksumCRC32:
+		return &part.ChecksumCRC32
+	case types.ChecksumCRC32C:
+		return &part.ChecksumCRC32C
+	case types.ChecksumSHA1:
+		return &part.ChecksumSHA1
+	case types.ChecksumSHA256:
+		return &part.ChecksumSHA256
+	case types.ChecksumCRC64NVME:
+		return &part.ChecksumCRC64NVME
+	default:
+		return nil
+	}
+}
+
+// uploadChecksumField returns the checksum field of info for algorithm
+func uploadChecksumField(info *types.UploadInfo, algorithm types.ChecksumType) *string {
+	switch algorithm {
+	case types.ChecksumCRC32:
+		return &info.ChecksumCRC32
+	case types.ChecksumCRC32C:
+		return &info.ChecksumCRC32C
+	case types.ChecksumSHA1:
+		return &info.ChecksumSHA1
+	case types.ChecksumSHA256:
+		return &info.ChecksumSHA256
+	case types.ChecksumCRC64NVME:
+		return &info.ChecksumCRC64NVME
+	default:
+		return nil
+	}
+}
+
+// objectChecksum returns the checksum reported for an object, if any
+func objectChecksum(info types.ObjectInfo) (types.ChecksumType, string) {
+	switch {
+	case info.ChecksumCRC64NVME != "":
+		return types.ChecksumCRC64NVME, info.ChecksumCRC64NVME
+	case info.ChecksumCRC32C != "":
+		return types.ChecksumCRC32C, info.ChecksumCRC32C
+	case info.ChecksumCRC32 != "":
+		return types.ChecksumCRC32, info.ChecksumCRC32
+	case info.ChecksumSHA256 != "":
+		return types.ChecksumSHA256, info.ChecksumSHA256
+	case info.ChecksumSHA1 != "":
+		return types.ChecksumSHA1, info.ChecksumSHA1
+	default:
+		return types.ChecksumNone, ""
+	}
+}
+
+// partsChecksumAlgorithm returns the algorithm requested in options, or the
+// one the first part carries a checksum for
+func partsChecksumAlgorithm(parts []types.ObjectPart, options PutOptions) (types.ChecksumType, error) {
+	algorithm, err := checksum.Parse(options.ChecksumAlgorithm)
+	if err != nil || algorithm != types.ChecksumNone || len(parts) == 0 {
+		return algorithm, err
+	}
+	for _, candidate := range []types.ChecksumType{
+		types.ChecksumCRC32, types.ChecksumCRC32C, types.ChecksumSHA1, types.ChecksumSHA256, types.ChecksumCRC64NVME,
+	} {
+		if *partChecksumField(&parts[0], candidate) != "" {
+			return candidate, nil
+		}
+	}
+	return types.ChecksumNone, nil
+}
+
+// compositeChecksum returns the checksum of the object the parts make up,
+// or "" if any part lacks a checksum for algorithm. CRC64NVME parts are
+// combined into the full-object checksum, which needs the part sizes;
+// other algorithms give a composite checksum.
+func compositeChecksum(parts []types.ObjectPart, algorithm types.ChecksumType) (string, error) {
+	if algorithm == types.ChecksumNone {
+		return "", nil
+	}
+	sums := make([][]byte, 0, len(parts))
+	sizes := make([]int64, 0, len(parts))
+	for i := range parts {
+		value := *partChecksumField(&parts[i], algorithm)
+		if value == "" {
+			return "", nil
+		}
+		if algorithm == types.ChecksumCRC64NVME && parts[i].Size <= 0 {
+			return "", nil
+		}
+		sum, err := checksum.Decode(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s checksum for part %d: %w", algorithm, parts[i].PartNumber, err)
+		}
+		sums = append(sums, sum)
+		sizes = append(sizes, parts[i].Size)
+	}
+	if algorithm == types.ChecksumCRC64NVME {
+		return checksum.CombineCRC64NVME(sums, sizes), nil
+	}
+	return checksum.Composite(algorithm, sums), nil
+}
+
+// applyCompositeChecksum reports the locally computed checksum of parts in
+// info, or verifies it against a checksum of the same kind, composite or
+// full-object, returned by the server
+func applyCompositeChecksum(info *types.UploadInfo, algorithm types.ChecksumType, composite, resource string) error {
+	field := uploadChecksumField(info, algorithm)
+	if field == nil || composite == "" {
+		return nil
+	}
+	switch {
+	case *field == "":
+		*field = composite
+	case checksum.IsComposite(*field) == checksum.IsComposite(composite) && *field != composite:
+		return &errors.ChecksumMismatchError{
+			Algorithm: algorithm.String(),
+			Expected:  composite,
+			Actual:    *field,
+			Resource:  resource,
+		}
+	}
+	return nil
+}
+
+// checksumReader verifies the data read through it against an expected
+// checksum when the underlying reader is exhausted
+type checksumReader struct {
+	reader    io.Reader
+	hash      hash.Hash
+	algorithm types.ChecksumType
+	expected  string
+	resource  string
+	err       error
+}
+
+// newChecksumReader returns a reader that fails with a ChecksumMismatchError
+// at EOF if the data does not match expected
+func newChecksumReader(reader io.Reader, algorithm types.ChecksumType, expected, resource string) *checksumReader {
+	return &checksumReader{
+		reader:    reader,
+		hash:      checksum.New(algorithm),
+		algorithm: algorithm,
+		expected:  expected,
+		resource:  resource,
+	}
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.reader.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if actual := checksum.Encode(r.hash.Sum(nil)); actual != r.expected {
+			err = &errors.ChecksumMismatchError{
+				Algorithm: r.algorithm.String(),
+				Expected:  r.expected,
+				Actual:    actual,
+				Resource:  r.resource,
+			}
+		}
+	}
+	if err != nil {
+		r.err = err
+	}
+	return n, err
+}
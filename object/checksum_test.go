@@ -0,0 +1,252 @@
+// Package object object/checksum_test.go
+package object
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/internal/cache"
+	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// createTrailerTestService creates a service whose executor sends checksum trailers
+func createTrailerTestService(t *testing.T, server *httptest.Server) *objectService {
+	t.Helper()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %v", err)
+	}
+
+	executor := core.NewExecutor(core.ExecutorConfig{
+		HTTPClient:      server.Client(),
+		EndpointURL:     serverURL,
+		Credentials:     credentials.NewStaticV4("access-key", "secret-key", ""),
+		Region:          "us-east-1",
+		BucketLookup:    int(types.BucketLookupPath),
+		MaxRetries:      1,
+		TrailingHeaders: true,
+	})
+
+	return &objectService{
+		executor:      executor,
+		locationCache: cache.NewLocationCache(0),
+	}
+}
+
+func TestPutStreamsChecksumTrailer(t *testing.T) {
+	data := strings.Repeat("checksum", 10000)
+
+	var contentSHA256, trailerNames, trailerLine string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentSHA256 = r.Header.Get("X-Amz-Content-Sha256")
+		trailerNames = r.Header.Get("X-Amz-Trailer")
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 128*1024), 128*1024)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "x-amz-checksum-") {
+				trailerLine = strings.TrimSuffix(line, "\r")
+			}
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := createTrailerTestService(t, server)
+
+	// A non-seekable reader is streamed with a trailer of the requested algorithm
+	reader := io.LimitReader(strings.NewReader(data), int64(len(data)))
+	if _, err := service.Put(context.Background(), "bucket", "object", reader, int64(len(data)),
+		WithChecksumAlgorithm("CRC64NVME")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if contentSHA256 != "STREAMING-UNSIGNED-PAYLOAD-TRAILER" {
+		t.Errorf("X-Amz-Content-Sha256 = %q", contentSHA256)
+	}
+	if trailerNames != "x-amz-checksum-crc64nvme" {
+		t.Errorf("X-Amz-Trailer = %q", trailerNames)
+	}
+	h := checksum.New(types.ChecksumCRC64NVME)
+	h.Write([]byte(data))
+	if want := "x-amz-checksum-crc64nvme:" + checksum.Encode(h.Sum(nil)); trailerLine != want {
+		t.Errorf("trailer = %q, want %q", trailerLine, want)
+	}
+}
+
+// checksumOf returns the encoded checksum of data
+func checksumOf(algorithm types.ChecksumType, data []byte) string {
+	h := checksum.New(algorithm)
+	h.Write(data)
+	return checksum.Encode(h.Sum(nil))
+}
+
+func TestPutSendsChecksumHeader(t *testing.T) {
+	data := []byte("checksummed object data")
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("x-amz-checksum-sha256")
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	info, err := service.Put(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)),
+		WithChecksumAlgorithm("SHA256"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	want := checksumOf(types.ChecksumSHA256, data)
+	if received != want {
+		t.Errorf("x-amz-checksum-sha256 = %q, want %q", received, want)
+	}
+	if info.ChecksumSHA256 != want {
+		t.Errorf("UploadInfo.ChecksumSHA256 = %q, want %q", info.ChecksumSHA256, want)
+	}
+
+	if _, err := service.Put(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)),
+		WithChecksumAlgorithm("MD4")); err == nil {
+		t.Error("Put() with an unsupported checksum algorithm should fail")
+	}
+}
+
+func TestPutMultipartCompositeChecksum(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("composite"), (11<<20)/9)
+	service := createAdvancedTestService(t, server)
+
+	info, err := service.Put(context.Background(), "test-bucket", "large.bin", bytes.NewReader(data), int64(len(data)),
+		WithPartSize(absMinPartSize), WithChecksumAlgorithm("CRC32C"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// Every completed part carries its checksum; the object gets the composite
+	var sums [][]byte
+	for _, part := range handler.completed {
+		want := checksumOf(types.ChecksumCRC32C, handler.parts[part.PartNumber])
+		if part.ChecksumCRC32C != want {
+			t.Errorf("part %d ChecksumCRC32C = %q, want %q", part.PartNumber, part.ChecksumCRC32C, want)
+		}
+		sum, _ := checksum.Decode(want)
+		sums = append(sums, sum)
+	}
+	if len(sums) != 3 {
+		t.Fatalf("completed %d parts, want 3", len(sums))
+	}
+	if want := checksum.Composite(types.ChecksumCRC32C, sums); info.ChecksumCRC32C != want {
+		t.Errorf("UploadInfo.ChecksumCRC32C = %q, want %q", info.ChecksumCRC32C, want)
+	}
+}
+
+func TestPutMultipartFullObjectCRC64NVME(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("crc64nvme"), (11<<20)/9)
+	service := createAdvancedTestService(t, server)
+
+	info, err := service.Put(context.Background(), "test-bucket", "large.bin", bytes.NewReader(data), int64(len(data)),
+		WithPartSize(absMinPartSize), WithChecksumAlgorithm("CRC64NVME"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if len(handler.completed) != 3 {
+		t.Fatalf("completed %d parts, want 3", len(handler.completed))
+	}
+	// CRC64NVME has no composite form; the parts combine into the checksum
+	// of the whole object
+	if want := checksumOf(types.ChecksumCRC64NVME, data); info.ChecksumCRC64NVME != want {
+		t.Errorf("UploadInfo.ChecksumCRC64NVME = %q, want the full-object checksum %q", info.ChecksumCRC64NVME, want)
+	}
+}
+
+func TestCompleteMultipartCompositeMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-amz-checksum-crc32", "AAAAAA==-2")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`))
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	parts := []types.ObjectPart{
+		{PartNumber: 1, ETag: "etag-1", ChecksumCRC32: checksumOf(types.ChecksumCRC32, []byte("one"))},
+		{PartNumber: 2, ETag: "etag-2", ChecksumCRC32: checksumOf(types.ChecksumCRC32, []byte("two"))},
+	}
+	_, err := service.CompleteMultipartUpload(context.Background(), "bucket", "object", "upload-1", parts)
+	if !errors.IsChecksumMismatch(err) {
+		t.Fatalf("CompleteMultipartUpload() error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestGetVerifiesChecksum(t *testing.T) {
+	data := []byte("verified download")
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  bool
+	}{
+		{name: "match", checksum: checksumOf(types.ChecksumCRC64NVME, data)},
+		{name: "mismatch", checksum: checksumOf(types.ChecksumCRC64NVME, []byte("other data")), wantErr: true},
+		{name: "composite skipped", checksum: "AAAAAAAAAAA=-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mode string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mode = r.Header.Get("x-amz-checksum-mode")
+				w.Header().Set("x-amz-checksum-crc64nvme", tt.checksum)
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(data)
+			}))
+			defer server.Close()
+
+			service := createAdvancedTestService(t, server)
+			reader, _, err := service.Get(context.Background(), "bucket", "object", WithGetChecksumMode())
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			defer func() { _ = reader.Close() }()
+
+			got, err := io.ReadAll(reader)
+			if mode != "ENABLED" {
+				t.Errorf("x-amz-checksum-mode = %q, want ENABLED", mode)
+			}
+			if tt.wantErr {
+				if !errors.IsChecksumMismatch(err) {
+					t.Fatalf("ReadAll() error = %v, want checksum mismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("data = %q, want %q", got, data)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/sse"
 	"github.com/Scorpio69t/rustfs-go/types"
 )
 
@@ -50,7 +51,10 @@ type copyPartResult struct {
 }
 
 // Compose creates a new object by composing one or more source objects.
-func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, sources []SourceInfo, opts ...PutOption) (types.UploadInfo, error) {
+func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, sources []SourceInfo, opts ...PutOption) (info types.UploadInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Compose", dst.Bucket, dst.Object)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(dst.Bucket); err != nil {
 		return types.UploadInfo{}, err
 	}
@@ -88,7 +92,7 @@ func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, source
 				opts.VersionID = src.VersionID
 			})
 		}
-		info, err := s.Stat(ctx, src.Bucket, src.Object, statOpts...)
+		info, err := s.stat(ctx, src.Bucket, src.Object, statOpts...)
 		if err != nil {
 			return types.UploadInfo{}, err
 		}
@@ -146,7 +150,8 @@ func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, source
 		}, nil
 	}
 
-	uploadID, err := s.InitiateMultipartUpload(ctx, dst.Bucket, dst.Object, opts...)
+	options := applyPutOptions(opts)
+	uploadID, err := s.initiateMultipartUpload(ctx, dst.Bucket, dst.Object, options)
 	if err != nil {
 		return types.UploadInfo{}, err
 	}
@@ -155,25 +160,7 @@ func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, source
 	partNumber := 1
 
 	for i, src := range sources {
-		header := make(http.Header)
-		copySource := fmt.Sprintf("%s/%s", src.Bucket, src.Object)
-		if src.VersionID != "" {
-			copySource += "?versionId=" + src.VersionID
-		}
-		header.Set("x-amz-copy-source", copySource)
-
-		if src.MatchETag != "" {
-			header.Set("x-amz-copy-source-if-match", src.MatchETag)
-		}
-		if src.NotMatchETag != "" {
-			header.Set("x-amz-copy-source-if-none-match", src.NotMatchETag)
-		}
-		if !src.MatchModified.IsZero() {
-			header.Set("x-amz-copy-source-if-modified-since", src.MatchModified.Format(http.TimeFormat))
-		}
-		if !src.NotModified.IsZero() {
-			header.Set("x-amz-copy-source-if-unmodified-since", src.NotModified.Format(http.TimeFormat))
-		}
+		header := composeCopySourceHeaders(src)
 
 		startIndex, endIndex := composeCalculateSplits(srcSizes[i], src)
 		for j, start := range startIndex {
@@ -190,7 +177,7 @@ func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, source
 		}
 	}
 
-	uploadInfo, err := s.CompleteMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID, parts, opts...)
+	uploadInfo, err := s.completeMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID, parts, options)
 	if err != nil {
 		return types.UploadInfo{}, err
 	}
@@ -199,6 +186,65 @@ func (s *objectService) Compose(ctx context.Context, dst DestinationInfo, source
 	return uploadInfo, nil
 }
 
+// UploadPartCopy uploads a part of a multipart upload by copying data from
+// an existing object. When src.RangeSet is true only the given byte range is
+// copied; otherwise the whole source object becomes the part.
+func (s *objectService) UploadPartCopy(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, src SourceInfo, opts ...PutOption) (_ types.ObjectPart, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.UploadPartCopy", bucketName, objectName)
+	defer func() { call.End(err) }()
+
+	// Validate inputs
+	if err := validateBucketName(bucketName); err != nil {
+		return types.ObjectPart{}, err
+	}
+	if err := validateObjectName(objectName); err != nil {
+		return types.ObjectPart{}, err
+	}
+	if err := validateBucketName(src.Bucket); err != nil {
+		return types.ObjectPart{}, err
+	}
+	if err := validateObjectName(src.Object); err != nil {
+		return types.ObjectPart{}, err
+	}
+	if src.RangeSet {
+		if src.RangeStart < 0 {
+			return types.ObjectPart{}, fmt.Errorf("range start must be >= 0")
+		}
+		if src.RangeEnd < src.RangeStart {
+			return types.ObjectPart{}, fmt.Errorf("range end must be >= range start")
+		}
+	}
+
+	options := applyPutOptions(opts)
+	header := composeCopySourceHeaders(src)
+	if src.RangeSet {
+		header.Set("x-amz-copy-source-range", fmt.Sprintf("bytes=%d-%d", src.RangeStart, src.RangeEnd))
+	}
+
+	// Merge custom headers
+	if options.CustomHeaders != nil {
+		for k, v := range options.CustomHeaders {
+			header[k] = v
+		}
+	}
+
+	// Set SSE-C headers for the destination if provided
+	if ssec, ok := options.SSE.(*sse.C); ok {
+		ssec.ApplyHeaders(header)
+	} else {
+		applySSECustomerHeaders(&core.RequestMetadata{CustomHeader: header}, options.SSECustomerAlgorithm, options.SSECustomerKey, options.SSECustomerKeyMD5)
+	}
+
+	part, err := s.uploadPartCopy(ctx, bucketName, objectName, uploadID, partNumber, header)
+	if err != nil {
+		return types.ObjectPart{}, err
+	}
+	if src.RangeSet {
+		part.Size = src.RangeEnd - src.RangeStart + 1
+	}
+	return part, nil
+}
+
 func (s *objectService) uploadPartCopy(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, headers http.Header) (types.ObjectPart, error) {
 	if headers == nil {
 		headers = make(http.Header)
@@ -293,6 +339,30 @@ func composeCopyOptions(src SourceInfo, opts ...PutOption) []CopyOption {
 	}}
 }
 
+// composeCopySourceHeaders builds the x-amz-copy-source headers for a source
+func composeCopySourceHeaders(src SourceInfo) http.Header {
+	header := make(http.Header)
+	copySource := fmt.Sprintf("%s/%s", src.Bucket, src.Object)
+	if src.VersionID != "" {
+		copySource += "?versionId=" + src.VersionID
+	}
+	header.Set("x-amz-copy-source", copySource)
+
+	if src.MatchETag != "" {
+		header.Set("x-amz-copy-source-if-match", src.MatchETag)
+	}
+	if src.NotMatchETag != "" {
+		header.Set("x-amz-copy-source-if-none-match", src.NotMatchETag)
+	}
+	if !src.MatchModified.IsZero() {
+		header.Set("x-amz-copy-source-if-modified-since", src.MatchModified.Format(http.TimeFormat))
+	}
+	if !src.NotModified.IsZero() {
+		header.Set("x-amz-copy-source-if-unmodified-since", src.NotModified.Format(http.TimeFormat))
+	}
+	return header
+}
+
 func composeHasConditions(src SourceInfo) bool {
 	return src.MatchETag != "" ||
 		src.NotMatchETag != "" ||
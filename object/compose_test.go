@@ -255,3 +255,59 @@ func TestComposeConditionalRange(t *testing.T) {
 		t.Fatalf("expected range header for compose copy")
 	}
 }
+
+func TestUploadPartCopy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected method %s", r.Method)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("uploadId") != "upload-id-1" || query.Get("partNumber") != "3" {
+			t.Errorf("unexpected PUT query %s", r.URL.RawQuery)
+		}
+		if got := r.Header.Get("x-amz-copy-source"); got != "src-bucket/src-object.txt?versionId=v1" {
+			t.Errorf("x-amz-copy-source = %q", got)
+		}
+		if got := r.Header.Get("x-amz-copy-source-range"); got != "bytes=100-199" {
+			t.Errorf("x-amz-copy-source-range = %q", got)
+		}
+		if got := r.Header.Get("x-amz-copy-source-if-match"); got != "etag-src" {
+			t.Errorf("x-amz-copy-source-if-match = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<CopyPartResult>
+  <ETag>"etag-part"</ETag>
+  <LastModified>2023-01-01T00:00:00Z</LastModified>
+</CopyPartResult>`)); err != nil {
+			t.Fatalf("Failed to write copy part response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	src := SourceInfo{
+		Bucket:     "src-bucket",
+		Object:     "src-object.txt",
+		VersionID:  "v1",
+		RangeStart: 100,
+		RangeEnd:   199,
+		RangeSet:   true,
+		MatchETag:  "etag-src",
+	}
+
+	part, err := service.UploadPartCopy(context.Background(), "dst-bucket", "dst-object.txt", "upload-id-1", 3, src)
+	if err != nil {
+		t.Fatalf("UploadPartCopy() error = %v", err)
+	}
+	if part.PartNumber != 3 || part.ETag != "etag-part" || part.Size != 100 {
+		t.Errorf("unexpected part %+v", part)
+	}
+
+	if _, err := service.UploadPartCopy(context.Background(), "dst-bucket", "dst-object.txt", "", 1, src); err == nil {
+		t.Error("UploadPartCopy() expected error for empty upload ID")
+	}
+}
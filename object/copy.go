@@ -20,7 +20,10 @@ type copyObjectResult struct {
 }
 
 // Copy copies an object (implementation)
-func (s *objectService) Copy(ctx context.Context, destBucket, destObject, sourceBucket, sourceObject string, opts ...CopyOption) (types.CopyInfo, error) {
+func (s *objectService) Copy(ctx context.Context, destBucket, destObject, sourceBucket, sourceObject string, opts ...CopyOption) (info types.CopyInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Copy", destBucket, destObject)
+	defer func() { call.End(err) }()
+
 	// Validate parameters
 	if err := validateBucketName(destBucket); err != nil {
 		return types.CopyInfo{}, err
@@ -67,6 +70,9 @@ func (s *objectService) Copy(ctx context.Context, destBucket, destObject, source
 		if options.ContentDisposition != "" {
 			meta.CustomHeader.Set("Content-Disposition", options.ContentDisposition)
 		}
+		if options.ContentLanguage != "" {
+			meta.CustomHeader.Set("Content-Language", options.ContentLanguage)
+		}
 		if options.CacheControl != "" {
 			meta.CustomHeader.Set("Cache-Control", options.CacheControl)
 		}
@@ -10,7 +10,10 @@ import (
 )
 
 // Delete deletes an object (implementation)
-func (s *objectService) Delete(ctx context.Context, bucketName, objectName string, opts ...DeleteOption) error {
+func (s *objectService) Delete(ctx context.Context, bucketName, objectName string, opts ...DeleteOption) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Delete", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	// Validate parameters
 	if err := validateBucketName(bucketName); err != nil {
 		return err
@@ -0,0 +1,202 @@
+// Package object object/delete_many.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// maxDeleteBatchSize is the maximum number of keys per DeleteObjects request
+const maxDeleteBatchSize = 1000
+
+// deleteObjectsRequest represents the DeleteObjects request body
+type deleteObjectsRequest struct {
+	XMLName xml.Name             `xml:"Delete"`
+	Quiet   bool                 `xml:"Quiet"`
+	Objects []deleteObjectsEntry `xml:"Object"`
+}
+
+// deleteObjectsEntry represents a single key in the DeleteObjects request
+type deleteObjectsEntry struct {
+	Key       string `xml:"Key"`
+	VersionID string `xml:"VersionId,omitempty"`
+}
+
+// deleteObjectsResult represents the DeleteObjects response
+type deleteObjectsResult struct {
+	XMLName xml.Name             `xml:"DeleteResult"`
+	Deleted []deletedObjectEntry `xml:"Deleted"`
+	Errors  []deleteErrorEntry   `xml:"Error"`
+}
+
+// deletedObjectEntry represents a successfully deleted key
+type deletedObjectEntry struct {
+	Key                   string `xml:"Key"`
+	VersionID             string `xml:"VersionId"`
+	DeleteMarker          bool   `xml:"DeleteMarker"`
+	DeleteMarkerVersionID string `xml:"DeleteMarkerVersionId"`
+}
+
+// deleteErrorEntry represents a key that failed to delete
+type deleteErrorEntry struct {
+	Key       string `xml:"Key"`
+	VersionID string `xml:"VersionId"`
+	Code      string `xml:"Code"`
+	Message   string `xml:"Message"`
+}
+
+// DeleteMany removes the objects received on objectsCh using batched
+// DeleteObjects requests of up to 1000 keys in quiet mode.
+//
+// Only failures are sent on the returned channel, which is closed once
+// objectsCh is closed and all batches are processed, or when ctx is done.
+// Keys of a batch whose request failed are reported with Err set.
+func (s *objectService) DeleteMany(ctx context.Context, bucketName string, objectsCh <-chan types.ObjectToDelete, opts ...DeleteManyOption) <-chan types.DeleteError {
+	ctx, call := s.executor.StartCall(ctx, "Object.DeleteMany", bucketName, "")
+	errorCh := make(chan types.DeleteError)
+
+	go func() {
+		// callErr is the first request failure, not counting keys the
+		// server refused to delete
+		var callErr error
+		defer func() { call.End(callErr) }()
+		defer close(errorCh)
+
+		sendError := func(deleteErr types.DeleteError) bool {
+			select {
+			case errorCh <- deleteErr:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if err := validateBucketName(bucketName); err != nil {
+			callErr = err
+			sendError(types.DeleteError{Err: err})
+			return
+		}
+
+		options := applyDeleteManyOptions(opts)
+		batch := make([]types.ObjectToDelete, 0, maxDeleteBatchSize)
+
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			deleteErrors, err := s.deleteObjectsBatch(ctx, bucketName, batch, options)
+			if err != nil {
+				if callErr == nil {
+					callErr = err
+				}
+				for _, object := range batch {
+					if !sendError(types.DeleteError{Key: object.Key, VersionID: object.VersionID, Err: err}) {
+						return false
+					}
+				}
+			}
+			for _, deleteErr := range deleteErrors {
+				if !sendError(deleteErr) {
+					return false
+				}
+			}
+			batch = batch[:0]
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				callErr = ctx.Err()
+				return
+			case object, ok := <-objectsCh:
+				if !ok {
+					flush()
+					return
+				}
+				if object.Key == "" {
+					continue
+				}
+				batch = append(batch, object)
+				if len(batch) == maxDeleteBatchSize && !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return errorCh
+}
+
+// deleteObjectsBatch sends a single DeleteObjects request and returns per-key errors
+func (s *objectService) deleteObjectsBatch(ctx context.Context, bucketName string, batch []types.ObjectToDelete, options DeleteManyOptions) ([]types.DeleteError, error) {
+	payload := deleteObjectsRequest{
+		Quiet:   true,
+		Objects: make([]deleteObjectsEntry, len(batch)),
+	}
+	for i, object := range batch {
+		payload.Objects[i] = deleteObjectsEntry{Key: object.Key, VersionID: object.VersionID}
+	}
+
+	body, err := xml.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal delete objects request: %w", err)
+	}
+
+	meta := core.RequestMetadata{
+		BucketName:       bucketName,
+		QueryValues:      url.Values{"delete": {""}},
+		CustomHeader:     make(http.Header),
+		ContentBody:      bytes.NewReader(body),
+		ContentLength:    int64(len(body)),
+		ContentMD5Base64: sumMD5Base64(body),
+		ContentSHA256Hex: sumSHA256Hex(body),
+	}
+	meta.CustomHeader.Set("Content-Type", "application/xml")
+
+	// Bypass governance retention when requested
+	if options.GovernanceBypass {
+		meta.CustomHeader.Set("x-amz-bypass-governance-retention", "true")
+	}
+
+	// Merge custom headers
+	if options.CustomHeaders != nil {
+		for k, v := range options.CustomHeaders {
+			meta.CustomHeader[k] = v
+		}
+	}
+
+	req := core.NewRequest(ctx, http.MethodPost, meta)
+	resp, err := s.executor.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponse(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseErrorResponse(resp, bucketName, "")
+	}
+
+	var result deleteObjectsResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode delete objects response: %w", err)
+	}
+
+	deleteErrors := make([]types.DeleteError, 0, len(result.Errors))
+	for _, entry := range result.Errors {
+		deleteErrors = append(deleteErrors, types.DeleteError{
+			Key:       entry.Key,
+			VersionID: entry.VersionID,
+			Code:      entry.Code,
+			Message:   entry.Message,
+		})
+	}
+	return deleteErrors, nil
+}
@@ -0,0 +1,111 @@
+// Package object object/delete_many_test.go
+package object
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+func TestDeleteMany(t *testing.T) {
+	var batchSizes []int
+	var bypass []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !r.URL.Query().Has("delete") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Content-MD5") == "" {
+			t.Error("Content-MD5 header not set")
+		}
+
+		var payload deleteObjectsRequest
+		if err := xml.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !payload.Quiet {
+			t.Error("expected quiet mode")
+		}
+		batchSizes = append(batchSizes, len(payload.Objects))
+		bypass = append(bypass, r.Header.Get("x-amz-bypass-governance-retention"))
+
+		w.WriteHeader(http.StatusOK)
+		response := `<DeleteResult>`
+		for _, object := range payload.Objects {
+			if object.Key == "locked-7" {
+				response += `<Error><Key>locked-7</Key><VersionId>` + object.VersionID + `</VersionId><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`
+			}
+		}
+		response += `</DeleteResult>`
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+
+	objectsCh := make(chan types.ObjectToDelete)
+	go func() {
+		defer close(objectsCh)
+		for i := 0; i < 2500; i++ {
+			key := fmt.Sprintf("object-%d", i)
+			if i == 7 {
+				key = "locked-7"
+			}
+			objectsCh <- types.ObjectToDelete{Key: key, VersionID: "v1"}
+		}
+	}()
+
+	var deleteErrors []types.DeleteError
+	for deleteErr := range service.DeleteMany(context.Background(), "test-bucket", objectsCh, WithDeleteManyGovernanceBypass()) {
+		deleteErrors = append(deleteErrors, deleteErr)
+	}
+
+	if fmt.Sprint(batchSizes) != "[1000 1000 500]" {
+		t.Errorf("batch sizes = %v, want [1000 1000 500]", batchSizes)
+	}
+	for i, value := range bypass {
+		if value != "true" {
+			t.Errorf("batch %d missing governance bypass header", i)
+		}
+	}
+	if len(deleteErrors) != 1 {
+		t.Fatalf("got %d delete errors, want 1", len(deleteErrors))
+	}
+	if deleteErrors[0].Key != "locked-7" || deleteErrors[0].Code != "AccessDenied" || deleteErrors[0].VersionID != "v1" {
+		t.Errorf("unexpected delete error %+v", deleteErrors[0])
+	}
+}
+
+func TestDeleteManyRequestFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+
+	objectsCh := make(chan types.ObjectToDelete, 2)
+	objectsCh <- types.ObjectToDelete{Key: "a"}
+	objectsCh <- types.ObjectToDelete{Key: "b"}
+	close(objectsCh)
+
+	var failed []string
+	for deleteErr := range service.DeleteMany(context.Background(), "test-bucket", objectsCh) {
+		if deleteErr.Err == nil {
+			t.Errorf("expected request error for key %s", deleteErr.Key)
+		}
+		failed = append(failed, deleteErr.Key)
+	}
+	if fmt.Sprint(failed) != "[a b]" {
+		t.Errorf("failed keys = %v, want [a b]", failed)
+	}
+}
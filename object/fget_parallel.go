@@ -0,0 +1,264 @@
+// Package object object/fget_parallel.go
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const (
+	// downloadStateSuffix is appended to the target path to name the sidecar state file
+	downloadStateSuffix = ".rustfs-download.json"
+	// downloadPartialSuffix is appended to the target path to name the partial file
+	downloadPartialSuffix = ".rustfs-partial"
+	// defaultDownloadPartSize is the default byte range size of parallel downloads
+	defaultDownloadPartSize = 16 * 1024 * 1024
+)
+
+// downloadState is the persisted state of a parallel FGet
+type downloadState struct {
+	Bucket    string `json:"bucket"`
+	Object    string `json:"object"`
+	VersionID string `json:"versionId,omitempty"`
+	ETag      string `json:"etag"`
+	Size      int64  `json:"size"`
+	PartSize  int64  `json:"partSize"`
+
+	// Completed holds the indexes of ranges already written to the partial file
+	Completed map[int]bool `json:"completed"`
+}
+
+// matches reports whether the state belongs to the same object version and layout
+func (d *downloadState) matches(bucketName, objectName, versionID string, info types.ObjectInfo, partSize int64) bool {
+	return d.Bucket == bucketName &&
+		d.Object == objectName &&
+		d.VersionID == versionID &&
+		d.ETag != "" &&
+		d.ETag == info.ETag &&
+		d.Size == info.Size &&
+		d.PartSize == partSize
+}
+
+// loadDownloadState reads a sidecar state file; a missing or corrupt file yields nil
+func loadDownloadState(path string) *downloadState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var state downloadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+	if state.Completed == nil {
+		state.Completed = make(map[int]bool)
+	}
+	return &state
+}
+
+// fgetParallel downloads an object as concurrent ranged GETs written into a
+// partial file with WriteAt. Every range is pinned to the ETag seen by Stat.
+// Progress is kept in a sidecar state file so an interrupted download resumes
+// with only the missing ranges; the partial file is renamed on success.
+func (s *objectService) fgetParallel(ctx context.Context, bucketName, objectName, filePath string, options GetOptions) (types.ObjectInfo, error) {
+	partSize := options.PartSize
+	if partSize <= 0 {
+		partSize = defaultDownloadPartSize
+	}
+
+	info, err := s.statForGet(ctx, bucketName, objectName, options)
+	if err != nil {
+		return types.ObjectInfo{}, err
+	}
+
+	statePath := filePath + downloadStateSuffix
+	partialPath := filePath + downloadPartialSuffix
+
+	state := loadDownloadState(statePath)
+	if state == nil || !state.matches(bucketName, objectName, options.VersionID, info, partSize) {
+		state = nil
+	} else if stat, err := os.Stat(partialPath); err != nil || stat.Size() != info.Size {
+		state = nil
+	}
+
+	flags := os.O_RDWR
+	if state == nil {
+		flags |= os.O_CREATE | os.O_TRUNC
+		state = &downloadState{
+			Bucket:    bucketName,
+			Object:    objectName,
+			VersionID: options.VersionID,
+			ETag:      info.ETag,
+			Size:      info.Size,
+			PartSize:  partSize,
+			Completed: make(map[int]bool),
+		}
+	}
+
+	file, err := os.OpenFile(partialPath, flags, 0o644)
+	if err != nil {
+		return types.ObjectInfo{}, err
+	}
+	if err := file.Truncate(info.Size); err != nil {
+		_ = file.Close()
+		return types.ObjectInfo{}, err
+	}
+	if err := saveJSONAtomic(statePath, state); err != nil {
+		_ = file.Close()
+		return types.ObjectInfo{}, fmt.Errorf("failed to write download state: %w", err)
+	}
+
+	err = s.downloadMissingRanges(ctx, bucketName, objectName, file, state, statePath, options)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return types.ObjectInfo{}, err
+	}
+
+	if err := os.Rename(partialPath, filePath); err != nil {
+		return types.ObjectInfo{}, err
+	}
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return info, err
+	}
+	return info, nil
+}
+
+// statForGet stats an object with the version and SSE headers of a download
+func (s *objectService) statForGet(ctx context.Context, bucketName, objectName string, options GetOptions) (types.ObjectInfo, error) {
+	headers := make(http.Header)
+	if options.SSE != nil {
+		options.SSE.ApplyHeaders(headers)
+	} else if options.SSECustomerAlgorithm != "" && options.SSECustomerKey != "" {
+		headers.Set("x-amz-server-side-encryption-customer-algorithm", options.SSECustomerAlgorithm)
+		headers.Set("x-amz-server-side-encryption-customer-key", options.SSECustomerKey)
+		if options.SSECustomerKeyMD5 != "" {
+			headers.Set("x-amz-server-side-encryption-customer-key-MD5", options.SSECustomerKeyMD5)
+		}
+	}
+	for k, v := range options.CustomHeaders {
+		headers[k] = v
+	}
+	return s.stat(ctx, bucketName, objectName, func(opts *StatOptions) {
+		opts.VersionID = options.VersionID
+		opts.CustomHeaders = headers
+		opts.UseAccelerate = options.UseAccelerate
+	})
+}
+
+// downloadMissingRanges fetches ranges absent from state with NumThreads
+// workers. Each completed range is synced to disk before the state listing
+// it is persisted, so a crash cannot leave the state ahead of the file.
+func (s *objectService) downloadMissingRanges(ctx context.Context, bucketName, objectName string, file *os.File, state *downloadState, statePath string, options GetOptions) error {
+	numThreads := int(options.NumThreads)
+	if numThreads <= 0 {
+		numThreads = defaultNumThreads
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	setErr := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	// Pin every range to the version and ETag that was stat'ed
+	rangeOptions := options
+	rangeOptions.MatchETag = state.ETag
+	rangeOptions.NotMatchETag = ""
+	rangeOptions.MatchModified = time.Time{}
+	rangeOptions.NotModified = time.Time{}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < numThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				err := s.downloadRange(ctx, bucketName, objectName, file, index, state.PartSize, state.Size, rangeOptions)
+				if err == nil {
+					err = file.Sync()
+				}
+				mu.Lock()
+				if err != nil {
+					setErr(fmt.Errorf("download range %d: %w", index, err))
+				} else {
+					state.Completed[index] = true
+					if err := saveJSONAtomic(statePath, state); err != nil {
+						setErr(fmt.Errorf("failed to write download state: %w", err))
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for index := 0; int64(index)*state.PartSize < state.Size; index++ {
+		mu.Lock()
+		done := state.Completed[index]
+		mu.Unlock()
+		if done {
+			continue
+		}
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// downloadRange fetches a single byte range and writes it at its file offset
+func (s *objectService) downloadRange(ctx context.Context, bucketName, objectName string, file *os.File, index int, partSize, objectSize int64, options GetOptions) error {
+	start := int64(index) * partSize
+	end := min(start+partSize, objectSize) - 1
+
+	options.RangeStart = start
+	options.RangeEnd = end
+	options.SetRange = true
+
+	reader, _, err := s.get(ctx, bucketName, objectName, func(opts *GetOptions) {
+		*opts = options
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	written, err := io.Copy(io.NewOffsetWriter(file, start), io.LimitReader(reader, end-start+1))
+	if err != nil {
+		return err
+	}
+	if written != end-start+1 {
+		return fmt.Errorf("short read: got %d of %d bytes", written, end-start+1)
+	}
+	return nil
+}
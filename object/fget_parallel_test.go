@@ -0,0 +1,153 @@
+// Package object object/fget_parallel_test.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rangeTestServer serves a single object and honors Range and If-Match headers
+type rangeTestServer struct {
+	mu        sync.Mutex
+	data      []byte
+	etag      string
+	failStart int64
+	ranges    map[string]int
+}
+
+func (r *rangeTestServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("ETag", `"`+r.etag+`"`)
+	if req.Method == http.MethodHead {
+		w.Header().Set("Content-Length", strconv.Itoa(len(r.data)))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if match := req.Header.Get("If-Match"); match != "" && strings.Trim(match, `"`) != r.etag {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	var start, end int64
+	if _, err := fmt.Sscanf(req.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	r.ranges[req.Header.Get("Range")]++
+	r.mu.Unlock()
+	if start == r.failStart {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(r.data)))
+	w.Header().Set("Content-Length", strconv.FormatInt(end-start+1, 10))
+	w.WriteHeader(http.StatusPartialContent)
+	_, _ = w.Write(r.data[start : end+1])
+}
+
+func TestFGetParallelResume(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	handler := &rangeTestServer{data: data, etag: "etag-1", failStart: 4096, ranges: map[string]int{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	filePath := filepath.Join(t.TempDir(), "model.bin")
+	opts := []GetOption{WithGetParallel(2), WithGetPartSize(2048)}
+
+	if _, err := service.FGet(context.Background(), "test-bucket", "model.bin", filePath, opts...); err == nil {
+		t.Fatal("FGet() expected error when a range fails")
+	}
+	if _, err := os.Stat(filePath + downloadStateSuffix); err != nil {
+		t.Fatalf("state file not kept after failure: %v", err)
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("target file must not exist before the download completes")
+	}
+
+	handler.failStart = -1
+	info, err := service.FGet(context.Background(), "test-bucket", "model.bin", filePath, opts...)
+	if err != nil {
+		t.Fatalf("FGet() resume error = %v", err)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("Size = %d, want %d", info.Size, len(data))
+	}
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("downloaded content does not match")
+	}
+	if handler.ranges["bytes=0-2047"] != 1 {
+		t.Errorf("first range fetched %d times, want 1", handler.ranges["bytes=0-2047"])
+	}
+	if _, err := os.Stat(filePath + downloadStateSuffix); !os.IsNotExist(err) {
+		t.Error("state file should be removed after completion")
+	}
+	if _, err := os.Stat(filePath + downloadPartialSuffix); !os.IsNotExist(err) {
+		t.Error("partial file should be renamed after completion")
+	}
+}
+
+func TestFGetParallelRejectsRange(t *testing.T) {
+	handler := &rangeTestServer{data: []byte("0123456789"), etag: "etag-1", failStart: -1, ranges: map[string]int{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	filePath := filepath.Join(t.TempDir(), "model.bin")
+	_, err := service.FGet(context.Background(), "test-bucket", "model.bin", filePath, WithGetParallel(2), WithGetRange(2, 5))
+	if err == nil {
+		t.Fatal("FGet() with a range and WithGetParallel error = nil")
+	}
+	if len(handler.ranges) != 0 {
+		t.Errorf("server received ranges %v, want no requests", handler.ranges)
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("FGet() must not create the file")
+	}
+}
+
+func TestFGetParallelETagChanged(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 5000)
+	handler := &rangeTestServer{data: data, etag: "etag-1", failStart: 2048, ranges: map[string]int{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	filePath := filepath.Join(t.TempDir(), "model.bin")
+	opts := []GetOption{WithGetParallel(1), WithGetPartSize(2048)}
+
+	if _, err := service.FGet(context.Background(), "test-bucket", "model.bin", filePath, opts...); err == nil {
+		t.Fatal("FGet() expected error when a range fails")
+	}
+
+	// A new object version must restart the download from scratch
+	handler.data = bytes.Repeat([]byte("b"), 5000)
+	handler.etag = "etag-2"
+	handler.failStart = -1
+	if _, err := service.FGet(context.Background(), "test-bucket", "model.bin", filePath, opts...); err != nil {
+		t.Fatalf("FGet() error = %v", err)
+	}
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(got, handler.data) {
+		t.Error("download mixed content from different object versions")
+	}
+}
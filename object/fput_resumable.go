@@ -0,0 +1,299 @@
+// Package object object/fput_resumable.go
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// checkpointSuffix is appended to the source path to name the checkpoint file
+const checkpointSuffix = ".rustfs-upload.json"
+
+// uploadCheckpoint is the persisted state of a resumable FPut
+type uploadCheckpoint struct {
+	Bucket   string    `json:"bucket"`
+	Object   string    `json:"object"`
+	UploadID string    `json:"uploadId"`
+	PartSize int64     `json:"partSize"`
+	FileSize int64     `json:"fileSize"`
+	ModTime  time.Time `json:"modTime"`
+
+	// Parts holds completed parts keyed by part number
+	Parts map[int]checkpointPart `json:"parts"`
+}
+
+// checkpointPart records a single uploaded part
+type checkpointPart struct {
+	ETag     string `json:"etag"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// CheckpointPath returns the checkpoint file used by resumable FPut for filePath
+func CheckpointPath(filePath string) string {
+	return filePath + checkpointSuffix
+}
+
+// matches reports whether the checkpoint belongs to the same upload target and source file
+func (c *uploadCheckpoint) matches(bucketName, objectName string, partSize int64, stat os.FileInfo) bool {
+	return c.Bucket == bucketName &&
+		c.Object == objectName &&
+		c.UploadID != "" &&
+		c.PartSize == partSize &&
+		c.FileSize == stat.Size() &&
+		c.ModTime.Equal(stat.ModTime())
+}
+
+// loadCheckpoint reads a checkpoint file; a missing or corrupt file yields nil
+func loadCheckpoint(path string) *uploadCheckpoint {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var checkpoint uploadCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil
+	}
+	if checkpoint.Parts == nil {
+		checkpoint.Parts = make(map[int]checkpointPart)
+	}
+	return &checkpoint
+}
+
+// save writes the checkpoint atomically
+func (c *uploadCheckpoint) save(path string) error {
+	return saveJSONAtomic(path, c)
+}
+
+// saveJSONAtomic writes v as JSON to path via a temporary file and rename
+func saveJSONAtomic(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// fputResumable uploads a file as a multipart upload whose progress is
+// persisted to a checkpoint file. On failure the upload is left open so a
+// later call can reconcile with ListObjectParts and upload only missing parts.
+func (s *objectService) fputResumable(ctx context.Context, bucketName, objectName, filePath string, file io.ReaderAt, stat os.FileInfo, options PutOptions) (types.UploadInfo, error) {
+	if options.CSE != nil {
+		return types.UploadInfo{}, fmt.Errorf("resumable uploads do not support client-side encryption")
+	}
+
+	objectSize := stat.Size()
+	partSize, err := optimalPartSize(objectSize, options.PartSize)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	algorithm, err := checksum.Parse(options.ChecksumAlgorithm)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	checkpointPath := options.CheckpointFile
+	if checkpointPath == "" {
+		checkpointPath = CheckpointPath(filePath)
+	}
+
+	checkpoint := loadCheckpoint(checkpointPath)
+	if checkpoint != nil && checkpoint.matches(bucketName, objectName, partSize, stat) {
+		if err := s.reconcileCheckpoint(ctx, checkpoint, objectSize, algorithm); err != nil {
+			if !errors.IsNotFound(err) {
+				return types.UploadInfo{}, err
+			}
+			// The upload was completed or aborted elsewhere; start over
+			checkpoint = nil
+		}
+	} else {
+		checkpoint = nil
+	}
+
+	if checkpoint == nil {
+		uploadID, err := s.initiateMultipartUpload(ctx, bucketName, objectName, options)
+		if err != nil {
+			return types.UploadInfo{}, err
+		}
+		checkpoint = &uploadCheckpoint{
+			Bucket:   bucketName,
+			Object:   objectName,
+			UploadID: uploadID,
+			PartSize: partSize,
+			FileSize: objectSize,
+			ModTime:  stat.ModTime(),
+			Parts:    make(map[int]checkpointPart),
+		}
+		if err := checkpoint.save(checkpointPath); err != nil {
+			return types.UploadInfo{}, fmt.Errorf("failed to write upload checkpoint: %w", err)
+		}
+	}
+
+	if err := s.uploadMissingParts(ctx, checkpoint, checkpointPath, file, objectSize, algorithm, options); err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	parts := make([]types.ObjectPart, 0, len(checkpoint.Parts))
+	for number, part := range checkpoint.Parts {
+		objectPart := types.ObjectPart{PartNumber: number, ETag: part.ETag, Size: part.Size}
+		if field := partChecksumField(&objectPart, algorithm); field != nil {
+			*field = part.Checksum
+		}
+		parts = append(parts, objectPart)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	uploadInfo, err := s.completeMultipartUpload(ctx, bucketName, objectName, checkpoint.UploadID, parts, options)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	uploadInfo.Size = objectSize
+
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		return uploadInfo, err
+	}
+	return uploadInfo, nil
+}
+
+// reconcileCheckpoint replaces the recorded parts with those the server
+// reports for the upload, keeping only parts with the expected size and ETag
+func (s *objectService) reconcileCheckpoint(ctx context.Context, checkpoint *uploadCheckpoint, objectSize int64, algorithm types.ChecksumType) error {
+	uploaded := make(map[int]checkpointPart)
+	marker := 0
+	for {
+		result, err := s.listObjectParts(ctx, checkpoint.Bucket, checkpoint.Object, checkpoint.UploadID, WithListPartsMarker(marker))
+		if err != nil {
+			return err
+		}
+		for _, part := range result.Parts {
+			etag := trimETag(part.ETag)
+			if part.Size != expectedPartSize(part.PartNumber, checkpoint.PartSize, objectSize) {
+				continue
+			}
+			recorded, ok := checkpoint.Parts[part.PartNumber]
+			if ok && recorded.ETag != etag {
+				continue
+			}
+			uploadedPart := checkpointPart{ETag: etag, Size: part.Size, Checksum: recorded.Checksum}
+			if field := partChecksumField(&part, algorithm); field != nil && *field != "" {
+				uploadedPart.Checksum = *field
+			}
+			uploaded[part.PartNumber] = uploadedPart
+		}
+		if !result.IsTruncated || result.NextPartNumberMarker <= marker {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	checkpoint.Parts = uploaded
+	return nil
+}
+
+// expectedPartSize returns the size of part number for an object split into partSize parts
+func expectedPartSize(number int, partSize, objectSize int64) int64 {
+	offset := int64(number-1) * partSize
+	if number <= 0 || offset >= objectSize {
+		return -1
+	}
+	return min(partSize, objectSize-offset)
+}
+
+// uploadMissingParts uploads parts absent from the checkpoint with NumThreads
+// workers, persisting the checkpoint after every completed part
+func (s *objectService) uploadMissingParts(ctx context.Context, checkpoint *uploadCheckpoint, checkpointPath string, file io.ReaderAt, objectSize int64, algorithm types.ChecksumType, options PutOptions) error {
+	numThreads := int(options.NumThreads)
+	if numThreads <= 0 {
+		numThreads = defaultNumThreads
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	setErr := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	jobs := make(chan partJob)
+	var wg sync.WaitGroup
+	for i := 0; i < numThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				part, err := s.uploadPart(ctx, checkpoint.Bucket, checkpoint.Object, checkpoint.UploadID, job.number, job.reader, job.size, options)
+				mu.Lock()
+				if err != nil {
+					setErr(fmt.Errorf("upload part %d: %w", job.number, err))
+				} else {
+					uploaded := checkpointPart{ETag: part.ETag, Size: job.size}
+					if field := partChecksumField(&part, algorithm); field != nil {
+						uploaded.Checksum = *field
+					}
+					checkpoint.Parts[job.number] = uploaded
+					if err := checkpoint.save(checkpointPath); err != nil {
+						setErr(fmt.Errorf("failed to write upload checkpoint: %w", err))
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	number := 1
+	for offset := int64(0); offset < objectSize; offset += checkpoint.PartSize {
+		size := min(checkpoint.PartSize, objectSize-offset)
+		mu.Lock()
+		_, done := checkpoint.Parts[number]
+		mu.Unlock()
+		if !done {
+			select {
+			case jobs <- partJob{number: number, reader: io.NewSectionReader(file, offset, size), size: size}:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		number++
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
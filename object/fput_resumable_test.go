@@ -0,0 +1,88 @@
+// Package object object/fput_resumable_test.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFPutResumable(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}, uploads: map[int]int{}, failPart: 2}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("resumable-data.."), (11<<20)/16)
+	filePath := filepath.Join(t.TempDir(), "image.bin")
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+
+	service := createAdvancedTestService(t, server)
+	opts := []PutOption{WithResumable(), WithPartSize(absMinPartSize), WithNumThreads(1)}
+
+	if _, err := service.FPut(context.Background(), "test-bucket", "large.bin", filePath, opts...); err == nil {
+		t.Fatal("FPut() expected error when a part fails")
+	}
+	if handler.aborted {
+		t.Error("resumable upload must not be aborted on failure")
+	}
+
+	checkpoint := loadCheckpoint(CheckpointPath(filePath))
+	if checkpoint == nil {
+		t.Fatal("checkpoint file not written")
+	}
+	if checkpoint.UploadID != "upload-1" || checkpoint.PartSize != absMinPartSize {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+	if _, ok := checkpoint.Parts[1]; !ok {
+		t.Error("checkpoint missing completed part 1")
+	}
+
+	handler.failPart = 0
+	info, err := service.FPut(context.Background(), "test-bucket", "large.bin", filePath, opts...)
+	if err != nil {
+		t.Fatalf("FPut() resume error = %v", err)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("Size = %d, want %d", info.Size, len(data))
+	}
+	if handler.uploads[1] != 1 {
+		t.Errorf("part 1 uploaded %d times, want 1", handler.uploads[1])
+	}
+	if len(handler.completed) != 3 {
+		t.Fatalf("completed %d parts, want 3", len(handler.completed))
+	}
+
+	var joined []byte
+	for i := 1; i <= 3; i++ {
+		joined = append(joined, handler.parts[i]...)
+	}
+	if !bytes.Equal(joined, data) {
+		t.Error("reassembled parts do not match source data")
+	}
+	if _, err := os.Stat(CheckpointPath(filePath)); !os.IsNotExist(err) {
+		t.Error("checkpoint file should be removed after completion")
+	}
+}
+
+func TestExpectedPartSize(t *testing.T) {
+	tests := []struct {
+		number int
+		want   int64
+	}{
+		{number: 1, want: 5},
+		{number: 2, want: 5},
+		{number: 3, want: 1},
+		{number: 4, want: -1},
+		{number: 0, want: -1},
+	}
+	for _, tt := range tests {
+		if got := expectedPartSize(tt.number, 5, 11); got != tt.want {
+			t.Errorf("expectedPartSize(%d) = %d, want %d", tt.number, got, tt.want)
+		}
+	}
+}
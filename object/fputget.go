@@ -3,6 +3,7 @@ package object
 
 import (
 	"context"
+	"errors"
 	"io"
 	"mime"
 	"os"
@@ -13,7 +14,14 @@ import (
 )
 
 // FPut uploads a local file using streaming IO and optional metadata.
+// Files larger than the part size are uploaded as parallel multipart parts
+// read directly from the file. With WithResumable, multipart progress is
+// persisted to a checkpoint file and a failed upload is left open so that a
+// later FPut of the same file uploads only the missing parts.
 func (s *objectService) FPut(ctx context.Context, bucketName, objectName, filePath string, opts ...PutOption) (info types.UploadInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.FPut", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return info, err
 	}
@@ -46,7 +54,11 @@ func (s *objectService) FPut(ctx context.Context, bucketName, objectName, filePa
 		}
 	}
 	// Re-apply options to include inferred content type
-	_ = applyPutOptions(opts)
+	options = applyPutOptions(opts)
+
+	if options.Resumable && !options.DisableMultipart && stat.Size() > multipartThreshold(options.PartSize) {
+		return s.fputResumable(ctx, bucketName, objectName, filePath, file, stat, options)
+	}
 
 	reader := io.NewSectionReader(file, 0, stat.Size())
 	info, err = s.Put(ctx, bucketName, objectName, reader, stat.Size(), opts...)
@@ -54,7 +66,12 @@ func (s *objectService) FPut(ctx context.Context, bucketName, objectName, filePa
 }
 
 // FGet downloads an object directly to a local file path.
+// With WithGetParallel the object is fetched as concurrent byte ranges and an
+// interrupted download resumes from its partial file on the next call.
 func (s *objectService) FGet(ctx context.Context, bucketName, objectName, filePath string, opts ...GetOption) (info types.ObjectInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.FGet", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return info, err
 	}
@@ -62,16 +79,11 @@ func (s *objectService) FGet(ctx context.Context, bucketName, objectName, filePa
 		return info, err
 	}
 
-	var reader io.ReadCloser
-	reader, info, err = s.Get(ctx, bucketName, objectName, opts...)
-	if err != nil {
-		return info, err
+	// Parallel downloads always fetch the whole object
+	options := applyGetOptions(opts)
+	if options.Parallel && options.CSE == nil && options.SetRange {
+		return info, errors.New("WithGetRange cannot be combined with WithGetParallel")
 	}
-	defer func() {
-		if cerr := reader.Close(); err == nil && cerr != nil {
-			err = cerr
-		}
-	}()
 
 	dir := filepath.Dir(filePath)
 	if dir == "" {
@@ -81,6 +93,22 @@ func (s *objectService) FGet(ctx context.Context, bucketName, objectName, filePa
 		return info, err
 	}
 
+	// Client-side decryption needs the whole object, so it stays single-stream
+	if options.Parallel && options.CSE == nil {
+		return s.fgetParallel(ctx, bucketName, objectName, filePath, options)
+	}
+
+	var reader io.ReadCloser
+	reader, info, err = s.get(ctx, bucketName, objectName, opts...)
+	if err != nil {
+		return info, err
+	}
+	defer func() {
+		if cerr := reader.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
 	tmpFile, err := os.CreateTemp(dir, ".fget-*")
 	if err != nil {
 		return info, err
@@ -4,6 +4,8 @@ package object
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -11,11 +13,20 @@ import (
 	"strings"
 
 	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
 	"github.com/Scorpio69t/rustfs-go/types"
 )
 
 // Get downloads an object (implementation)
-func (s *objectService) Get(ctx context.Context, bucketName, objectName string, opts ...GetOption) (io.ReadCloser, types.ObjectInfo, error) {
+func (s *objectService) Get(ctx context.Context, bucketName, objectName string, opts ...GetOption) (_ io.ReadCloser, _ types.ObjectInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Get", bucketName, objectName)
+	defer func() { call.End(err) }()
+	return s.get(ctx, bucketName, objectName, opts...)
+}
+
+// get downloads an object as part of the caller's traced call
+func (s *objectService) get(ctx context.Context, bucketName, objectName string, opts ...GetOption) (io.ReadCloser, types.ObjectInfo, error) {
 	// Validate parameters
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, types.ObjectInfo{}, err
@@ -27,6 +38,11 @@ func (s *objectService) Get(ctx context.Context, bucketName, objectName string,
 	// Apply options
 	options := applyGetOptions(opts)
 
+	// Ranged reads of encrypted objects fetch only the covering segments
+	if options.CSE != nil && options.SetRange {
+		return s.getCSERange(ctx, bucketName, objectName, options)
+	}
+
 	// Build request metadata
 	meta := core.RequestMetadata{
 		BucketName:   bucketName,
@@ -38,7 +54,7 @@ func (s *objectService) Get(ctx context.Context, bucketName, objectName string,
 	// Set Range header
 	if options.SetRange {
 		rangeHeader := "bytes=" + strconv.FormatInt(options.RangeStart, 10) + "-"
-		if options.RangeEnd > 0 {
+		if closedRange(options) {
 			rangeHeader += strconv.FormatInt(options.RangeEnd, 10)
 		}
 		meta.CustomHeader.Set("Range", rangeHeader)
@@ -84,6 +100,11 @@ func (s *objectService) Get(ctx context.Context, bucketName, objectName string,
 		}
 	}
 
+	// Request the stored checksum for verification
+	if options.ChecksumMode {
+		meta.CustomHeader.Set("x-amz-checksum-mode", "ENABLED")
+	}
+
 	// Merge custom headers
 	if options.CustomHeaders != nil {
 		for k, v := range options.CustomHeaders {
@@ -114,12 +135,33 @@ func (s *objectService) Get(ctx context.Context, bucketName, objectName string,
 		return nil, types.ObjectInfo{}, err
 	}
 
+	// Verify full-object downloads against the stored checksum
+	var body io.Reader = resp.Body
+	if options.ChecksumMode && resp.StatusCode == http.StatusOK {
+		algorithm, value := objectChecksum(objectInfo)
+		if algorithm != types.ChecksumNone && !checksum.IsComposite(value) {
+			body = newChecksumReader(resp.Body, algorithm, value, "/"+bucketName+"/"+objectName)
+		}
+	}
+
 	if options.CSE != nil {
-		cseMetadata := map[string]string{}
-		for key, value := range objectInfo.UserMetadata {
-			cseMetadata[strings.ToLower(key)] = value
+		cseMetadata := cseMetadataFromInfo(objectInfo)
+		if cse.IsStream(cseMetadata) {
+			plainSize, err := cse.PlaintextSize(cseMetadata, objectInfo.Size)
+			if err != nil {
+				closeResponse(resp)
+				return nil, types.ObjectInfo{}, err
+			}
+			plain, err := options.CSE.DecryptStream(body, cseMetadata)
+			if err != nil {
+				closeResponse(resp)
+				return nil, types.ObjectInfo{}, err
+			}
+			objectInfo.Size = plainSize
+			return readCloser{Reader: plain, Closer: resp.Body}, objectInfo, nil
 		}
-		decrypted, err := options.CSE.Decrypt(resp.Body, cseMetadata)
+
+		decrypted, err := options.CSE.Decrypt(body, cseMetadata)
 		if err != nil {
 			closeResponse(resp)
 			return nil, types.ObjectInfo{}, err
@@ -131,5 +173,85 @@ func (s *objectService) Get(ctx context.Context, bucketName, objectName string,
 
 	// Return response body and object info
 	// Note: Caller is responsible for closing Body
+	if body != resp.Body {
+		return readCloser{Reader: body, Closer: resp.Body}, objectInfo, nil
+	}
 	return resp.Body, objectInfo, nil
 }
+
+// getCSERange maps a plaintext range of a stream-encrypted object to the
+// ciphertext segments covering it and decrypts only those segments
+func (s *objectService) getCSERange(ctx context.Context, bucketName, objectName string, options GetOptions) (io.ReadCloser, types.ObjectInfo, error) {
+	info, err := s.statForGet(ctx, bucketName, objectName, options)
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+
+	cseMetadata := cseMetadataFromInfo(info)
+	if !cse.IsStream(cseMetadata) {
+		return nil, types.ObjectInfo{}, errors.New("ranged reads require stream-encrypted objects")
+	}
+	plainSize, err := cse.PlaintextSize(cseMetadata, info.Size)
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+
+	start := options.RangeStart
+	end := plainSize - 1
+	if closedRange(options) {
+		end = min(options.RangeEnd, plainSize-1)
+	}
+	if start < 0 || start > end {
+		return nil, types.ObjectInfo{}, fmt.Errorf("invalid range %d-%d for object size %d", start, end, plainSize)
+	}
+
+	encStart, encEnd, err := cse.SegmentRange(cseMetadata, info.Size, start, end-start+1)
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+
+	// Fetch the ciphertext segments pinned to the stat'ed object
+	rangeOptions := options
+	rangeOptions.CSE = nil
+	rangeOptions.RangeStart = encStart
+	rangeOptions.RangeEnd = encEnd
+	if rangeOptions.MatchETag == "" {
+		rangeOptions.MatchETag = info.ETag
+	}
+	body, objectInfo, err := s.get(ctx, bucketName, objectName, func(opts *GetOptions) {
+		*opts = rangeOptions
+	})
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+
+	plain, err := options.CSE.DecryptRange(body, cseMetadata, info.Size, start, end-start+1)
+	if err != nil {
+		_ = body.Close()
+		return nil, types.ObjectInfo{}, err
+	}
+	objectInfo.Size = end - start + 1
+	return readCloser{Reader: plain, Closer: body}, objectInfo, nil
+}
+
+// cseMetadataFromInfo returns user metadata with lower-cased keys for CSE lookups
+func cseMetadataFromInfo(info types.ObjectInfo) map[string]string {
+	metadata := make(map[string]string, len(info.UserMetadata))
+	for key, value := range info.UserMetadata {
+		metadata[strings.ToLower(key)] = value
+	}
+	return metadata
+}
+
+// readCloser pairs a transformed reader with the closer of its source
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// closedRange reports whether a range ends at RangeEnd rather than at the
+// end of the object. Only a non-positive end before a positive start is
+// open, so a one-byte range at offset 0 stays closed.
+func closedRange(options GetOptions) bool {
+	return options.RangeEnd > 0 || options.RangeEnd >= options.RangeStart
+}
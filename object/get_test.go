@@ -0,0 +1,164 @@
+// Package object object/get_test.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
+	"github.com/Scorpio69t/rustfs-go/pkg/tracing"
+)
+
+// cseTestServer stores a single object with its user metadata and serves ranges
+type cseTestServer struct {
+	mu       sync.Mutex
+	data     []byte
+	metadata http.Header
+	ranges   []string
+}
+
+func (c *cseTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		c.data, _ = io.ReadAll(r.Body)
+		c.metadata = make(http.Header)
+		for key, values := range r.Header {
+			if len(key) > len("X-Amz-Meta-") && key[:len("X-Amz-Meta-")] == "X-Amz-Meta-" {
+				c.metadata[key] = values
+			}
+		}
+		w.Header().Set("ETag", `"etag-1"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodHead, http.MethodGet:
+		for key, values := range c.metadata {
+			w.Header()[key] = values
+		}
+		w.Header().Set("ETag", `"etag-1"`)
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", strconv.Itoa(len(c.data)))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		var start, end int64
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			w.Header().Set("Content-Length", strconv.Itoa(len(c.data)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(c.data)
+			return
+		}
+		c.ranges = append(c.ranges, r.Header.Get("Range"))
+		w.Header().Set("Content-Length", strconv.FormatInt(end-start+1, 10))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(c.data[start : end+1])
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGetCSEStreamAndRange(t *testing.T) {
+	handler := &cseTestServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	cseClient, err := cse.New(key)
+	if err != nil {
+		t.Fatalf("cse.New() error = %v", err)
+	}
+
+	plain := make([]byte, 3*cse.DefaultSegmentSize+123)
+	_, _ = rand.Read(plain)
+
+	service := createAdvancedTestService(t, server)
+	if _, err := service.Put(context.Background(), "test-bucket", "backup.bin", bytes.NewReader(plain), int64(len(plain)), WithPutCSE(cseClient)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if int64(len(handler.data)) != cse.EncryptedSize(int64(len(plain))) {
+		t.Fatalf("stored %d bytes, want %d", len(handler.data), cse.EncryptedSize(int64(len(plain))))
+	}
+
+	reader, info, err := service.Get(context.Background(), "test-bucket", "backup.bin", WithGetCSE(cseClient))
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got, err := io.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil {
+		t.Fatalf("failed to read object: %v", err)
+	}
+	if !bytes.Equal(got, plain) || info.Size != int64(len(plain)) {
+		t.Errorf("full read mismatch (size %d)", info.Size)
+	}
+
+	start, end := int64(cse.DefaultSegmentSize+10), int64(2*cse.DefaultSegmentSize+20)
+	reader, info, err = service.Get(context.Background(), "test-bucket", "backup.bin", WithGetCSE(cseClient), WithGetRange(start, end))
+	if err != nil {
+		t.Fatalf("Get() with range error = %v", err)
+	}
+	got, err = io.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil {
+		t.Fatalf("failed to read range: %v", err)
+	}
+	if !bytes.Equal(got, plain[start:end+1]) || info.Size != end-start+1 {
+		t.Errorf("ranged read mismatch (size %d)", info.Size)
+	}
+
+	segment := int64(cse.DefaultSegmentSize + 16)
+	wantRange := fmt.Sprintf("bytes=%d-%d", segment, 3*segment-1)
+	if len(handler.ranges) != 1 || handler.ranges[0] != wantRange {
+		t.Errorf("ciphertext ranges = %v, want [%s]", handler.ranges, wantRange)
+	}
+}
+
+func TestGetTracing(t *testing.T) {
+	handler := &cseTestServer{data: []byte("hello world")}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	recorder := tracing.NewRecorder()
+	service := createAdvancedTestService(t, server, func(config *core.ExecutorConfig) {
+		config.Tracer = recorder
+	})
+
+	reader, _, err := service.Get(context.Background(), "test-bucket", "hello.txt")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = reader.Close()
+	if _, err := service.Stat(context.Background(), "test-bucket", "hello.txt"); err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	// Each SDK call is one span named after the method, with its attempts
+	// as children
+	spans := recorder.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("recorded %d spans, want 4", len(spans))
+	}
+	for i, want := range []struct{ call, attempt string }{{"Object.Get", http.MethodGet}, {"Object.Stat", http.MethodHead}} {
+		attempt, call := spans[2*i], spans[2*i+1]
+		if call.Name != want.call || call.Parent != nil || call.Err != nil {
+			t.Errorf("call span = %s (parent %v, error %v), want %s", call.Name, call.Parent, call.Err, want.call)
+		}
+		if call.Attributes[tracing.AttrBucket] != "test-bucket" || call.Attributes[tracing.AttrKey] != "hello.txt" {
+			t.Errorf("%s attributes = %v", call.Name, call.Attributes)
+		}
+		if attempt.Name != want.attempt || attempt.Parent != call {
+			t.Errorf("attempt span = %s with parent %v, want %s under %s", attempt.Name, attempt.Parent, want.attempt, want.call)
+		}
+	}
+}
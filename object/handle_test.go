@@ -0,0 +1,134 @@
+// Package object object/handle_test.go
+package object
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestObjectHandleZip(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		_, _ = w.Write(bytes.Repeat([]byte(name), 1000))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+
+	handler := &rangeTestServer{data: archive.Bytes(), etag: "etag-1", failStart: -1, ranges: map[string]int{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	obj, err := service.Open(context.Background(), "test-bucket", "archive.zip")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer obj.Close()
+
+	info, err := obj.Stat()
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(obj, info.Size)
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	rc, err := zr.Open("b.txt")
+	if err != nil {
+		t.Fatalf("failed to open zip member: %v", err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("failed to read zip member: %v", err)
+	}
+	if !bytes.Equal(got, bytes.Repeat([]byte("b.txt"), 1000)) {
+		t.Error("zip member content mismatch")
+	}
+}
+
+func TestObjectHandleSeekAndReadAhead(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	handler := &rangeTestServer{data: data, etag: "etag-1", failStart: -1, ranges: map[string]int{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	obj, err := service.Open(context.Background(), "test-bucket", "data.txt", WithReadAhead(16))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if pos, err := obj.Seek(-6, io.SeekEnd); err != nil || pos != 30 {
+		t.Fatalf("Seek(-6, SeekEnd) = %d, %v", pos, err)
+	}
+	tail, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(tail) != "uvwxyz" {
+		t.Errorf("tail = %q, want uvwxyz", tail)
+	}
+
+	if _, err := obj.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek(0, SeekStart) error = %v", err)
+	}
+	buf := make([]byte, 4)
+	for i := 0; i < 4; i++ {
+		if _, err := io.ReadFull(obj, buf); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+	}
+	if string(buf) != "cdef" {
+		t.Errorf("buf = %q, want cdef", buf)
+	}
+	if handler.ranges["bytes=0-15"] != 1 || len(handler.ranges) != 2 {
+		t.Errorf("unexpected ranged GETs %v", handler.ranges)
+	}
+
+	// The object changing underneath the handle must fail reads
+	handler.etag = "etag-2"
+	if _, err := obj.ReadAt(buf, 20); err == nil {
+		t.Error("ReadAt() expected precondition failure after overwrite")
+	}
+
+	if err := obj.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := obj.Read(buf); err == nil {
+		t.Error("Read() expected error after Close")
+	}
+}
+
+func TestObjectHandleReadsFirstByte(t *testing.T) {
+	handler := &rangeTestServer{data: []byte("0123456789"), etag: "etag-1", failStart: -1, ranges: map[string]int{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	obj, err := service.Open(context.Background(), "test-bucket", "data.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer obj.Close()
+
+	// A format sniffer reading one byte must not fetch the whole object
+	buf := make([]byte, 4)
+	if n, err := obj.ReadAt(buf[:1], 0); err != nil || n != 1 || buf[0] != '0' {
+		t.Fatalf("ReadAt() = %d, %v with %q, want the first byte", n, err, buf[:n])
+	}
+	if handler.ranges["bytes=0-0"] != 1 || len(handler.ranges) != 1 {
+		t.Errorf("unexpected ranged GETs %v", handler.ranges)
+	}
+}
@@ -36,15 +36,27 @@ type CommonPrefix struct {
 
 // List lists objects (implementation)
 func (s *objectService) List(ctx context.Context, bucketName string, opts ...ListOption) <-chan types.ObjectInfo {
+	return s.list(ctx, "Object.List", bucketName, opts)
+}
+
+// list streams a listing traced as the call name, which ends with the
+// listing
+func (s *objectService) list(ctx context.Context, name, bucketName string, opts []ListOption) <-chan types.ObjectInfo {
+	ctx, call := s.executor.StartCall(ctx, name, bucketName, "")
+
 	// Create object info channel
 	objectCh := make(chan types.ObjectInfo)
 
 	// Start background goroutine for listing
 	go func() {
+		// listErr is the error entry that ended the listing
+		var listErr error
+		defer func() { call.End(listErr) }()
 		defer close(objectCh)
 
 		// Validate parameters
 		if err := validateBucketName(bucketName); err != nil {
+			listErr = err
 			objectCh <- types.ObjectInfo{Err: err}
 			return
 		}
@@ -77,10 +89,12 @@ func (s *objectService) List(ctx context.Context, bucketName string, opts ...Lis
 			}
 
 			if obj.Err != nil {
+				listErr = obj.Err
 				select {
 				case objectCh <- obj:
 				case <-ctx.Done():
-					objectCh <- types.ObjectInfo{Err: ctx.Err()}
+					listErr = ctx.Err()
+					objectCh <- types.ObjectInfo{Err: listErr}
 				}
 				return false
 			}
@@ -89,16 +103,18 @@ func (s *objectService) List(ctx context.Context, bucketName string, opts ...Lis
 			case objectCh <- obj:
 				return true
 			case <-ctx.Done():
-				objectCh <- types.ObjectInfo{Err: ctx.Err()}
+				listErr = ctx.Err()
 			case <-stopCh:
-				objectCh <- types.ObjectInfo{Err: ErrListStopped}
+				listErr = ErrListStopped
 			}
+			objectCh <- types.ObjectInfo{Err: listErr}
 			return false
 		}
 
 		// Switch to version listing if requested
 		if options.WithVersions {
 			if err := s.streamObjectVersions(ctx, bucketName, &options, objectCh); err != nil {
+				listErr = err
 				objectCh <- types.ObjectInfo{Err: err}
 			}
 			return
@@ -169,7 +185,7 @@ func (s *objectService) List(ctx context.Context, bucketName string, opts ...Lis
 // ListVersions lists object versions and delete markers.
 func (s *objectService) ListVersions(ctx context.Context, bucketName string, opts ...ListOption) <-chan types.ObjectInfo {
 	opts = append(opts, WithListVersions())
-	return s.List(ctx, bucketName, opts...)
+	return s.list(ctx, "Object.ListVersions", bucketName, opts)
 }
 
 // streamObjectVersions streams object versions and delete markers using ListObjectVersions.
@@ -14,7 +14,10 @@ import (
 )
 
 // SetLegalHold sets the legal hold status for an object.
-func (s *objectService) SetLegalHold(ctx context.Context, bucketName, objectName string, hold objectlock.LegalHoldStatus, opts ...LegalHoldOption) error {
+func (s *objectService) SetLegalHold(ctx context.Context, bucketName, objectName string, hold objectlock.LegalHoldStatus, opts ...LegalHoldOption) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.SetLegalHold", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -62,7 +65,10 @@ func (s *objectService) SetLegalHold(ctx context.Context, bucketName, objectName
 }
 
 // GetLegalHold retrieves the legal hold status for an object.
-func (s *objectService) GetLegalHold(ctx context.Context, bucketName, objectName string, opts ...LegalHoldOption) (objectlock.LegalHoldStatus, error) {
+func (s *objectService) GetLegalHold(ctx context.Context, bucketName, objectName string, opts ...LegalHoldOption) (_ objectlock.LegalHoldStatus, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.GetLegalHold", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return "", err
 	}
@@ -103,7 +109,10 @@ func (s *objectService) GetLegalHold(ctx context.Context, bucketName, objectName
 }
 
 // SetRetention sets retention mode and retain-until date for an object.
-func (s *objectService) SetRetention(ctx context.Context, bucketName, objectName string, mode objectlock.RetentionMode, retainUntil time.Time, opts ...RetentionOption) error {
+func (s *objectService) SetRetention(ctx context.Context, bucketName, objectName string, mode objectlock.RetentionMode, retainUntil time.Time, opts ...RetentionOption) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.SetRetention", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -160,7 +169,10 @@ func (s *objectService) SetRetention(ctx context.Context, bucketName, objectName
 }
 
 // GetRetention retrieves retention configuration for an object.
-func (s *objectService) GetRetention(ctx context.Context, bucketName, objectName string, opts ...RetentionOption) (objectlock.RetentionMode, time.Time, error) {
+func (s *objectService) GetRetention(ctx context.Context, bucketName, objectName string, opts ...RetentionOption) (_ objectlock.RetentionMode, _ time.Time, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.GetRetention", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return "", time.Time{}, err
 	}
@@ -4,6 +4,8 @@ package object
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/sse"
 	"github.com/Scorpio69t/rustfs-go/types"
 )
 
@@ -44,10 +47,19 @@ type completeMultipartUpload struct {
 type completePart struct {
 	PartNumber int    `xml:"PartNumber"`
 	ETag       string `xml:"ETag"`
+
+	ChecksumCRC32     string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C    string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA1      string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256    string `xml:"ChecksumSHA256,omitempty"`
+	ChecksumCRC64NVME string `xml:"ChecksumCRC64NVME,omitempty"`
 }
 
 // InitiateMultipartUpload starts a multipart upload
-func (s *objectService) InitiateMultipartUpload(ctx context.Context, bucketName, objectName string, opts ...PutOption) (string, error) {
+func (s *objectService) InitiateMultipartUpload(ctx context.Context, bucketName, objectName string, opts ...PutOption) (_ string, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.InitiateMultipartUpload", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	// Validate inputs
 	if err := validateBucketName(bucketName); err != nil {
 		return "", err
@@ -56,21 +68,21 @@ func (s *objectService) InitiateMultipartUpload(ctx context.Context, bucketName,
 		return "", err
 	}
 
-	// Apply options
-	options := applyPutOptions(opts)
-	if !options.contentTypeSet && options.ContentType == "" {
-		options.ContentType = "application/octet-stream"
-	}
+	return s.initiateMultipartUpload(ctx, bucketName, objectName, applyPutOptions(opts))
+}
+
+// initiateMultipartUpload starts a multipart upload using resolved options
+func (s *objectService) initiateMultipartUpload(ctx context.Context, bucketName, objectName string, options PutOptions) (string, error) {
 	if !options.contentTypeSet && options.ContentType == "" {
 		options.ContentType = "application/octet-stream"
 	}
 
 	// Build request metadata
 	meta := core.RequestMetadata{
-		BucketName:   bucketName,
-		ObjectName:   objectName,
-		QueryValues:  url.Values{},
-		CustomHeader: make(http.Header),
+		BucketName:    bucketName,
+		ObjectName:    objectName,
+		QueryValues:   url.Values{},
+		CustomHeader:  make(http.Header),
 		UseAccelerate: options.UseAccelerate,
 	}
 
@@ -92,6 +104,21 @@ func (s *objectService) InitiateMultipartUpload(ctx context.Context, bucketName,
 		meta.CustomHeader.Set("Content-Disposition", options.ContentDisposition)
 	}
 
+	// Set Content-Language
+	if options.ContentLanguage != "" {
+		meta.CustomHeader.Set("Content-Language", options.ContentLanguage)
+	}
+
+	// Set Cache-Control
+	if options.CacheControl != "" {
+		meta.CustomHeader.Set("Cache-Control", options.CacheControl)
+	}
+
+	// Set Expires
+	if !options.Expires.IsZero() {
+		meta.CustomHeader.Set("Expires", options.Expires.Format(http.TimeFormat))
+	}
+
 	// Set storage class
 	if options.StorageClass != "" {
 		meta.CustomHeader.Set("x-amz-storage-class", options.StorageClass)
@@ -130,11 +157,13 @@ func (s *objectService) InitiateMultipartUpload(ctx context.Context, bucketName,
 		}
 	}
 
-	// Set SSE-C headers if provided (must match initiation)
-	applySSECustomerHeaders(&meta, options.SSECustomerAlgorithm, options.SSECustomerKey, options.SSECustomerKeyMD5)
-
-	// Set SSE-C headers if provided
-	applySSECustomerHeaders(&meta, options.SSECustomerAlgorithm, options.SSECustomerKey, options.SSECustomerKeyMD5)
+	// Apply server-side encryption headers
+	if options.SSE != nil {
+		options.SSE.ApplyHeaders(meta.CustomHeader)
+	} else {
+		// Fallback to legacy SSE-C headers if SSE field not set
+		applySSECustomerHeaders(&meta, options.SSECustomerAlgorithm, options.SSECustomerKey, options.SSECustomerKeyMD5)
+	}
 
 	// Create POST request
 	req := core.NewRequest(ctx, http.MethodPost, meta)
@@ -162,7 +191,10 @@ func (s *objectService) InitiateMultipartUpload(ctx context.Context, bucketName,
 }
 
 // UploadPart uploads a single part
-func (s *objectService) UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, partSize int64, opts ...PutOption) (types.ObjectPart, error) {
+func (s *objectService) UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, partSize int64, opts ...PutOption) (_ types.ObjectPart, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.UploadPart", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	// Validate inputs
 	if err := validateBucketName(bucketName); err != nil {
 		return types.ObjectPart{}, err
@@ -180,9 +212,11 @@ func (s *objectService) UploadPart(ctx context.Context, bucketName, objectName,
 		return types.ObjectPart{}, fmt.Errorf("reader cannot be nil")
 	}
 
-	// Apply options
-	options := applyPutOptions(opts)
+	return s.uploadPart(ctx, bucketName, objectName, uploadID, partNumber, reader, partSize, applyPutOptions(opts))
+}
 
+// uploadPart uploads a single part using resolved options
+func (s *objectService) uploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, partSize int64, options PutOptions) (types.ObjectPart, error) {
 	// Build request metadata
 	meta := core.RequestMetadata{
 		BucketName:    bucketName,
@@ -198,8 +232,27 @@ func (s *objectService) UploadPart(ctx context.Context, bucketName, objectName,
 	meta.QueryValues.Set("uploadId", uploadID)
 	meta.QueryValues.Set("partNumber", strconv.Itoa(partNumber))
 
+	// Calculate Content-MD5 when the part can be replayed
+	if options.SendContentMD5 && partSize > 0 {
+		if seeker, ok := reader.(io.ReadSeeker); ok {
+			md5Hash := md5.New()
+			if _, err := io.Copy(md5Hash, seeker); err != nil {
+				return types.ObjectPart{}, err
+			}
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return types.ObjectPart{}, err
+			}
+			meta.CustomHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Hash.Sum(nil)))
+		}
+	}
+
 	// Set checksum headers
 	applyChecksumHeaders(&meta, options)
+	applyStreamingOptions(&meta, options)
+	algorithm, checksumValue, err := applyChecksumValue(&meta, reader, partSize, options)
+	if err != nil {
+		return types.ObjectPart{}, err
+	}
 
 	// Merge custom headers
 	if options.CustomHeaders != nil {
@@ -209,7 +262,11 @@ func (s *objectService) UploadPart(ctx context.Context, bucketName, objectName,
 	}
 
 	// Set SSE-C headers if provided (required for SSE-C multipart uploads)
-	applySSECustomerHeaders(&meta, options.SSECustomerAlgorithm, options.SSECustomerKey, options.SSECustomerKeyMD5)
+	if ssec, ok := options.SSE.(*sse.C); ok {
+		ssec.ApplyHeaders(meta.CustomHeader)
+	} else {
+		applySSECustomerHeaders(&meta, options.SSECustomerAlgorithm, options.SSECustomerKey, options.SSECustomerKeyMD5)
+	}
 
 	// Create PUT request
 	req := core.NewRequest(ctx, http.MethodPut, meta)
@@ -246,12 +303,21 @@ func (s *objectService) UploadPart(ctx context.Context, bucketName, objectName,
 	if checksumSHA256 := resp.Header.Get("x-amz-checksum-sha256"); checksumSHA256 != "" {
 		part.ChecksumSHA256 = checksumSHA256
 	}
+	if checksumCRC64NVME := resp.Header.Get("x-amz-checksum-crc64nvme"); checksumCRC64NVME != "" {
+		part.ChecksumCRC64NVME = checksumCRC64NVME
+	}
+	if field := partChecksumField(&part, algorithm); field != nil && *field == "" {
+		*field = checksumValue
+	}
 
 	return part, nil
 }
 
 // CompleteMultipartUpload finalizes a multipart upload
-func (s *objectService) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []types.ObjectPart, opts ...PutOption) (types.UploadInfo, error) {
+func (s *objectService) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []types.ObjectPart, opts ...PutOption) (_ types.UploadInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.CompleteMultipartUpload", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	// Validate inputs
 	if err := validateBucketName(bucketName); err != nil {
 		return types.UploadInfo{}, err
@@ -266,18 +332,35 @@ func (s *objectService) CompleteMultipartUpload(ctx context.Context, bucketName,
 		return types.UploadInfo{}, fmt.Errorf("parts cannot be empty")
 	}
 
-	// Apply options
-	options := applyPutOptions(opts)
+	return s.completeMultipartUpload(ctx, bucketName, objectName, uploadID, parts, applyPutOptions(opts))
+}
 
+// completeMultipartUpload finalizes a multipart upload using resolved options
+func (s *objectService) completeMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []types.ObjectPart, options PutOptions) (types.UploadInfo, error) {
 	// Build completion payload
 	completeParts := make([]completePart, len(parts))
 	for i, part := range parts {
 		completeParts[i] = completePart{
-			PartNumber: part.PartNumber,
-			ETag:       part.ETag,
+			PartNumber:        part.PartNumber,
+			ETag:              part.ETag,
+			ChecksumCRC32:     part.ChecksumCRC32,
+			ChecksumCRC32C:    part.ChecksumCRC32C,
+			ChecksumSHA1:      part.ChecksumSHA1,
+			ChecksumSHA256:    part.ChecksumSHA256,
+			ChecksumCRC64NVME: part.ChecksumCRC64NVME,
 		}
 	}
 
+	// Compute the composite checksum of the parts to verify the result
+	algorithm, err := partsChecksumAlgorithm(parts, options)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	composite, err := compositeChecksum(parts, algorithm)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
 	completeUpload := completeMultipartUpload{
 		Parts: completeParts,
 	}
@@ -365,12 +448,23 @@ func (s *objectService) CompleteMultipartUpload(ctx context.Context, bucketName,
 	if checksumSHA256 := resp.Header.Get("x-amz-checksum-sha256"); checksumSHA256 != "" {
 		uploadInfo.ChecksumSHA256 = checksumSHA256
 	}
+	if checksumCRC64NVME := resp.Header.Get("x-amz-checksum-crc64nvme"); checksumCRC64NVME != "" {
+		uploadInfo.ChecksumCRC64NVME = checksumCRC64NVME
+	}
+
+	// Verify a composite checksum reported by the server, or report ours
+	if err := applyCompositeChecksum(&uploadInfo, algorithm, composite, "/"+bucketName+"/"+objectName); err != nil {
+		return uploadInfo, err
+	}
 
 	return uploadInfo, nil
 }
 
 // AbortMultipartUpload aborts an in-progress multipart upload
-func (s *objectService) AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error {
+func (s *objectService) AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.AbortMultipartUpload", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	// Validate inputs
 	if err := validateBucketName(bucketName); err != nil {
 		return err
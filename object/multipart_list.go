@@ -61,7 +61,10 @@ type ListPartsResult struct {
 }
 
 // ListMultipartUploads lists in-progress multipart uploads for a bucket.
-func (s *objectService) ListMultipartUploads(ctx context.Context, bucketName string, opts ...MultipartListOption) (ListMultipartUploadsResult, error) {
+func (s *objectService) ListMultipartUploads(ctx context.Context, bucketName string, opts ...MultipartListOption) (_ ListMultipartUploadsResult, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.ListMultipartUploads", bucketName, "")
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return ListMultipartUploadsResult{}, err
 	}
@@ -113,7 +116,15 @@ func (s *objectService) ListMultipartUploads(ctx context.Context, bucketName str
 }
 
 // ListObjectParts lists parts for a specific multipart upload.
-func (s *objectService) ListObjectParts(ctx context.Context, bucketName, objectName, uploadID string, opts ...ListPartsOption) (ListPartsResult, error) {
+func (s *objectService) ListObjectParts(ctx context.Context, bucketName, objectName, uploadID string, opts ...ListPartsOption) (_ ListPartsResult, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.ListObjectParts", bucketName, objectName)
+	defer func() { call.End(err) }()
+	return s.listObjectParts(ctx, bucketName, objectName, uploadID, opts...)
+}
+
+// listObjectParts lists the parts of an upload as part of the caller's
+// traced call
+func (s *objectService) listObjectParts(ctx context.Context, bucketName, objectName, uploadID string, opts ...ListPartsOption) (ListPartsResult, error) {
 	if err := validateBucketName(bucketName); err != nil {
 		return ListPartsResult{}, err
 	}
@@ -23,12 +23,17 @@ func NewService(executor *core.Executor, locationCache *cache.LocationCache) Ser
 // Put, Get, Stat, Delete, List, Copy are implemented in separate files
 // - put.go: Put method
 // - get.go: Get method
+// - handle.go: Open method and Object handle
 // - stat.go: Stat method
 // - delete.go: Delete method
+// - delete_many.go: DeleteMany method
 // - list.go: List method
 // - copy.go: Copy method
+// - rewrap.go: Rewrap method
+// - fput_resumable.go: checkpointed multipart upload for FPut
+// - fget_parallel.go: parallel ranged download for FGet
 // - multipart.go: InitiateMultipartUpload, UploadPart, CompleteMultipartUpload, AbortMultipartUpload methods
-// - compose.go: Compose method
+// - compose.go: Compose, UploadPartCopy methods
 // - append.go: Append method
 // - select.go: Select method
 // - restore.go: Restore method
@@ -71,6 +76,15 @@ func applyDeleteOptions(opts []DeleteOption) DeleteOptions {
 	return options
 }
 
+// applyDeleteManyOptions applies bulk delete options
+func applyDeleteManyOptions(opts []DeleteManyOption) DeleteManyOptions {
+	options := DeleteManyOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
 // applyListOptions applies list options
 func applyListOptions(opts []ListOption) ListOptions {
 	options := ListOptions{
@@ -72,6 +72,15 @@ type PutOptions struct {
 	// Number of concurrent uploads
 	NumThreads uint
 
+	// Disable automatic multipart uploads for large or unknown-size objects
+	DisableMultipart bool
+
+	// Persist FPut multipart progress to a checkpoint file so uploads can resume
+	Resumable bool
+
+	// Checkpoint file path for resumable FPut (defaults to CheckpointPath of the source)
+	CheckpointFile string
+
 	// Use S3 Accelerate endpoint
 	UseAccelerate bool
 }
@@ -128,6 +137,21 @@ type GetOptions struct {
 	SSECustomerAlgorithm string
 	SSECustomerKey       string
 	SSECustomerKeyMD5    string
+
+	// Parallel ranged download for FGet
+	Parallel bool
+
+	// Number of concurrent range downloads
+	NumThreads uint
+
+	// Byte range size of parallel downloads
+	PartSize int64
+
+	// Minimum range fetched by Object handle reads
+	ReadAhead int64
+
+	// Request the stored checksum and verify full-object downloads against it
+	ChecksumMode bool
 }
 
 // StatOptions controls stat/metadata retrieval
@@ -154,6 +178,15 @@ type DeleteOptions struct {
 	CustomHeaders http.Header
 }
 
+// DeleteManyOptions controls bulk object deletion
+type DeleteManyOptions struct {
+	// Bypass governance-mode retention
+	GovernanceBypass bool
+
+	// Custom headers
+	CustomHeaders http.Header
+}
+
 // ListOptions controls object listing
 type ListOptions struct {
 	// Prefix filter
@@ -219,6 +252,7 @@ type CopyOptions struct {
 	ContentType        string
 	ContentEncoding    string
 	ContentDisposition string
+	ContentLanguage    string
 	CacheControl       string
 	Expires            time.Time
 
@@ -338,7 +372,10 @@ func WithChecksumMode(mode string) PutOption {
 	}
 }
 
-// WithChecksumAlgorithm sets the checksum algorithm (e.g., CRC32C).
+// WithChecksumAlgorithm sets the checksum algorithm (CRC32, CRC32C, SHA1,
+// SHA256 or CRC64NVME). The checksum is computed while uploading and sent as
+// x-amz-checksum-<algorithm>, or as a trailer for non-seekable bodies;
+// multipart uploads send per-part checksums and verify the composite.
 func WithChecksumAlgorithm(algorithm string) PutOption {
 	return func(opts *PutOptions) {
 		opts.ChecksumAlgorithm = algorithm
@@ -352,6 +389,36 @@ func WithPartSize(size uint64) PutOption {
 	}
 }
 
+// WithNumThreads sets the number of parts uploaded concurrently
+func WithNumThreads(n uint) PutOption {
+	return func(opts *PutOptions) {
+		opts.NumThreads = n
+	}
+}
+
+// WithDisableMultipart forces a single PUT request regardless of object size
+func WithDisableMultipart() PutOption {
+	return func(opts *PutOptions) {
+		opts.DisableMultipart = true
+	}
+}
+
+// WithResumable makes FPut record multipart progress in a checkpoint file next
+// to the source so an interrupted upload resumes with only the missing parts
+func WithResumable() PutOption {
+	return func(opts *PutOptions) {
+		opts.Resumable = true
+	}
+}
+
+// WithCheckpointFile enables resumable FPut using the given checkpoint file path
+func WithCheckpointFile(path string) PutOption {
+	return func(opts *PutOptions) {
+		opts.Resumable = true
+		opts.CheckpointFile = path
+	}
+}
+
 // WithSSES3 enables SSE-S3 server-side encryption for uploads
 func WithSSES3() PutOption {
 	return func(opts *PutOptions) {
@@ -403,6 +470,10 @@ func WithPutCSE(client *cse.Client) PutOption {
 }
 
 // WithGetRange sets byte range for downloads
+//
+// The range covers start through end inclusive, so WithGetRange(0, 0)
+// reads the first byte. A zero end after a positive start reads to the end
+// of the object.
 func WithGetRange(start, end int64) GetOption {
 	return func(opts *GetOptions) {
 		opts.RangeStart = start
@@ -411,6 +482,43 @@ func WithGetRange(start, end int64) GetOption {
 	}
 }
 
+// WithGetChecksumMode requests the object's stored checksum
+// (x-amz-checksum-mode: ENABLED) and verifies the downloaded bytes against
+// it. Reading a full object whose data does not match fails with
+// *errors.ChecksumMismatchError at EOF; ranged reads and multipart composite
+// checksums are not verified.
+func WithGetChecksumMode() GetOption {
+	return func(opts *GetOptions) {
+		opts.ChecksumMode = true
+	}
+}
+
+// WithGetParallel makes FGet download concurrent byte ranges pinned to the
+// object's ETag and resume interrupted downloads from a sidecar state file.
+// A numThreads of 0 uses the default concurrency. Parallel downloads fetch
+// the whole object, so FGet returns an error when WithGetRange is also set.
+func WithGetParallel(numThreads uint) GetOption {
+	return func(opts *GetOptions) {
+		opts.Parallel = true
+		opts.NumThreads = numThreads
+	}
+}
+
+// WithGetPartSize sets the byte range size of parallel FGet downloads
+func WithGetPartSize(size int64) GetOption {
+	return func(opts *GetOptions) {
+		opts.PartSize = size
+	}
+}
+
+// WithReadAhead makes Object handle reads fetch at least size bytes per
+// ranged GET and serve following reads from the buffered data
+func WithReadAhead(size int64) GetOption {
+	return func(opts *GetOptions) {
+		opts.ReadAhead = size
+	}
+}
+
 // WithGetSSECustomer sets SSE-C parameters for downloads (key must be base64 encoded)
 func WithGetSSECustomer(keyB64, keyMD5 string) GetOption {
 	return func(opts *GetOptions) {
@@ -479,6 +587,13 @@ func WithVersionID(versionID string) interface{} {
 	}
 }
 
+// WithDeleteManyGovernanceBypass bypasses governance retention for bulk deletes
+func WithDeleteManyGovernanceBypass() DeleteManyOption {
+	return func(opts *DeleteManyOptions) {
+		opts.GovernanceBypass = true
+	}
+}
+
 // WithListPrefix sets listing prefix
 func WithListPrefix(prefix string) ListOption {
 	return func(opts *ListOptions) {
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"net/url"
 	"strings"
-	"time"
 
 	"github.com/Scorpio69t/rustfs-go/pkg/policy"
 	"github.com/Scorpio69t/rustfs-go/pkg/signer"
@@ -57,7 +56,7 @@ func (s *objectService) PresignedPostPolicy(ctx context.Context, p *policy.PostP
 		return nil, nil, errors.New("presigned operations are not supported for anonymous credentials")
 	}
 
-	t := time.Now().UTC()
+	t := s.executor.Now().UTC()
 
 	if credValues.SignerType.IsV2() {
 		policyBase64, err := p.Base64()
@@ -2,7 +2,6 @@
 package object
 
 import (
-	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/base64"
@@ -12,11 +11,18 @@ import (
 	"strconv"
 
 	"github.com/Scorpio69t/rustfs-go/internal/core"
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
 	"github.com/Scorpio69t/rustfs-go/types"
 )
 
-// Put uploads an object (implementation - simple upload, not using multipart)
-func (s *objectService) Put(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts ...PutOption) (types.UploadInfo, error) {
+// Put uploads an object (implementation)
+//
+// Objects larger than the part size, or of unknown size (objectSize = -1),
+// are uploaded in parallel parts using the multipart API.
+func (s *objectService) Put(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts ...PutOption) (info types.UploadInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Put", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	// Validate parameters
 	if err := validateBucketName(bucketName); err != nil {
 		return types.UploadInfo{}, err
@@ -35,7 +41,7 @@ func (s *objectService) Put(ctx context.Context, bucketName, objectName string,
 	}
 
 	if options.CSE != nil {
-		ciphertext, metadata, err := options.CSE.Encrypt(reader)
+		ciphertext, metadata, err := options.CSE.EncryptStream(reader)
 		if err != nil {
 			return types.UploadInfo{}, err
 		}
@@ -48,10 +54,20 @@ func (s *objectService) Put(ctx context.Context, bucketName, objectName string,
 			}
 			options.UserMetadata[key] = value
 		}
-		reader = bytes.NewReader(ciphertext)
-		objectSize = int64(len(ciphertext))
+		reader = ciphertext
+		objectSize = cse.EncryptedSize(objectSize)
+	}
+
+	// Switch to multipart for large or unknown-size streams
+	if !options.DisableMultipart && (objectSize < 0 || objectSize > multipartThreshold(options.PartSize)) {
+		return s.putMultipart(ctx, bucketName, objectName, reader, objectSize, options)
 	}
 
+	return s.putObject(ctx, bucketName, objectName, reader, objectSize, options)
+}
+
+// putObject uploads an object with a single PUT request
+func (s *objectService) putObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, options PutOptions) (types.UploadInfo, error) {
 	// Build request metadata
 	meta := core.RequestMetadata{
 		BucketName:    bucketName,
@@ -134,6 +150,11 @@ func (s *objectService) Put(ctx context.Context, bucketName, objectName string,
 
 	// Set checksum headers
 	applyChecksumHeaders(&meta, options)
+	applyStreamingOptions(&meta, options)
+	algorithm, checksumValue, err := applyChecksumValue(&meta, reader, objectSize, options)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
 
 	// Merge custom headers
 	if options.CustomHeaders != nil {
@@ -181,5 +202,10 @@ func (s *objectService) Put(ctx context.Context, bucketName, objectName string,
 		}
 	}
 
+	// Report the checksum computed locally when the server does not echo it
+	if field := uploadChecksumField(&uploadInfo, algorithm); field != nil && *field == "" {
+		*field = checksumValue
+	}
+
 	return uploadInfo, nil
 }
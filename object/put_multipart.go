@@ -0,0 +1,293 @@
+// Package object object/put_multipart.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const (
+	// absMinPartSize is the smallest part size accepted for all but the last part
+	absMinPartSize = 5 * 1024 * 1024
+	// minPartSize is the default part size for multipart uploads
+	minPartSize = 16 * 1024 * 1024
+	// maxPartSize is the largest part size accepted by the server
+	maxPartSize = 5 * 1024 * 1024 * 1024
+	// maxPartsCount is the maximum number of parts in one upload
+	maxPartsCount = 10000
+	// maxMultipartPutObjectSize is the largest object a multipart upload can produce
+	maxMultipartPutObjectSize = 5 * 1024 * 1024 * 1024 * 1024
+	// defaultNumThreads is the default number of concurrent part uploads
+	defaultNumThreads = 4
+	// abortTimeout bounds cleanup of failed multipart uploads
+	abortTimeout = time.Minute
+)
+
+// partJob describes a single part handed to an upload worker
+type partJob struct {
+	number int
+	reader io.ReadSeeker
+	size   int64
+
+	// buf is the pooled buffer backing reader (nil for io.ReaderAt sources)
+	buf []byte
+}
+
+// multipartThreshold returns the object size above which Put switches to multipart
+func multipartThreshold(partSize uint64) int64 {
+	if partSize > 0 && partSize <= maxPartSize {
+		return int64(partSize)
+	}
+	return minPartSize
+}
+
+// optimalPartSize returns the part size to use for an object of objectSize bytes.
+// An objectSize of -1 denotes a stream of unknown length.
+func optimalPartSize(objectSize int64, configured uint64) (int64, error) {
+	if objectSize > maxMultipartPutObjectSize {
+		return 0, fmt.Errorf("object size %d exceeds max %d", objectSize, int64(maxMultipartPutObjectSize))
+	}
+
+	if configured > 0 {
+		if configured < absMinPartSize || configured > maxPartSize {
+			return 0, fmt.Errorf("part size must be between %d and %d bytes", absMinPartSize, int64(maxPartSize))
+		}
+		partSize := int64(configured)
+		if objectSize > 0 && (objectSize+partSize-1)/partSize > maxPartsCount {
+			return 0, fmt.Errorf("part size %d is too small for object size %d", partSize, objectSize)
+		}
+		return partSize, nil
+	}
+
+	partSize := int64(minPartSize)
+	if objectSize > partSize*maxPartsCount {
+		// Round up to the next MiB so the object fits in maxPartsCount parts
+		partSize = (objectSize + maxPartsCount - 1) / maxPartsCount
+		partSize = (partSize + 1<<20 - 1) &^ (1<<20 - 1)
+	}
+	return partSize, nil
+}
+
+// putMultipart uploads an object in parallel parts and aborts the upload on failure
+func (s *objectService) putMultipart(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, options PutOptions) (types.UploadInfo, error) {
+	partSize, err := optimalPartSize(objectSize, options.PartSize)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	// Read the first part of unknown-size streams up front so that small
+	// payloads still go out as a single PUT
+	var first []byte
+	if objectSize < 0 {
+		first = make([]byte, partSize)
+		n, err := io.ReadFull(reader, first)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return types.UploadInfo{}, err
+		}
+		if int64(n) < partSize {
+			return s.putObject(ctx, bucketName, objectName, bytes.NewReader(first[:n]), int64(n), options)
+		}
+	}
+
+	uploadID, err := s.initiateMultipartUpload(ctx, bucketName, objectName, options)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	parts, err := s.uploadParts(ctx, bucketName, objectName, uploadID, reader, objectSize, partSize, first, options)
+	if err == nil {
+		var uploadInfo types.UploadInfo
+		uploadInfo, err = s.completeMultipartUpload(ctx, bucketName, objectName, uploadID, parts, options)
+		if err == nil {
+			return uploadInfo, nil
+		}
+	}
+
+	// Abort with a detached context so caller cancellation does not leak the upload
+	abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abortTimeout)
+	defer cancel()
+	_ = s.AbortMultipartUpload(abortCtx, bucketName, objectName, uploadID)
+
+	return types.UploadInfo{}, err
+}
+
+// uploadParts reads the source into parts and uploads them with NumThreads workers
+func (s *objectService) uploadParts(ctx context.Context, bucketName, objectName, uploadID string, reader io.Reader, objectSize, partSize int64, first []byte, options PutOptions) ([]types.ObjectPart, error) {
+	numThreads := int(options.NumThreads)
+	if numThreads <= 0 {
+		numThreads = defaultNumThreads
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		parts    []types.ObjectPart
+		firstErr error
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	// Buffer pool bounds memory to numThreads parts for non-seekable sources;
+	// buffers are allocated lazily on first use
+	buffers := make(chan []byte, numThreads)
+	for i := 0; i < numThreads; i++ {
+		buffers <- nil
+	}
+
+	jobs := make(chan partJob)
+	var wg sync.WaitGroup
+	for i := 0; i < numThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				part, err := s.uploadPart(ctx, bucketName, objectName, uploadID, job.number, job.reader, job.size, options)
+				if job.buf != nil {
+					buffers <- job.buf
+				}
+				if err != nil {
+					setErr(fmt.Errorf("upload part %d: %w", job.number, err))
+					continue
+				}
+				mu.Lock()
+				parts = append(parts, part)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	readErr := produceParts(ctx, jobs, buffers, reader, objectSize, partSize, first)
+	close(jobs)
+	wg.Wait()
+
+	if readErr != nil {
+		setErr(readErr)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+// readerAtSource returns reader as an io.ReaderAt and its current offset.
+// Only sources that are also io.Seeker report their position, so others are
+// not read in place.
+func readerAtSource(reader io.Reader) (io.ReaderAt, int64, bool) {
+	readerAt, ok := reader.(io.ReaderAt)
+	if !ok {
+		return nil, 0, false
+	}
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return nil, 0, false
+	}
+	base, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0, false
+	}
+	return readerAt, base, true
+}
+
+// produceParts splits the source into part jobs until it is exhausted
+func produceParts(ctx context.Context, jobs chan<- partJob, buffers chan []byte, reader io.Reader, objectSize, partSize int64, first []byte) error {
+	send := func(job partJob) error {
+		select {
+		case jobs <- job:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Seekable sources with a known size are read in place without buffering,
+	// starting at their current position as a single PUT would
+	if readerAt, base, ok := readerAtSource(reader); ok && objectSize >= 0 {
+		number := 1
+		for offset := int64(0); offset < objectSize; offset += partSize {
+			size := min(partSize, objectSize-offset)
+			if err := send(partJob{number: number, reader: io.NewSectionReader(readerAt, base+offset, size), size: size}); err != nil {
+				return err
+			}
+			number++
+		}
+		return nil
+	}
+
+	var uploaded int64
+	for number := 1; ; number++ {
+		var (
+			buf []byte
+			n   int
+			eof bool
+		)
+
+		if first != nil {
+			// The first part was read ahead; it takes one slot of the pool
+			<-buffers
+			buf, n = first, len(first)
+			first = nil
+		} else {
+			select {
+			case buf = <-buffers:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if buf == nil {
+				buf = make([]byte, partSize)
+			}
+
+			want := partSize
+			if objectSize >= 0 {
+				want = min(partSize, objectSize-uploaded)
+			}
+
+			var err error
+			n, err = io.ReadFull(reader, buf[:want])
+			switch {
+			case err == io.EOF || err == io.ErrUnexpectedEOF:
+				if objectSize >= 0 {
+					return fmt.Errorf("unexpected EOF: read %d of %d bytes", uploaded+int64(n), objectSize)
+				}
+				eof = true
+			case err != nil:
+				return err
+			}
+		}
+
+		// Unknown-size stream ended exactly on a part boundary
+		if n == 0 && number > 1 {
+			buffers <- buf
+			return nil
+		}
+		if number > maxPartsCount {
+			return fmt.Errorf("object exceeds %d parts of %d bytes, increase the part size", maxPartsCount, partSize)
+		}
+
+		uploaded += int64(n)
+		if err := send(partJob{number: number, reader: bytes.NewReader(buf[:n]), size: int64(n), buf: buf}); err != nil {
+			return err
+		}
+		if eof || (objectSize >= 0 && uploaded >= objectSize) {
+			return nil
+		}
+	}
+}
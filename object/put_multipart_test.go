@@ -0,0 +1,246 @@
+// Package object object/put_multipart_test.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestOptimalPartSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectSize int64
+		configured uint64
+		want       int64
+		wantErr    bool
+	}{
+		{name: "unknown size uses default", objectSize: -1, want: minPartSize},
+		{name: "small object uses default", objectSize: 1024, want: minPartSize},
+		{name: "configured part size", objectSize: 100 << 20, configured: 8 << 20, want: 8 << 20},
+		{name: "configured part size too small", objectSize: 100 << 20, configured: 1 << 20, wantErr: true},
+		{name: "configured part size needs too many parts", objectSize: 100 << 30, configured: 5 << 20, wantErr: true},
+		{name: "large object grows part size", objectSize: 1 << 40, want: 105 << 20},
+		{name: "object too large", objectSize: maxMultipartPutObjectSize + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := optimalPartSize(tt.objectSize, tt.configured)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("optimalPartSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("optimalPartSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// multipartTestServer records multipart requests for assertions
+type multipartTestServer struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	completed []completePart
+	singlePut []byte
+	aborted   bool
+	failPart  int
+	uploads   map[int]int
+}
+
+func (m *multipartTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><Bucket>test-bucket</Bucket><Key>large.bin</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`))
+	case r.Method == http.MethodPut && query.Get("uploadId") != "":
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		body, _ := io.ReadAll(r.Body)
+		if partNumber == m.failPart {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		m.mu.Lock()
+		m.parts[partNumber] = body
+		if m.uploads != nil {
+			m.uploads[partNumber]++
+		}
+		m.mu.Unlock()
+		w.Header().Set("ETag", `"etag-`+strconv.Itoa(partNumber)+`"`)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPost && query.Get("uploadId") != "":
+		var payload completeMultipartUpload
+		if err := xml.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.mu.Lock()
+		m.completed = payload.Parts
+		m.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><Bucket>test-bucket</Bucket><Key>large.bin</Key><ETag>"final-etag"</ETag></CompleteMultipartUploadResult>`))
+	case r.Method == http.MethodGet && query.Get("uploadId") != "":
+		m.mu.Lock()
+		response := `<ListPartsResult><UploadId>upload-1</UploadId>`
+		for number, body := range m.parts {
+			response += `<Part><PartNumber>` + strconv.Itoa(number) + `</PartNumber><ETag>"etag-` + strconv.Itoa(number) + `"</ETag><Size>` + strconv.Itoa(len(body)) + `</Size></Part>`
+		}
+		response += `</ListPartsResult>`
+		m.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(response))
+	case r.Method == http.MethodDelete && query.Get("uploadId") != "":
+		m.mu.Lock()
+		m.aborted = true
+		m.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		m.mu.Lock()
+		m.singlePut = body
+		m.mu.Unlock()
+		w.Header().Set("ETag", `"single-etag"`)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestPutMultipartUnknownSize(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (11<<20)/16)
+	service := createAdvancedTestService(t, server)
+
+	// io.MultiReader hides io.ReaderAt so parts are buffered from the stream
+	reader := io.MultiReader(bytes.NewReader(data))
+	info, err := service.Put(context.Background(), "test-bucket", "large.bin", reader, -1,
+		WithPartSize(absMinPartSize), WithNumThreads(2))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if info.ETag != "final-etag" {
+		t.Errorf("ETag = %q, want final-etag", info.ETag)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("Size = %d, want %d", info.Size, len(data))
+	}
+	if len(handler.parts) != 3 {
+		t.Fatalf("uploaded %d parts, want 3", len(handler.parts))
+	}
+	var joined []byte
+	for i := 1; i <= 3; i++ {
+		joined = append(joined, handler.parts[i]...)
+	}
+	if !bytes.Equal(joined, data) {
+		t.Errorf("reassembled parts do not match source data")
+	}
+	for i, part := range handler.completed {
+		if part.PartNumber != i+1 {
+			t.Errorf("completed part %d has number %d", i, part.PartNumber)
+		}
+	}
+}
+
+func TestPutMultipartReaderAt(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte{0x42}, 12<<20)
+	service := createAdvancedTestService(t, server)
+
+	if _, err := service.Put(context.Background(), "test-bucket", "large.bin", bytes.NewReader(data), int64(len(data)),
+		WithPartSize(absMinPartSize)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if len(handler.parts) != 3 {
+		t.Fatalf("uploaded %d parts, want 3", len(handler.parts))
+	}
+	if got := len(handler.parts[3]); got != 2<<20 {
+		t.Errorf("last part size = %d, want %d", got, 2<<20)
+	}
+}
+
+func TestPutMultipartReaderAtOffset(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), (12<<20)/16)
+	service := createAdvancedTestService(t, server)
+
+	// The reader was seeked past a header; only the rest is uploaded
+	const skip = 1 << 20
+	reader := bytes.NewReader(data)
+	if _, err := reader.Seek(skip, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Put(context.Background(), "test-bucket", "large.bin", reader, int64(len(data)-skip),
+		WithPartSize(absMinPartSize)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if len(handler.parts) != 3 {
+		t.Fatalf("uploaded %d parts, want 3", len(handler.parts))
+	}
+	var joined []byte
+	for i := 1; i <= 3; i++ {
+		joined = append(joined, handler.parts[i]...)
+	}
+	if !bytes.Equal(joined, data[skip:]) {
+		t.Errorf("reassembled parts do not match the data after the reader offset")
+	}
+}
+
+func TestPutMultipartAbortOnFailure(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}, failPart: 2}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte{0x01}, 11<<20)
+	service := createAdvancedTestService(t, server)
+
+	_, err := service.Put(context.Background(), "test-bucket", "large.bin", bytes.NewReader(data), int64(len(data)),
+		WithPartSize(absMinPartSize))
+	if err == nil {
+		t.Fatal("Put() expected error when a part fails")
+	}
+	if !handler.aborted {
+		t.Error("expected multipart upload to be aborted")
+	}
+	if handler.completed != nil {
+		t.Error("upload must not be completed after a part failure")
+	}
+}
+
+func TestPutUnknownSizeSmallStream(t *testing.T) {
+	handler := &multipartTestServer{parts: map[int][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	reader := io.MultiReader(bytes.NewReader([]byte("small payload")))
+
+	info, err := service.Put(context.Background(), "test-bucket", "small.txt", reader, -1)
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if string(handler.singlePut) != "small payload" {
+		t.Errorf("single PUT body = %q, want %q", handler.singlePut, "small payload")
+	}
+	if len(handler.parts) != 0 {
+		t.Errorf("expected no multipart parts, got %d", len(handler.parts))
+	}
+	if info.Size != int64(len("small payload")) {
+		t.Errorf("Size = %d, want %d", info.Size, len("small payload"))
+	}
+}
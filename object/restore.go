@@ -13,7 +13,10 @@ import (
 )
 
 // Restore initiates a restore request for an archived object.
-func (s *objectService) Restore(ctx context.Context, bucketName, objectName, versionID string, req restore.RestoreRequest) error {
+func (s *objectService) Restore(ctx context.Context, bucketName, objectName, versionID string, req restore.RestoreRequest) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Restore", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
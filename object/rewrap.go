@@ -0,0 +1,110 @@
+// Package object object/rewrap.go
+package object
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// Rewrap rotates the wrapped data key of an envelope-encrypted object to the
+// client's current master key. The object is copied onto itself with
+// replaced metadata only, so the ciphertext is not downloaded or rewritten.
+// The copy is conditional on the ETag seen by Stat. Objects over 5 GiB are
+// copied with a multipart upload, which gives the object a new multipart
+// ETag.
+func (s *objectService) Rewrap(ctx context.Context, bucketName, objectName string, client *cse.Client) (copyInfo types.CopyInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Rewrap", bucketName, objectName)
+	defer func() { call.End(err) }()
+
+	if err := validateBucketName(bucketName); err != nil {
+		return types.CopyInfo{}, err
+	}
+	if err := validateObjectName(objectName); err != nil {
+		return types.CopyInfo{}, err
+	}
+	if client == nil {
+		return types.CopyInfo{}, errors.New("cse client must not be nil")
+	}
+
+	info, err := s.stat(ctx, bucketName, objectName)
+	if err != nil {
+		return types.CopyInfo{}, err
+	}
+
+	metadata := cseMetadataFromInfo(info)
+	if metadata[cse.MetadataKeyWrappedKey] == "" {
+		return types.CopyInfo{}, errors.New("object is not envelope encrypted")
+	}
+	rewrapped, err := client.Rewrap(metadata)
+	if err != nil {
+		return types.CopyInfo{}, err
+	}
+
+	// A single copy is limited to 5 GiB; larger objects are copied onto
+	// themselves part by part
+	if info.Size > composeMaxPartSize {
+		return s.rewrapMultipart(ctx, bucketName, objectName, info, rewrapped)
+	}
+
+	return s.Copy(ctx, bucketName, objectName, bucketName, objectName, func(opts *CopyOptions) {
+		opts.ReplaceMetadata = true
+		opts.UserMetadata = rewrapped
+		opts.MatchETag = info.ETag
+		opts.ContentType = info.ContentType
+		opts.StorageClass = info.StorageClass
+		opts.Expires = info.Expires
+		if info.Metadata != nil {
+			opts.ContentEncoding = info.Metadata.Get("Content-Encoding")
+			opts.ContentDisposition = info.Metadata.Get("Content-Disposition")
+			opts.ContentLanguage = info.Metadata.Get("Content-Language")
+			opts.CacheControl = info.Metadata.Get("Cache-Control")
+		}
+	})
+}
+
+// rewrapMultipart rewraps an object too large for a single copy with a
+// multipart copy of the object onto itself. A multipart upload does not
+// inherit the object's tags, so they are read and set again.
+func (s *objectService) rewrapMultipart(ctx context.Context, bucketName, objectName string, info types.ObjectInfo, rewrapped map[string]string) (types.CopyInfo, error) {
+	var tags map[string]string
+	if info.UserTagCount > 0 {
+		var err error
+		if tags, err = s.GetTagging(ctx, bucketName, objectName); err != nil {
+			return types.CopyInfo{}, err
+		}
+	}
+
+	dst := DestinationInfo{Bucket: bucketName, Object: objectName}
+	src := SourceInfo{Bucket: bucketName, Object: objectName, MatchETag: info.ETag}
+	uploadInfo, err := s.Compose(ctx, dst, []SourceInfo{src}, func(opts *PutOptions) {
+		opts.UserMetadata = rewrapped
+		opts.UserTags = tags
+		opts.ContentType = info.ContentType
+		opts.StorageClass = info.StorageClass
+		opts.Expires = info.Expires
+		if info.Metadata != nil {
+			opts.ContentEncoding = info.Metadata.Get("Content-Encoding")
+			opts.ContentDisposition = info.Metadata.Get("Content-Disposition")
+			opts.ContentLanguage = info.Metadata.Get("Content-Language")
+			opts.CacheControl = info.Metadata.Get("Cache-Control")
+		}
+	})
+	if err != nil {
+		return types.CopyInfo{}, err
+	}
+	return types.CopyInfo{
+		Bucket:            uploadInfo.Bucket,
+		Key:               uploadInfo.Key,
+		ETag:              uploadInfo.ETag,
+		VersionID:         uploadInfo.VersionID,
+		LastModified:      uploadInfo.LastModified,
+		ChecksumCRC32:     uploadInfo.ChecksumCRC32,
+		ChecksumCRC32C:    uploadInfo.ChecksumCRC32C,
+		ChecksumSHA1:      uploadInfo.ChecksumSHA1,
+		ChecksumSHA256:    uploadInfo.ChecksumSHA256,
+		ChecksumCRC64NVME: uploadInfo.ChecksumCRC64NVME,
+	}, nil
+}
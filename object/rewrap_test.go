@@ -0,0 +1,157 @@
+// Package object object/rewrap_test.go
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
+)
+
+// newRewrapTestKeys returns a data key wrapped with key v1 and a client
+// whose current key is v2
+func newRewrapTestKeys(t *testing.T) ([]byte, *cse.Client) {
+	t.Helper()
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+	oldWrapper, _ := cse.NewAESKeyWrapper("v1", oldKey)
+	_, wrapped, err := oldWrapper.WrapKey(bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatalf("WrapKey() error = %v", err)
+	}
+	ring, err := cse.NewKeyRing("v2", map[string][]byte{"v1": oldKey, "v2": newKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	client, _ := cse.NewEnvelope(ring)
+	return wrapped, client
+}
+
+func TestRewrap(t *testing.T) {
+	wrapped, client := newRewrapTestKeys(t)
+
+	var copyHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			w.Header().Set("Content-Length", "1024")
+			w.Header().Set("Content-Type", "application/x-tar")
+			w.Header().Set("Content-Language", "de-DE")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"etag-1"`)
+			w.Header().Set("x-amz-meta-rustfs-cse-algorithm", cse.AlgorithmAESGCMStream)
+			w.Header().Set("x-amz-meta-rustfs-cse-key-id", "v1")
+			w.Header().Set("x-amz-meta-rustfs-cse-wrapped-key", base64.StdEncoding.EncodeToString(wrapped))
+			w.Header().Set("x-amz-meta-owner", "backup-team")
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPut:
+			copyHeaders = r.Header.Clone()
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`<CopyObjectResult><ETag>"etag-2"</ETag><LastModified>2024-01-01T00:00:00Z</LastModified></CopyObjectResult>`))
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	if _, err := service.Rewrap(context.Background(), "test-bucket", "backup.tar", client); err != nil {
+		t.Fatalf("Rewrap() error = %v", err)
+	}
+
+	if copyHeaders == nil {
+		t.Fatal("no copy request sent")
+	}
+	if got := copyHeaders.Get("x-amz-copy-source"); got != "test-bucket/backup.tar" {
+		t.Errorf("x-amz-copy-source = %q", got)
+	}
+	if got := copyHeaders.Get("x-amz-metadata-directive"); got != "REPLACE" {
+		t.Errorf("x-amz-metadata-directive = %q, want REPLACE", got)
+	}
+	if got := copyHeaders.Get("x-amz-copy-source-if-match"); got != "etag-1" {
+		t.Errorf("x-amz-copy-source-if-match = %q, want etag-1", got)
+	}
+	if got := copyHeaders.Get("x-amz-meta-rustfs-cse-key-id"); got != "v2" {
+		t.Errorf("rewrapped key ID = %q, want v2", got)
+	}
+	if got := copyHeaders.Get("x-amz-meta-owner"); got != "backup-team" {
+		t.Errorf("user metadata not preserved, owner = %q", got)
+	}
+	if got := copyHeaders.Get("Content-Type"); got != "application/x-tar" {
+		t.Errorf("Content-Type = %q, want application/x-tar", got)
+	}
+	if got := copyHeaders.Get("Content-Language"); got != "de-DE" {
+		t.Errorf("Content-Language = %q, want de-DE", got)
+	}
+	if got := copyHeaders.Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+}
+
+func TestRewrapLargeObject(t *testing.T) {
+	wrapped, client := newRewrapTestKeys(t)
+
+	var initiateHeaders http.Header
+	partCopies := 0
+	completed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodHead:
+			w.Header().Set("Content-Length", strconv.FormatInt(6<<30, 10))
+			w.Header().Set("Content-Type", "application/x-tar")
+			w.Header().Set("ETag", `"etag-1"`)
+			w.Header().Set("x-amz-tagging-count", "1")
+			w.Header().Set("x-amz-meta-rustfs-cse-algorithm", cse.AlgorithmAESGCMStream)
+			w.Header().Set("x-amz-meta-rustfs-cse-key-id", "v1")
+			w.Header().Set("x-amz-meta-rustfs-cse-wrapped-key", base64.StdEncoding.EncodeToString(wrapped))
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && query.Has("tagging"):
+			_, _ = w.Write([]byte(`<Tagging><TagSet><Tag><Key>team</Key><Value>backup</Value></Tag></TagSet></Tagging>`))
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			initiateHeaders = r.Header.Clone()
+			_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == http.MethodPut && query.Get("uploadId") == "upload-1":
+			if got := r.Header.Get("x-amz-copy-source-if-match"); got != "etag-1" {
+				t.Errorf("part copy x-amz-copy-source-if-match = %q, want etag-1", got)
+			}
+			partCopies++
+			_, _ = w.Write([]byte(`<CopyPartResult><ETag>"etag-part"</ETag></CopyPartResult>`))
+		case r.Method == http.MethodPost && query.Get("uploadId") == "upload-1":
+			completed = true
+			_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><ETag>"etag-2-12"</ETag></CompleteMultipartUploadResult>`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	service := createAdvancedTestService(t, server)
+	info, err := service.Rewrap(context.Background(), "test-bucket", "backup.tar", client)
+	if err != nil {
+		t.Fatalf("Rewrap() error = %v", err)
+	}
+
+	if initiateHeaders == nil || partCopies < 2 || !completed {
+		t.Fatalf("multipart copy not run: initiated %v, %d part copies, completed %v", initiateHeaders != nil, partCopies, completed)
+	}
+	if got := initiateHeaders.Get("x-amz-meta-rustfs-cse-key-id"); got != "v2" {
+		t.Errorf("rewrapped key ID = %q, want v2", got)
+	}
+	if got := initiateHeaders.Get("Content-Type"); got != "application/x-tar" {
+		t.Errorf("Content-Type = %q, want application/x-tar", got)
+	}
+	if got := initiateHeaders.Get("x-amz-tagging"); got != "team=backup" {
+		t.Errorf("x-amz-tagging = %q, want team=backup", got)
+	}
+	if info.ETag != "etag-2-12" {
+		t.Errorf("ETag = %q, want etag-2-12", info.ETag)
+	}
+}
@@ -13,7 +13,10 @@ import (
 )
 
 // Select queries object content using S3 Select.
-func (s *objectService) Select(ctx context.Context, bucketName, objectName string, opts s3select.Options) (*s3select.Results, error) {
+func (s *objectService) Select(ctx context.Context, bucketName, objectName string, opts s3select.Options) (_ *s3select.Results, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Select", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Scorpio69t/rustfs-go/pkg/acl"
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
 	"github.com/Scorpio69t/rustfs-go/pkg/objectlock"
 	"github.com/Scorpio69t/rustfs-go/pkg/policy"
 	"github.com/Scorpio69t/rustfs-go/pkg/restore"
@@ -24,6 +25,9 @@ type Service interface {
 	// Get downloads an object
 	Get(ctx context.Context, bucketName, objectName string, opts ...GetOption) (io.ReadCloser, types.ObjectInfo, error)
 
+	// Open returns a seekable, random-access handle backed by ranged GETs
+	Open(ctx context.Context, bucketName, objectName string, opts ...GetOption) (*Object, error)
+
 	// FPut uploads a file from a local path
 	FPut(ctx context.Context, bucketName, objectName, filePath string, opts ...PutOption) (types.UploadInfo, error)
 
@@ -36,6 +40,9 @@ type Service interface {
 	// Delete removes an object
 	Delete(ctx context.Context, bucketName, objectName string, opts ...DeleteOption) error
 
+	// DeleteMany removes objects received from a channel in batches and streams back failures
+	DeleteMany(ctx context.Context, bucketName string, objectsCh <-chan types.ObjectToDelete, opts ...DeleteManyOption) <-chan types.DeleteError
+
 	// List lists objects
 	List(ctx context.Context, bucketName string, opts ...ListOption) <-chan types.ObjectInfo
 
@@ -45,6 +52,9 @@ type Service interface {
 	// Copy copies an object
 	Copy(ctx context.Context, destBucket, destObject, srcBucket, srcObject string, opts ...CopyOption) (types.CopyInfo, error)
 
+	// Rewrap rotates the wrapped CSE data key of an object with a metadata-only copy
+	Rewrap(ctx context.Context, bucketName, objectName string, client *cse.Client) (types.CopyInfo, error)
+
 	// Compose creates an object by composing source objects
 	Compose(ctx context.Context, dst DestinationInfo, sources []SourceInfo, opts ...PutOption) (types.UploadInfo, error)
 
@@ -69,6 +79,21 @@ type Service interface {
 	// PresignPut creates a presigned PUT URL with optional signed headers
 	PresignPut(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values, opts ...PresignOption) (*url.URL, http.Header, error)
 
+	// InitiateMultipartUpload starts a multipart upload and returns its upload ID
+	InitiateMultipartUpload(ctx context.Context, bucketName, objectName string, opts ...PutOption) (string, error)
+
+	// UploadPart uploads a single part of a multipart upload
+	UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, partSize int64, opts ...PutOption) (types.ObjectPart, error)
+
+	// UploadPartCopy uploads a part of a multipart upload by copying from an existing object
+	UploadPartCopy(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, src SourceInfo, opts ...PutOption) (types.ObjectPart, error)
+
+	// CompleteMultipartUpload assembles uploaded parts into the final object
+	CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []types.ObjectPart, opts ...PutOption) (types.UploadInfo, error)
+
+	// AbortMultipartUpload aborts a multipart upload and discards its parts
+	AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error
+
 	// ListMultipartUploads lists in-progress multipart uploads for a bucket
 	ListMultipartUploads(ctx context.Context, bucketName string, opts ...MultipartListOption) (ListMultipartUploadsResult, error)
 
@@ -115,6 +140,9 @@ type StatOption func(*StatOptions)
 // DeleteOption applies delete option
 type DeleteOption func(*DeleteOptions)
 
+// DeleteManyOption applies bulk delete option
+type DeleteManyOption func(*DeleteManyOptions)
+
 // ListOption applies list option
 type ListOption func(*ListOptions)
 
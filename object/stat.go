@@ -11,7 +11,14 @@ import (
 )
 
 // Stat gets object information (implementation)
-func (s *objectService) Stat(ctx context.Context, bucketName, objectName string, opts ...StatOption) (types.ObjectInfo, error) {
+func (s *objectService) Stat(ctx context.Context, bucketName, objectName string, opts ...StatOption) (_ types.ObjectInfo, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.Stat", bucketName, objectName)
+	defer func() { call.End(err) }()
+	return s.stat(ctx, bucketName, objectName, opts...)
+}
+
+// stat gets object information as part of the caller's traced call
+func (s *objectService) stat(ctx context.Context, bucketName, objectName string, opts ...StatOption) (types.ObjectInfo, error) {
 	// Validate parameters
 	if err := validateBucketName(bucketName); err != nil {
 		return types.ObjectInfo{}, err
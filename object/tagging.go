@@ -22,7 +22,10 @@ type taggingConfig struct {
 }
 
 // SetTagging sets tags on an object.
-func (s *objectService) SetTagging(ctx context.Context, bucketName, objectName string, tags map[string]string) error {
+func (s *objectService) SetTagging(ctx context.Context, bucketName, objectName string, tags map[string]string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.SetTagging", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -63,7 +66,10 @@ func (s *objectService) SetTagging(ctx context.Context, bucketName, objectName s
 }
 
 // GetTagging retrieves tags from an object.
-func (s *objectService) GetTagging(ctx context.Context, bucketName, objectName string) (map[string]string, error) {
+func (s *objectService) GetTagging(ctx context.Context, bucketName, objectName string) (_ map[string]string, err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.GetTagging", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return nil, err
 	}
@@ -103,7 +109,10 @@ func (s *objectService) GetTagging(ctx context.Context, bucketName, objectName s
 }
 
 // DeleteTagging removes tags from an object.
-func (s *objectService) DeleteTagging(ctx context.Context, bucketName, objectName string) error {
+func (s *objectService) DeleteTagging(ctx context.Context, bucketName, objectName string) (err error) {
+	ctx, call := s.executor.StartCall(ctx, "Object.DeleteTagging", bucketName, objectName)
+	defer func() { call.End(err) }()
+
 	if err := validateBucketName(bucketName); err != nil {
 		return err
 	}
@@ -5,8 +5,15 @@ import (
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"time"
 
+	"github.com/Scorpio69t/rustfs-go/pkg/breaker"
 	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/pkg/hedge"
+	"github.com/Scorpio69t/rustfs-go/pkg/metrics"
+	"github.com/Scorpio69t/rustfs-go/pkg/ratelimit"
+	"github.com/Scorpio69t/rustfs-go/pkg/retry"
+	"github.com/Scorpio69t/rustfs-go/pkg/tracing"
 	"github.com/Scorpio69t/rustfs-go/types"
 )
 
@@ -29,8 +36,19 @@ type Options struct {
 	Transport http.RoundTripper
 
 	// Trace is the HTTP trace client
+	// Its hooks are attached to every request attempt
 	Trace *httptrace.ClientTrace
 
+	// Tracer receives a span for every SDK call, with a child span per HTTP
+	// attempt carrying status, S3 error code, request ID and connection timings
+	// Default: no tracing
+	Tracer tracing.Tracer
+
+	// MetricsCollector receives the operation, status, S3 error code, attempt
+	// count, bytes and latencies of every request, e.g. metrics.NewMemory()
+	// Default: no metrics
+	MetricsCollector metrics.Collector
+
 	// BucketLookup is the bucket lookup type
 	// Default: BucketLookupAuto
 	BucketLookup types.BucketLookupType
@@ -42,6 +60,8 @@ type Options struct {
 	BucketLookupViaURL func(u url.URL, bucketName string) types.BucketLookupType
 
 	// TrailingHeaders enables trailing headers (for streaming upload)
+	// Non-seekable upload bodies are sent as aws-chunked with a checksum
+	// trailer (CRC32C unless another algorithm is requested) computed on the fly.
 	// Requires server support
 	TrailingHeaders bool
 
@@ -49,8 +69,65 @@ type Options struct {
 	// Default: 10, set to 1 to disable retries
 	MaxRetries int
 
+	// RetryPolicy decides whether and when failed requests are retried
+	// Default: retry.New(MaxRetries), full-jitter backoff with a retry budget
+	// shared by all requests of the client
+	RetryPolicy retry.Policy
+
+	// CircuitBreaker fails requests fast with errors.ErrCircuitOpen after
+	// consecutive retryable failures of an endpoint, or of a bucket with
+	// breaker.Config.PerBucket, e.g. breaker.New(breaker.Config{})
+	// Default: no circuit breaker
+	CircuitBreaker *breaker.Breaker
+
+	// Hedger enables hedged object reads (Get, Stat): when an attempt has
+	// not returned headers within a percentile of recent latencies, an
+	// identical request is sent if the rate limit allows, and the first
+	// answer wins. Hedger.Stats reports the hedge rate, e.g. hedge.New(hedge.Config{})
+	// Default: no hedging
+	Hedger *hedge.Hedger
+
+	// Interceptors run around every request attempt, in order, e.g. to add
+	// headers or audit requests with their bucket, object and operation
+	Interceptors []Interceptor
+
+	// UploadBandwidth and DownloadBandwidth limit the bytes per second sent
+	// in request bodies and read from response bodies by all goroutines
+	// using the client. Adjust them at runtime through Client.RateLimits
+	// Default: 0, unlimited
+	UploadBandwidth   int64
+	DownloadBandwidth int64
+
+	// RequestRate limits the requests per second of each operation class,
+	// e.g. {ratelimit.ClassWrite: 50}. Retries count towards the limit
+	// Default: unlimited
+	RequestRate map[ratelimit.Class]float64
+
 	// Accelerate enables S3 Accelerate endpoints for object operations
 	Accelerate bool
+
+	// LoadBalancing spreads the requests of a NewWithEndpoints client
+	// across its endpoints
+	// Default: BalanceRoundRobin
+	LoadBalancing BalancePolicy
+
+	// HealthCheckInterval is how often a NewWithEndpoints client probes its
+	// endpoints with HealthCheck, leaving unhealthy ones out of rotation
+	// Default: 10 seconds, negative disables probes
+	HealthCheckInterval time.Duration
+
+	// EjectionTime is how long a NewWithEndpoints client leaves an endpoint
+	// out of rotation after a connection-level error, unless a health probe
+	// succeeds first
+	// Default: 30 seconds
+	EjectionTime time.Duration
+
+	// TrackClockSkew keeps the signing time in step with the server clock
+	// using the Date header of every response. Without it the clock offset
+	// is only learned when a request fails with RequestTimeTooSkewed, which
+	// is then retried once
+	// Default: false
+	TrackClockSkew bool
 }
 
 // validate validates options
@@ -0,0 +1,242 @@
+// Package breaker provides a circuit breaker that stops requests to an
+// endpoint, or a bucket on it, after consecutive transient failures and
+// probes it again after a cool-down.
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/errors"
+)
+
+// State is the state of a circuit
+type State int
+
+const (
+	// Closed circuits let requests through and count consecutive failures
+	Closed State = iota
+	// Open circuits reject requests until the open timeout has elapsed
+	Open
+	// HalfOpen circuits let a limited number of trial requests through; a
+	// success closes the circuit and a failure opens it again
+	HalfOpen
+)
+
+// String returns the state name
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failures that opens a circuit
+	DefaultFailureThreshold = 5
+	// DefaultOpenTimeout is how long a circuit stays open before trial requests
+	DefaultOpenTimeout = 30 * time.Second
+	// DefaultHalfOpenRequests is the number of trial requests of a half-open circuit
+	DefaultHalfOpenRequests = 1
+)
+
+// Config configures a Breaker
+type Config struct {
+	// FailureThreshold is the number of consecutive retryable failures that
+	// opens a circuit. Default: DefaultFailureThreshold
+	FailureThreshold int
+	// OpenTimeout is how long a circuit rejects requests before letting
+	// trial requests through. Default: DefaultOpenTimeout
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of concurrent trial requests of a
+	// half-open circuit, all of which must succeed to close it.
+	// Default: DefaultHalfOpenRequests
+	HalfOpenRequests int
+	// PerBucket keys circuits by endpoint host and bucket instead of host
+	// alone, so that one failing bucket does not stop requests to others
+	PerBucket bool
+	// OnStateChange is called, outside the breaker's lock, whenever a
+	// circuit changes state
+	OnStateChange func(key string, from, to State)
+}
+
+// Breaker holds the circuits of a client, created on first use. It is safe
+// for concurrent use. A nil Breaker lets every request through.
+type Breaker struct {
+	config Config
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of one key
+type circuit struct {
+	state    State
+	failures int
+	openedAt time.Time
+	// trials and successes count the requests of a half-open circuit
+	trials    int
+	successes int
+}
+
+// New returns a Breaker with all circuits closed
+func New(config Config) *Breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultOpenTimeout
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = DefaultHalfOpenRequests
+	}
+	return &Breaker{config: config, circuits: make(map[string]*circuit)}
+}
+
+// Key returns the circuit key of a request to bucket on host
+func (b *Breaker) Key(host, bucket string) string {
+	if b == nil || !b.config.PerBucket || bucket == "" {
+		return host
+	}
+	return host + "/" + bucket
+}
+
+// State returns the state of the circuit of key
+func (b *Breaker) State(key string) State {
+	if b == nil {
+		return Closed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok {
+		return Closed
+	}
+	if c.state == Open && b.cooledDown(c, time.Now()) {
+		return HalfOpen
+	}
+	return c.state
+}
+
+// Allow reports whether a request may be sent on the circuit of key,
+// returning a *errors.CircuitOpenError if not. An allowed request must be
+// followed by a call to Record, or to Release if it ended without an outcome.
+func (b *Breaker) Allow(key string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	c := b.circuit(key)
+	now := time.Now()
+	from := c.state
+	if c.state == Open && b.cooledDown(c, now) {
+		c.state, c.trials, c.successes = HalfOpen, 0, 0
+	}
+
+	var err error
+	switch {
+	case c.state == Open:
+		err = &errors.CircuitOpenError{Key: key, RetryAt: c.openedAt.Add(b.config.OpenTimeout)}
+	case c.state == HalfOpen && c.trials >= b.config.HalfOpenRequests:
+		err = &errors.CircuitOpenError{Key: key, RetryAt: now}
+	case c.state == HalfOpen:
+		c.trials++
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.notify(key, from, to)
+	return err
+}
+
+// Record reports the outcome of a request allowed on the circuit of key.
+// Failures are transient errors a retry could fix; any other outcome,
+// including client errors, shows that the endpoint is serving.
+func (b *Breaker) Record(key string, failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	c := b.circuit(key)
+	from := c.state
+
+	switch c.state {
+	case Closed:
+		if !failed {
+			c.failures = 0
+		} else if c.failures++; c.failures >= b.config.FailureThreshold {
+			c.state, c.openedAt = Open, time.Now()
+		}
+	case HalfOpen:
+		if failed {
+			c.state, c.openedAt = Open, time.Now()
+		} else if c.successes++; c.successes >= b.config.HalfOpenRequests {
+			c.state, c.failures = Closed, 0
+		}
+	}
+	// Outcomes of requests sent before the circuit opened are ignored
+	to := c.state
+	b.mu.Unlock()
+
+	b.notify(key, from, to)
+}
+
+// Release ends a request allowed on the circuit of key without an outcome,
+// such as one cancelled by its caller. It leaves the failure count alone and
+// gives a half-open circuit's trial slot back for another request.
+func (b *Breaker) Release(key string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[key]; ok && c.state == HalfOpen && c.trials > c.successes {
+		c.trials--
+	}
+}
+
+// Reset closes every circuit
+func (b *Breaker) Reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	changed := make(map[string]State)
+	for key, c := range b.circuits {
+		if c.state != Closed {
+			changed[key] = c.state
+		}
+	}
+	b.circuits = make(map[string]*circuit)
+	b.mu.Unlock()
+
+	for key, from := range changed {
+		b.notify(key, from, Closed)
+	}
+}
+
+func (b *Breaker) circuit(key string) *circuit {
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	return c
+}
+
+func (b *Breaker) cooledDown(c *circuit, now time.Time) bool {
+	return !now.Before(c.openedAt.Add(b.config.OpenTimeout))
+}
+
+func (b *Breaker) notify(key string, from, to State) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(key, from, to)
+	}
+}
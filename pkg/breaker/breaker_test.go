@@ -0,0 +1,157 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	rustfserrors "github.com/Scorpio69t/rustfs-go/errors"
+)
+
+type transition struct {
+	key      string
+	from, to State
+}
+
+// newTestBreaker returns a breaker recording its state changes
+func newTestBreaker(config Config) (*Breaker, func() []transition) {
+	var mu sync.Mutex
+	var changes []transition
+	config.OnStateChange = func(key string, from, to State) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, transition{key, from, to})
+	}
+	return New(config), func() []transition {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]transition(nil), changes...)
+	}
+}
+
+func TestBreakerTripsAndRecovers(t *testing.T) {
+	b, changes := newTestBreaker(Config{FailureThreshold: 3, OpenTimeout: 20 * time.Millisecond})
+	const key = "node1:9000"
+
+	// Successes reset the count of consecutive failures
+	for _, failed := range []bool{true, true, false, true, true} {
+		if err := b.Allow(key); err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+		b.Record(key, failed)
+	}
+	if b.State(key) != Closed {
+		t.Fatalf("State() = %v, want closed", b.State(key))
+	}
+
+	_ = b.Allow(key)
+	b.Record(key, true)
+	if b.State(key) != Open {
+		t.Fatalf("State() = %v, want open", b.State(key))
+	}
+	err := b.Allow(key)
+	var openErr *rustfserrors.CircuitOpenError
+	if !errors.Is(err, rustfserrors.ErrCircuitOpen) || !errors.As(err, &openErr) || openErr.Key != key {
+		t.Fatalf("Allow() error = %v, want a circuit open error", err)
+	}
+
+	// After the timeout one trial request is let through
+	time.Sleep(25 * time.Millisecond)
+	if b.State(key) != HalfOpen {
+		t.Fatalf("State() = %v, want half-open", b.State(key))
+	}
+	if err := b.Allow(key); err != nil {
+		t.Fatalf("trial Allow() error = %v", err)
+	}
+	if err := b.Allow(key); !rustfserrors.IsCircuitOpen(err) {
+		t.Fatalf("second trial Allow() error = %v, want circuit open", err)
+	}
+	b.Record(key, false)
+	if b.State(key) != Closed {
+		t.Fatalf("State() = %v, want closed", b.State(key))
+	}
+
+	want := []transition{{key, Closed, Open}, {key, Open, HalfOpen}, {key, HalfOpen, Closed}}
+	got := changes()
+	if len(got) != len(want) {
+		t.Fatalf("state changes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBreakerFailedTrialReopens(t *testing.T) {
+	b, _ := newTestBreaker(Config{FailureThreshold: 1, OpenTimeout: 10 * time.Millisecond})
+	_ = b.Allow("host")
+	b.Record("host", true)
+
+	time.Sleep(15 * time.Millisecond)
+	if err := b.Allow("host"); err != nil {
+		t.Fatalf("trial Allow() error = %v", err)
+	}
+	b.Record("host", true)
+	if b.State("host") != Open {
+		t.Errorf("State() = %v, want open after a failed trial", b.State("host"))
+	}
+}
+
+func TestBreakerReleasedTrial(t *testing.T) {
+	b, _ := newTestBreaker(Config{FailureThreshold: 2, OpenTimeout: 10 * time.Millisecond})
+	_ = b.Allow("host")
+	b.Record("host", true)
+
+	// Released requests neither reset nor add to the failure count
+	_ = b.Allow("host")
+	b.Release("host")
+	_ = b.Allow("host")
+	b.Record("host", true)
+	if b.State("host") != Open {
+		t.Fatalf("State() = %v, want open", b.State("host"))
+	}
+
+	// A cancelled trial leaves the circuit half-open and frees its slot
+	time.Sleep(15 * time.Millisecond)
+	if err := b.Allow("host"); err != nil {
+		t.Fatalf("trial Allow() error = %v", err)
+	}
+	b.Release("host")
+	if b.State("host") != HalfOpen {
+		t.Fatalf("State() = %v, want half-open after a released trial", b.State("host"))
+	}
+	if err := b.Allow("host"); err != nil {
+		t.Fatalf("second trial Allow() error = %v", err)
+	}
+	b.Record("host", false)
+	if b.State("host") != Closed {
+		t.Errorf("State() = %v, want closed", b.State("host"))
+	}
+}
+
+func TestBreakerKeys(t *testing.T) {
+	perHost := New(Config{FailureThreshold: 1})
+	if perHost.Key("node1", "photos") != "node1" {
+		t.Errorf("Key() = %q, want the host", perHost.Key("node1", "photos"))
+	}
+
+	perBucket, changes := newTestBreaker(Config{FailureThreshold: 1, PerBucket: true})
+	photos, logs := perBucket.Key("node1", "photos"), perBucket.Key("node1", "logs")
+	_ = perBucket.Allow(photos)
+	perBucket.Record(photos, true)
+	if perBucket.Allow(photos) == nil || perBucket.Allow(logs) != nil {
+		t.Error("only the failing bucket's circuit should open")
+	}
+
+	perBucket.Reset()
+	if perBucket.State(photos) != Closed || len(changes()) != 2 {
+		t.Errorf("after Reset() state = %v, changes = %v", perBucket.State(photos), changes())
+	}
+
+	var none *Breaker
+	if none.Allow("host") != nil || none.State("host") != Closed {
+		t.Error("nil Breaker should let requests through")
+	}
+}
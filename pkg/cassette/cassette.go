@@ -0,0 +1,118 @@
+// Package cassette records HTTP exchanges with a RustFS server to a cassette
+// file and replays them later, so SDK tests captured once against a live
+// cluster can run offline and deterministically. A Transport plugs into the
+// client through Options.Transport.
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// formatVersion is the version of the cassette file format
+const formatVersion = 1
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Credentials are redacted before it is
+// stored.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a request or response body. It is stored as text when it is valid
+// UTF-8 and base64-encoded otherwise.
+type Body []byte
+
+// bodyJSON is the stored form of a Body
+type bodyJSON struct {
+	Text   *string `json:"text,omitempty"`
+	Base64 string  `json:"base64,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte("null"), nil
+	}
+	if utf8.Valid(b) {
+		text := string(b)
+		return json.Marshal(bodyJSON{Text: &text})
+	}
+	return json.Marshal(bodyJSON{Base64: base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var stored *bodyJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	switch {
+	case stored == nil:
+		*b = nil
+	case stored.Text != nil:
+		*b = Body(*stored.Text)
+	default:
+		decoded, err := base64.StdEncoding.DecodeString(stored.Base64)
+		if err != nil {
+			return fmt.Errorf("cassette: invalid base64 body: %w", err)
+		}
+		*b = decoded
+	}
+	return nil
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette: parsing %s: %w", path, err)
+	}
+	if c.Version != formatVersion {
+		return nil, fmt.Errorf("cassette: %s has unsupported version %d", path, c.Version)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating its directory
+func (c *Cassette) Save(path string) error {
+	c.Version = formatVersion
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,376 @@
+// Package cassette pkg/cassette/transport.go
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// Mode selects whether a Transport records or replays
+type Mode int
+
+const (
+	// ModeAuto replays the cassette when its file exists and records it
+	// otherwise
+	ModeAuto Mode = iota
+	// ModeReplay only replays the cassette and never contacts a server
+	ModeReplay
+	// ModeRecord sends every request and records a new cassette
+	ModeRecord
+)
+
+// String returns the mode name
+func (m Mode) String() string {
+	switch m {
+	case ModeAuto:
+		return "auto"
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	default:
+		return "unknown"
+	}
+}
+
+// Redacted replaces credentials in recorded requests
+const Redacted = "REDACTED"
+
+// ErrNoMatch is returned when replaying a request that matches no unused
+// recorded interaction
+var ErrNoMatch = errors.New("cassette: no recorded interaction matches the request")
+
+// BodyMatcher reports whether a request body matches a recorded one
+type BodyMatcher func(recorded, actual []byte) bool
+
+// ExactBody matches request bodies byte for byte. Streaming-signed uploads
+// embed a signature in every chunk and never match exactly.
+func ExactBody(recorded, actual []byte) bool {
+	return bytes.Equal(recorded, actual)
+}
+
+// DefaultMatchHeaders are the headers compared when Config.MatchHeaders is
+// nil, besides the method, path and query
+var DefaultMatchHeaders = []string{
+	"Range",
+	"If-Match",
+	"If-None-Match",
+	"X-Amz-Copy-Source",
+	"X-Amz-Copy-Source-Range",
+	"X-Amz-Write-Offset-Bytes",
+}
+
+// volatileHeaders change with every signature or attempt and are never
+// compared
+var volatileHeaders = map[string]bool{
+	"Authorization":         true,
+	"Date":                  true,
+	"X-Amz-Date":            true,
+	"X-Amz-Content-Sha256":  true,
+	"X-Amz-Security-Token":  true,
+	"User-Agent":            true,
+	"Amz-Sdk-Invocation-Id": true,
+	"Amz-Sdk-Request":       true,
+}
+
+// volatileQuery are the presigned URL parameters that are never compared
+var volatileQuery = map[string]bool{
+	"X-Amz-Algorithm":      true,
+	"X-Amz-Credential":     true,
+	"X-Amz-Date":           true,
+	"X-Amz-Expires":        true,
+	"X-Amz-SignedHeaders":  true,
+	"X-Amz-Signature":      true,
+	"X-Amz-Security-Token": true,
+	"AWSAccessKeyId":       true,
+	"Expires":              true,
+	"Signature":            true,
+}
+
+// secretHeaders are always redacted from recorded requests
+var secretHeaders = []string{
+	"Authorization",
+	"X-Amz-Security-Token",
+	"X-Amz-Server-Side-Encryption-Customer-Key",
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key",
+}
+
+// secretQuery are the query parameters always redacted from recorded URLs
+var secretQuery = []string{
+	"X-Amz-Credential",
+	"X-Amz-Signature",
+	"X-Amz-Security-Token",
+	"AWSAccessKeyId",
+	"Signature",
+}
+
+// Config configures a Transport
+type Config struct {
+	// Path is the cassette file
+	Path string
+	// Mode selects recording or replaying
+	// Default: ModeAuto
+	Mode Mode
+	// Transport sends requests while recording
+	// Default: http.DefaultTransport
+	Transport http.RoundTripper
+	// MatchHeaders are the request headers that must equal the recorded
+	// ones. Signature and date headers are never compared.
+	// Default: DefaultMatchHeaders
+	MatchHeaders []string
+	// MatchBody compares request bodies, e.g. ExactBody
+	// Default: bodies are not compared
+	MatchBody BodyMatcher
+	// RedactHeaders are request and response headers redacted in addition
+	// to the credential headers
+	RedactHeaders []string
+	// Redact is called on every interaction before it is recorded, e.g. to
+	// scrub secrets from bodies
+	Redact func(*Interaction)
+}
+
+// Transport is an http.RoundTripper that records exchanges to a cassette or
+// replays them. When replaying, a request is answered with the first unused
+// recorded interaction that has the same method, path, query (ignoring
+// presigned signature parameters), MatchHeaders and, optionally, body.
+//
+// Example:
+//
+//	tr, err := cassette.New(cassette.Config{Path: "testdata/upload.json"})
+//	client, err := rustfs.New(endpoint, &rustfs.Options{Transport: tr, ...})
+//	...
+//	err = tr.Save()
+type Transport struct {
+	config    Config
+	recording bool
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New returns a Transport for the cassette at config.Path, loading it
+// unless recording
+func New(config Config) (*Transport, error) {
+	if config.Path == "" {
+		return nil, errors.New("cassette: path is required")
+	}
+	if config.Transport == nil {
+		config.Transport = http.DefaultTransport
+	}
+	if config.MatchHeaders == nil {
+		config.MatchHeaders = DefaultMatchHeaders
+	}
+
+	t := &Transport{config: config}
+	switch config.Mode {
+	case ModeRecord:
+		t.recording = true
+	case ModeReplay, ModeAuto:
+		c, err := Load(config.Path)
+		switch {
+		case err == nil:
+			t.cassette = c
+		case config.Mode == ModeAuto && errors.Is(err, fs.ErrNotExist):
+			t.recording = true
+		default:
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("cassette: invalid mode %d", config.Mode)
+	}
+	if t.recording {
+		t.cassette = &Cassette{Version: formatVersion}
+	}
+	t.used = make([]bool, len(t.cassette.Interactions))
+	return t, nil
+}
+
+// Recording reports whether the transport records rather than replays
+func (t *Transport) Recording() bool {
+	return t.recording
+}
+
+// Save writes the recorded cassette to Config.Path. It does nothing when
+// replaying.
+func (t *Transport) Save() error {
+	if !t.recording {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cassette.Save(t.config.Path)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.recording {
+		return t.record(req, body)
+	}
+	return t.replay(req, body)
+}
+
+// readBody reads and closes the request body
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// record sends the request and records the exchange
+func (t *Transport) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	resp, err := t.config.Transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: req.Header.Clone(),
+			Body:   bytes.Clone(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       bytes.Clone(respBody),
+		},
+	}
+	t.redact(in)
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, in)
+	t.mu.Unlock()
+	return resp, nil
+}
+
+// redact replaces credentials and Config.RedactHeaders in an interaction
+// and applies Config.Redact
+func (t *Transport) redact(in *Interaction) {
+	for _, header := range []http.Header{in.Request.Header, in.Response.Header} {
+		for _, names := range [][]string{secretHeaders, t.config.RedactHeaders} {
+			for _, name := range names {
+				if header.Get(name) != "" {
+					header.Set(name, Redacted)
+				}
+			}
+		}
+	}
+	if t.config.Redact != nil {
+		t.config.Redact(in)
+	}
+}
+
+// redactURL returns u with credentials in its query redacted
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := u.Query()
+	changed := false
+	for _, name := range secretQuery {
+		if query.Has(name) {
+			query.Set(name, Redacted)
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// replay answers the request from the cassette
+func (t *Transport) replay(req *http.Request, body []byte) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, in := range t.cassette.Interactions {
+		if !t.used[i] && t.matches(&in.Request, req, body) {
+			t.used[i] = true
+			return replayResponse(&in.Response, req), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, req.Method, req.URL.Path)
+}
+
+// matches reports whether a recorded request matches req
+func (t *Transport) matches(recorded *Request, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	u, err := url.Parse(recorded.URL)
+	if err != nil || u.EscapedPath() != req.URL.EscapedPath() || stableQuery(u) != stableQuery(req.URL) {
+		return false
+	}
+	for _, name := range t.config.MatchHeaders {
+		name = http.CanonicalHeaderKey(name)
+		value := recorded.Header.Get(name)
+		if volatileHeaders[name] || value == Redacted {
+			continue
+		}
+		if value != req.Header.Get(name) {
+			return false
+		}
+	}
+	return t.config.MatchBody == nil || t.config.MatchBody(recorded.Body, body)
+}
+
+// stableQuery returns the sorted query of u without signature parameters
+func stableQuery(u *url.URL) string {
+	query := u.Query()
+	for name := range query {
+		if volatileQuery[name] {
+			delete(query, name)
+		}
+	}
+	return query.Encode()
+}
+
+// replayResponse builds the response to req from a recorded one
+func replayResponse(recorded *Response, req *http.Request) *http.Response {
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	contentLength := int64(len(recorded.Body))
+	if req.Method == http.MethodHead {
+		// HEAD responses describe a body they do not carry
+		contentLength, _ = strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: contentLength,
+		Request:       req,
+	}
+}
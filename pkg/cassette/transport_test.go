@@ -0,0 +1,228 @@
+// Package cassette pkg/cassette/transport_test.go
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rustfs "github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/rustfstest"
+)
+
+// exercise runs a fixed sequence of operations and returns what was read
+func exercise(t *testing.T, client *rustfs.Client) string {
+	t.Helper()
+	ctx := context.Background()
+	if err := client.Bucket().Create(ctx, "cassette"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, data := range []string{"first", "second"} {
+		if _, err := client.Object().Put(ctx, "cassette", "key", strings.NewReader(data), int64(len(data))); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	reader, _, err := client.Object().Get(ctx, "cassette", "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading object: %v", err)
+	}
+	info, err := client.Object().Stat(ctx, "cassette", "key")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	return string(data) + "/" + info.ETag
+}
+
+func newClient(t *testing.T, server *rustfstest.Server, tr http.RoundTripper) *rustfs.Client {
+	t.Helper()
+	opts := server.Options()
+	opts.Transport = tr
+	opts.MaxRetries = 1
+	client, err := rustfs.New(server.Endpoint(), opts)
+	if err != nil {
+		t.Fatalf("rustfs.New() error = %v", err)
+	}
+	return client
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "session.json")
+	server := rustfstest.NewServer(rustfstest.Config{})
+	defer server.Close()
+
+	recorder, err := New(Config{Path: path, Mode: ModeAuto, Transport: server.Options().Transport})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if !recorder.Recording() {
+		t.Fatal("Recording() = false for a missing cassette")
+	}
+	recorded := exercise(t, newClient(t, server, recorder))
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	if bytes.Contains(raw, []byte("Credential="+rustfstest.DefaultAccessKey)) || !bytes.Contains(raw, []byte(Redacted)) {
+		t.Error("cassette contains an unredacted Authorization header")
+	}
+
+	// Replay against a server that is gone
+	server.Close()
+	player, err := New(Config{Path: path})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if player.Recording() {
+		t.Fatal("Recording() = true for an existing cassette")
+	}
+	if replayed := exercise(t, newClient(t, server, player)); replayed != recorded {
+		t.Errorf("replayed %q, recorded %q", replayed, recorded)
+	}
+
+	// Every interaction is replayed once
+	_, err = newClient(t, server, player).Object().Stat(context.Background(), "cassette", "key")
+	if !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Stat() beyond the cassette error = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestMatching(t *testing.T) {
+	c := &Cassette{Interactions: []*Interaction{{
+		Request: Request{
+			Method: http.MethodPut,
+			URL:    "http://recorded:9000/bucket/key?X-Amz-Signature=REDACTED&partNumber=1&uploadId=abc",
+			Header: http.Header{"Range": {"bytes=0-9"}, "Authorization": {Redacted}},
+			Body:   Body("payload"),
+		},
+		Response: Response{StatusCode: http.StatusOK, Header: http.Header{"Etag": {`"etag"`}}},
+	}}}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		rangeSpec string
+		body      string
+		wantMatch bool
+	}{
+		{"same request", "http://replay/bucket/key?uploadId=abc&partNumber=1&X-Amz-Signature=other", "bytes=0-9", "payload", true},
+		{"other query", "http://replay/bucket/key?uploadId=abc&partNumber=2", "bytes=0-9", "payload", false},
+		{"other path", "http://replay/bucket/other?uploadId=abc&partNumber=1", "bytes=0-9", "payload", false},
+		{"other header", "http://replay/bucket/key?uploadId=abc&partNumber=1", "bytes=10-19", "payload", false},
+		{"other body", "http://replay/bucket/key?uploadId=abc&partNumber=1", "bytes=0-9", "changed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := New(Config{Path: path, Mode: ModeReplay, MatchBody: ExactBody})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			req, _ := http.NewRequest(http.MethodPut, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Range", tt.rangeSpec)
+			req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=...")
+			resp, err := tr.RoundTrip(req)
+			if tt.wantMatch {
+				if err != nil || resp.Header.Get("ETag") != `"etag"` {
+					t.Errorf("RoundTrip() = %v, %v, want the recorded response", resp, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrNoMatch) {
+				t.Errorf("RoundTrip() error = %v, want ErrNoMatch", err)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Session": {"secret"}},
+			Body:       io.NopCloser(strings.NewReader("token=secret")),
+		}, nil
+	})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	tr, err := New(Config{
+		Path:          path,
+		Mode:          ModeRecord,
+		Transport:     upstream,
+		RedactHeaders: []string{"X-Session"},
+		Redact: func(in *Interaction) {
+			in.Response.Body = bytes.ReplaceAll(in.Response.Body, []byte("secret"), []byte(Redacted))
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://host/bucket/key?X-Amz-Credential=AKID%2F20260101&X-Amz-Signature=abc", nil)
+	req.Header.Set("X-Amz-Security-Token", "session-token")
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "token=secret" {
+		t.Errorf("RoundTrip() body = %q, want the unredacted response", body)
+	}
+	if err := tr.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	in := c.Interactions[0]
+	for _, secret := range []string{"AKID", "abc", "session-token"} {
+		if strings.Contains(in.Request.URL, secret) || strings.Contains(in.Request.Header.Get("X-Amz-Security-Token"), secret) {
+			t.Errorf("recorded request contains %q: %s", secret, in.Request.URL)
+		}
+	}
+	if in.Response.Header.Get("X-Session") != Redacted || string(in.Response.Body) != "token="+Redacted {
+		t.Errorf("recorded response = %v %q, want redacted", in.Response.Header, in.Response.Body)
+	}
+}
+
+func TestBodyEncoding(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	binary := []byte{0xff, 0x00, 0xfe}
+	c := &Cassette{Interactions: []*Interaction{{
+		Request:  Request{Method: http.MethodPut, URL: "http://host/b/k", Body: Body("text")},
+		Response: Response{StatusCode: http.StatusOK, Body: binary},
+	}}}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	in := loaded.Interactions[0]
+	if string(in.Request.Body) != "text" || !bytes.Equal(in.Response.Body, binary) {
+		t.Errorf("Load() bodies = %q, %v", in.Request.Body, []byte(in.Response.Body))
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,187 @@
+// Package checksum provides the S3 additional checksum algorithms
+// (CRC32, CRC32C, SHA1, SHA256 and CRC64NVME) and their header encoding.
+package checksum
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"strconv"
+	"strings"
+
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// headerPrefix is the common prefix of checksum headers and trailers
+const headerPrefix = "x-amz-checksum-"
+
+// nvmePoly is the reflected CRC-64/NVME polynomial
+const nvmePoly = 0x9A6C9329AC4BC9B5
+
+var (
+	castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+	nvmeTable       = crc64.MakeTable(nvmePoly)
+)
+
+// New returns a hash for the checksum algorithm, or nil for ChecksumNone
+// and unknown values.
+func New(algorithm types.ChecksumType) hash.Hash {
+	switch algorithm {
+	case types.ChecksumCRC32:
+		return crc32.NewIEEE()
+	case types.ChecksumCRC32C:
+		return crc32.New(castagnoliTable)
+	case types.ChecksumSHA1:
+		return sha1.New()
+	case types.ChecksumSHA256:
+		return sha256.New()
+	case types.ChecksumCRC64NVME:
+		return crc64.New(nvmeTable)
+	default:
+		return nil
+	}
+}
+
+// Parse returns the checksum algorithm named by name (case-insensitive).
+// An empty name yields ChecksumNone.
+func Parse(name string) (types.ChecksumType, error) {
+	switch strings.ToUpper(name) {
+	case "":
+		return types.ChecksumNone, nil
+	case "CRC32":
+		return types.ChecksumCRC32, nil
+	case "CRC32C":
+		return types.ChecksumCRC32C, nil
+	case "SHA1":
+		return types.ChecksumSHA1, nil
+	case "SHA256":
+		return types.ChecksumSHA256, nil
+	case "CRC64NVME":
+		return types.ChecksumCRC64NVME, nil
+	default:
+		return types.ChecksumNone, fmt.Errorf("unsupported checksum algorithm %q", name)
+	}
+}
+
+// HeaderKey returns the header carrying the checksum, e.g. x-amz-checksum-crc32c.
+func HeaderKey(algorithm types.ChecksumType) string {
+	if algorithm == types.ChecksumNone {
+		return ""
+	}
+	return headerPrefix + strings.ToLower(algorithm.String())
+}
+
+// FromHeaderKey returns the algorithm whose checksum is carried by header key.
+func FromHeaderKey(key string) (types.ChecksumType, bool) {
+	name, ok := strings.CutPrefix(strings.ToLower(key), headerPrefix)
+	if !ok {
+		return types.ChecksumNone, false
+	}
+	algorithm, err := Parse(name)
+	if err != nil || algorithm == types.ChecksumNone {
+		return types.ChecksumNone, false
+	}
+	return algorithm, true
+}
+
+// Encode returns the base64 header encoding of a checksum.
+func Encode(sum []byte) string {
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+// Decode returns the raw checksum of a base64 header value.
+func Decode(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// Composite returns the multipart composite checksum of the given part
+// checksums: the checksum of their concatenated raw values, followed by
+// "-" and the number of parts. CRC64NVME has no composite form, so it
+// returns "" for it; see CombineCRC64NVME.
+func Composite(algorithm types.ChecksumType, partSums [][]byte) string {
+	h := New(algorithm)
+	if h == nil || algorithm == types.ChecksumCRC64NVME {
+		return ""
+	}
+	for _, sum := range partSums {
+		h.Write(sum)
+	}
+	return Encode(h.Sum(nil)) + "-" + strconv.Itoa(len(partSums))
+}
+
+// CombineCRC64NVME returns the full-object CRC64NVME checksum of an object
+// uploaded in parts, from the raw checksums and sizes of its parts. It
+// returns "" if a checksum is not 8 bytes long.
+func CombineCRC64NVME(partSums [][]byte, partSizes []int64) string {
+	var crc uint64
+	for i, sum := range partSums {
+		if len(sum) != crc64.Size {
+			return ""
+		}
+		crc = combineCRC64(crc, binary.BigEndian.Uint64(sum), partSizes[i])
+	}
+	return Encode(binary.BigEndian.AppendUint64(nil, crc))
+}
+
+// combineCRC64 returns the CRC-64/NVME of A followed by B from crcA, crcB
+// and the length of B, by applying the CRC of lenB zero bytes to crcA as a
+// GF(2) matrix (the method of zlib's crc32_combine)
+func combineCRC64(crcA, crcB uint64, lenB int64) uint64 {
+	if lenB <= 0 {
+		return crcA
+	}
+
+	// odd is the operator for one zero bit, even the one for two
+	var even, odd [64]uint64
+	odd[0] = nvmePoly
+	for n, row := 1, uint64(1); n < 64; n, row = n+1, row<<1 {
+		odd[n] = row
+	}
+	gf2MatrixSquare(&even, &odd)
+	gf2MatrixSquare(&odd, &even)
+
+	// Apply lenB zero bytes, squaring the operator for each bit of lenB
+	for {
+		gf2MatrixSquare(&even, &odd)
+		if lenB&1 != 0 {
+			crcA = gf2MatrixTimes(&even, crcA)
+		}
+		if lenB >>= 1; lenB == 0 {
+			break
+		}
+		gf2MatrixSquare(&odd, &even)
+		if lenB&1 != 0 {
+			crcA = gf2MatrixTimes(&odd, crcA)
+		}
+		if lenB >>= 1; lenB == 0 {
+			break
+		}
+	}
+	return crcA ^ crcB
+}
+
+func gf2MatrixTimes(mat *[64]uint64, vec uint64) uint64 {
+	var sum uint64
+	for i := 0; vec != 0; i, vec = i+1, vec>>1 {
+		if vec&1 != 0 {
+			sum ^= mat[i]
+		}
+	}
+	return sum
+}
+
+func gf2MatrixSquare(square, mat *[64]uint64) {
+	for n := range mat {
+		square[n] = gf2MatrixTimes(mat, mat[n])
+	}
+}
+
+// IsComposite reports whether a checksum value is a multipart composite checksum.
+func IsComposite(value string) bool {
+	return strings.Contains(value, "-")
+}
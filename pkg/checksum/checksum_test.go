@@ -0,0 +1,108 @@
+package checksum
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+func TestNewCheckValues(t *testing.T) {
+	// Check values of each algorithm over "123456789"
+	cases := []struct {
+		algorithm types.ChecksumType
+		expected  string
+	}{
+		{types.ChecksumCRC32, "cbf43926"},
+		{types.ChecksumCRC32C, "e3069283"},
+		{types.ChecksumCRC64NVME, "ae8b14860a799888"},
+		{types.ChecksumSHA1, "f7c3bc1d808e04732adf679965ccc34ca7ae3441"},
+		{types.ChecksumSHA256, "15e2b0d3c33891ebb0f1ef609ec419420c20e320ce94c65fbc8c3312448eb225"},
+	}
+
+	for _, tc := range cases {
+		h := New(tc.algorithm)
+		if h == nil {
+			t.Fatalf("New(%s) returned nil", tc.algorithm)
+		}
+		h.Write([]byte("123456789"))
+		if got := hex.EncodeToString(h.Sum(nil)); got != tc.expected {
+			t.Errorf("%s = %s, want %s", tc.algorithm, got, tc.expected)
+		}
+	}
+
+	if New(types.ChecksumNone) != nil {
+		t.Error("New(ChecksumNone) should return nil")
+	}
+}
+
+func TestParseAndHeaderKey(t *testing.T) {
+	algorithm, err := Parse("crc64nvme")
+	if err != nil || algorithm != types.ChecksumCRC64NVME {
+		t.Fatalf("Parse(crc64nvme) = %v, %v", algorithm, err)
+	}
+	if _, err := Parse("md5"); err == nil {
+		t.Error("Parse(md5) should fail")
+	}
+
+	key := HeaderKey(types.ChecksumCRC32C)
+	if key != "x-amz-checksum-crc32c" {
+		t.Fatalf("HeaderKey = %q", key)
+	}
+	if got, ok := FromHeaderKey("X-Amz-Checksum-Crc32c"); !ok || got != types.ChecksumCRC32C {
+		t.Errorf("FromHeaderKey = %v, %v", got, ok)
+	}
+	if _, ok := FromHeaderKey("x-amz-checksum-mode"); ok {
+		t.Error("FromHeaderKey should reject x-amz-checksum-mode")
+	}
+}
+
+func TestComposite(t *testing.T) {
+	parts := [][]byte{[]byte("part-one"), []byte("part-two")}
+	var sums [][]byte
+	for _, part := range parts {
+		h := New(types.ChecksumSHA256)
+		h.Write(part)
+		sums = append(sums, h.Sum(nil))
+	}
+
+	h := New(types.ChecksumSHA256)
+	h.Write(append(append([]byte{}, sums[0]...), sums[1]...))
+	want := Encode(h.Sum(nil)) + "-2"
+
+	got := Composite(types.ChecksumSHA256, sums)
+	if got != want {
+		t.Fatalf("Composite = %q, want %q", got, want)
+	}
+	if !IsComposite(got) {
+		t.Error("IsComposite should report composite values")
+	}
+	if IsComposite(Encode(sums[0])) {
+		t.Error("IsComposite should reject full-object values")
+	}
+}
+
+func TestCombineCRC64NVME(t *testing.T) {
+	data := make([]byte, 100000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	whole := New(types.ChecksumCRC64NVME)
+	whole.Write(data)
+	want := Encode(whole.Sum(nil))
+
+	var sums [][]byte
+	var sizes []int64
+	for _, part := range [][]byte{data[:65536], data[65536:99999], data[99999:]} {
+		h := New(types.ChecksumCRC64NVME)
+		h.Write(part)
+		sums = append(sums, h.Sum(nil))
+		sizes = append(sizes, int64(len(part)))
+	}
+	if got := CombineCRC64NVME(sums, sizes); got != want {
+		t.Errorf("CombineCRC64NVME = %q, want the full-object checksum %q", got, want)
+	}
+	if got := Composite(types.ChecksumCRC64NVME, sums); got != "" {
+		t.Errorf("Composite(CRC64NVME) = %q, want none", got)
+	}
+}
@@ -20,6 +20,7 @@ package credentials
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -58,25 +59,15 @@ func NewFileRustfsClient(filename, alias string) *Credentials {
 
 func (p *FileRustfsClient) retrieve() (Value, error) {
 	if p.Filename == "" {
-		if value, ok := os.LookupEnv("RUSTFS_SHARED_CREDENTIALS_FILE"); ok {
-			p.Filename = value
-		} else {
-			homeDir, err := os.UserHomeDir()
-			if err != nil {
-				return Value{}, err
-			}
-			p.Filename = filepath.Join(homeDir, ".rustfs", "config.json")
-			if runtime.GOOS == "windows" {
-				p.Filename = filepath.Join(homeDir, "rustfs", "config.json")
-			}
+		filename, err := defaultConfigFile()
+		if err != nil {
+			return Value{}, err
 		}
+		p.Filename = filename
 	}
 
 	if p.Alias == "" {
-		p.Alias = os.Getenv("RUSTFS_ALIAS")
-		if p.Alias == "" {
-			p.Alias = "s3"
-		}
+		p.Alias = defaultAlias()
 	}
 
 	p.retrieved = false
@@ -110,12 +101,78 @@ func (p *FileRustfsClient) IsExpired() bool {
 	return !p.retrieved
 }
 
+// defaultConfigFile returns the client config file named by
+// RUSTFS_SHARED_CREDENTIALS_FILE, or the one in the home directory.
+func defaultConfigFile() (string, error) {
+	if value, ok := os.LookupEnv("RUSTFS_SHARED_CREDENTIALS_FILE"); ok {
+		return value, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(homeDir, "rustfs", "config.json"), nil
+	}
+	return filepath.Join(homeDir, ".rustfs", "config.json"), nil
+}
+
+// defaultAlias returns the alias named by RUSTFS_ALIAS, or "s3".
+func defaultAlias() string {
+	if alias := os.Getenv("RUSTFS_ALIAS"); alias != "" {
+		return alias
+	}
+	return "s3"
+}
+
+// Alias is a host entry of the client config file.
+type Alias struct {
+	// URL of the server, e.g. "https://rustfs.example.com:9000"
+	URL string
+	// AccessKey and SecretKey of the host
+	AccessKey string
+	SecretKey string
+	// API is the signature version: "S3v4", "S3v2" or empty
+	API string
+	// Path is the bucket lookup style: "auto", "on" (path-style),
+	// "off" (virtual-host style) or empty
+	Path string
+	// Region of the host, if recorded
+	Region string
+}
+
+// LoadAlias reads an alias from the client config file. An empty filename
+// or alias default as for FileRustfsClient. Unlike FileRustfsClient, a
+// missing alias is an error.
+func LoadAlias(filename, alias string) (Alias, error) {
+	if filename == "" {
+		var err error
+		if filename, err = defaultConfigFile(); err != nil {
+			return Alias{}, err
+		}
+	}
+	if alias == "" {
+		alias = defaultAlias()
+	}
+
+	hostCfg, err := loadAlias(filename, alias)
+	if err != nil {
+		return Alias{}, err
+	}
+	if hostCfg == (hostConfig{}) {
+		return Alias{}, fmt.Errorf("alias %q not found in %s", alias, filename)
+	}
+	return Alias(hostCfg), nil
+}
+
 // hostConfig configuration of a host.
 type hostConfig struct {
 	URL       string `json:"url"`
 	AccessKey string `json:"accessKey"`
 	SecretKey string `json:"secretKey"`
 	API       string `json:"api"`
+	Path      string `json:"path"`
+	Region    string `json:"region"`
 }
 
 // config config version.
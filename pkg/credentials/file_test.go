@@ -213,3 +213,34 @@ func TestFileRustfsClient(t *testing.T) {
 		t.Error("Should be expired if not loaded")
 	}
 }
+
+func TestLoadAlias(t *testing.T) {
+	os.Clearenv()
+
+	alias, err := LoadAlias("config.json.sample", "play")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alias.URL != "https://play.min.io" {
+		t.Errorf("Expected 'https://play.min.io', got %s'", alias.URL)
+	}
+	if alias.AccessKey != "Q3AM3UQ867SPQQA43P2F" || alias.API != "S3v2" {
+		t.Errorf("Unexpected alias %+v", alias)
+	}
+
+	t.Setenv("RUSTFS_ALIAS", "s3")
+	alias, err = LoadAlias("config.json.sample", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alias.URL != "https://s3.amazonaws.com" {
+		t.Errorf("Expected 'https://s3.amazonaws.com', got %s'", alias.URL)
+	}
+
+	if _, err = LoadAlias("config.json.sample", "missing"); err == nil {
+		t.Error("Expected an error for a missing alias")
+	}
+	if _, err = LoadAlias("non-existent.json", "play"); !os.IsNotExist(err) {
+		t.Errorf("Expected open non-existent.json: no such file or directory, got %s", err)
+	}
+}
@@ -22,6 +22,9 @@ const (
 // Client provides client-side encryption helpers.
 type Client struct {
 	key []byte
+
+	// wrapper enables envelope encryption with per-object data keys
+	wrapper KeyWrapper
 }
 
 // New creates a new CSE client with a 16, 24, or 32 byte key.
@@ -35,13 +38,19 @@ func New(key []byte) (*Client, error) {
 }
 
 // Encrypt encrypts the entire reader and returns ciphertext and metadata.
+// The whole object is buffered in memory; use EncryptStream for large objects.
 func (c *Client) Encrypt(reader io.Reader) ([]byte, map[string]string, error) {
 	plain, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	block, err := aes.NewCipher(c.key)
+	key, metadata, err := c.newDataKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,17 +66,23 @@ func (c *Client) Encrypt(reader io.Reader) ([]byte, map[string]string, error) {
 	}
 
 	ciphertext := gcm.Seal(nil, nonce, plain, nil)
-	metadata := map[string]string{
-		MetadataKeyAlgorithm: AlgorithmAESGCM,
-		MetadataKeyNonce:     base64.StdEncoding.EncodeToString(nonce),
-	}
+	metadata[MetadataKeyAlgorithm] = AlgorithmAESGCM
+	metadata[MetadataKeyNonce] = base64.StdEncoding.EncodeToString(nonce)
 
 	return ciphertext, metadata, nil
 }
 
 // Decrypt decrypts the entire reader using metadata.
+// Both the single-shot and the segmented stream formats are accepted.
 func (c *Client) Decrypt(reader io.Reader, metadata map[string]string) ([]byte, error) {
 	algo := metadata[MetadataKeyAlgorithm]
+	if algo == AlgorithmAESGCMStream {
+		plain, err := c.DecryptStream(reader, metadata)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(plain)
+	}
 	if algo != AlgorithmAESGCM {
 		return nil, errors.New("unsupported or missing cse algorithm")
 	}
@@ -86,7 +101,12 @@ func (c *Client) Decrypt(reader io.Reader, metadata map[string]string) ([]byte,
 		return nil, err
 	}
 
-	block, err := aes.NewCipher(c.key)
+	key, err := c.dataKey(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
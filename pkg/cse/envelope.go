@@ -0,0 +1,228 @@
+package cse
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+)
+
+const (
+	// MetadataKeyKeyID stores the ID of the master key that wrapped the data key.
+	MetadataKeyKeyID = "rustfs-cse-key-id"
+	// MetadataKeyWrappedKey stores the base64-encoded wrapped data key.
+	MetadataKeyWrappedKey = "rustfs-cse-wrapped-key"
+
+	// dataKeySize is the size of generated per-object data keys
+	dataKeySize = 32
+)
+
+// aesKWDefaultIV is the RFC 3394 default initial value
+var aesKWDefaultIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// KeyWrapper wraps and unwraps per-object data keys with a master key.
+// Implementations may call out to a KMS; keyID identifies the master key
+// and is stored alongside the wrapped key in object metadata.
+type KeyWrapper interface {
+	// WrapKey encrypts dataKey and returns the ID of the master key used
+	WrapKey(dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key wrapped by the master key keyID
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// NewEnvelope creates a CSE client that encrypts every object with a random
+// data key and stores the data key wrapped by wrapper in object metadata.
+// Rotating the master key then only requires rewrapping the data keys.
+func NewEnvelope(wrapper KeyWrapper) (*Client, error) {
+	if wrapper == nil {
+		return nil, errors.New("cse key wrapper must not be nil")
+	}
+	return &Client{wrapper: wrapper}, nil
+}
+
+// Rewrap unwraps the data key in metadata and wraps it again with the
+// client's wrapper, returning a copy of metadata with the new wrapped key.
+// The object data does not change.
+func (c *Client) Rewrap(metadata map[string]string) (map[string]string, error) {
+	if c.wrapper == nil {
+		return nil, errors.New("cse rewrap requires an envelope client")
+	}
+	dataKey, err := c.dataKey(metadata)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := c.wrapper.WrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rewrapped := maps.Clone(metadata)
+	rewrapped[MetadataKeyKeyID] = keyID
+	rewrapped[MetadataKeyWrappedKey] = base64.StdEncoding.EncodeToString(wrapped)
+	return rewrapped, nil
+}
+
+// newDataKey returns the key for a new object and the metadata describing it.
+// Envelope clients generate and wrap a random data key; static clients use their key.
+func (c *Client) newDataKey() ([]byte, map[string]string, error) {
+	if c.wrapper == nil {
+		return c.key, map[string]string{}, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+	keyID, wrapped, err := c.wrapper.WrapKey(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, map[string]string{
+		MetadataKeyKeyID:      keyID,
+		MetadataKeyWrappedKey: base64.StdEncoding.EncodeToString(wrapped),
+	}, nil
+}
+
+// dataKey returns the key an object was encrypted with
+func (c *Client) dataKey(metadata map[string]string) ([]byte, error) {
+	wrappedB64 := metadata[MetadataKeyWrappedKey]
+	if wrappedB64 == "" {
+		if c.key == nil {
+			return nil, errors.New("missing cse wrapped key")
+		}
+		return c.key, nil
+	}
+	if c.wrapper == nil {
+		return nil, errors.New("cse object uses envelope encryption but client has no key wrapper")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(wrappedB64)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrapper.UnwrapKey(metadata[MetadataKeyKeyID], wrapped)
+}
+
+// AESKeyWrapper wraps data keys with a local key-encryption key using
+// AES Key Wrap (RFC 3394).
+type AESKeyWrapper struct {
+	keyID string
+	block cipher.Block
+}
+
+// NewAESKeyWrapper creates an AES-KW wrapper for a 16, 24, or 32 byte key-encryption key.
+func NewAESKeyWrapper(keyID string, kek []byte) (*AESKeyWrapper, error) {
+	if keyID == "" {
+		return nil, errors.New("cse key ID must not be empty")
+	}
+	switch len(kek) {
+	case 16, 24, 32:
+	default:
+		return nil, errors.New("cse key must be 16, 24, or 32 bytes")
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return &AESKeyWrapper{keyID: keyID, block: block}, nil
+}
+
+// WrapKey wraps dataKey with AES-KW.
+func (w *AESKeyWrapper) WrapKey(dataKey []byte) (string, []byte, error) {
+	if len(dataKey) < 16 || len(dataKey)%8 != 0 {
+		return "", nil, errors.New("data key must be a multiple of 8 bytes and at least 16 bytes")
+	}
+
+	n := len(dataKey) / 8
+	out := make([]byte, 8+len(dataKey))
+	copy(out, aesKWDefaultIV)
+	copy(out[8:], dataKey)
+
+	buf := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buf, out[:8])
+			copy(buf[8:], out[i*8:i*8+8])
+			w.block.Encrypt(buf, buf)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(out[:8], binary.BigEndian.Uint64(buf[:8])^t)
+			copy(out[i*8:], buf[8:])
+		}
+	}
+	return w.keyID, out, nil
+}
+
+// UnwrapKey unwraps a key wrapped by WrapKey and verifies its integrity.
+func (w *AESKeyWrapper) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != w.keyID {
+		return nil, fmt.Errorf("unknown cse key ID %q", keyID)
+	}
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, errors.New("invalid wrapped key size")
+	}
+
+	n := len(wrapped)/8 - 1
+	out := make([]byte, len(wrapped))
+	copy(out, wrapped)
+
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(out[:8])^t)
+			copy(buf[8:], out[i*8:i*8+8])
+			w.block.Decrypt(buf, buf)
+			copy(out[:8], buf[:8])
+			copy(out[i*8:], buf[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(out[:8], aesKWDefaultIV) != 1 {
+		return nil, errors.New("cse key unwrap failed: integrity check mismatch")
+	}
+	return out[8:], nil
+}
+
+// KeyRing wraps new data keys with a primary master key and unwraps data
+// keys wrapped by any master key it holds, so old objects stay readable
+// while the primary key is rotated.
+type KeyRing struct {
+	primary  string
+	wrappers map[string]*AESKeyWrapper
+}
+
+// NewKeyRing creates a key ring from master keys indexed by key ID.
+// primaryID selects the key used to wrap new data keys.
+func NewKeyRing(primaryID string, keys map[string][]byte) (*KeyRing, error) {
+	if _, ok := keys[primaryID]; !ok {
+		return nil, fmt.Errorf("primary cse key ID %q not in key ring", primaryID)
+	}
+	ring := &KeyRing{primary: primaryID, wrappers: make(map[string]*AESKeyWrapper, len(keys))}
+	for keyID, kek := range keys {
+		wrapper, err := NewAESKeyWrapper(keyID, kek)
+		if err != nil {
+			return nil, fmt.Errorf("cse key %q: %w", keyID, err)
+		}
+		ring.wrappers[keyID] = wrapper
+	}
+	return ring, nil
+}
+
+// WrapKey wraps dataKey with the primary master key.
+func (r *KeyRing) WrapKey(dataKey []byte) (string, []byte, error) {
+	return r.wrappers[r.primary].WrapKey(dataKey)
+}
+
+// UnwrapKey unwraps dataKey with the master key keyID.
+func (r *KeyRing) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	wrapper, ok := r.wrappers[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown cse key ID %q", keyID)
+	}
+	return wrapper.UnwrapKey(keyID, wrapped)
+}
@@ -0,0 +1,89 @@
+package cse
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+func TestAESKeyWrapRFC3394(t *testing.T) {
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+	want, _ := hex.DecodeString("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	wrapper, err := NewAESKeyWrapper("kek-1", kek)
+	if err != nil {
+		t.Fatalf("NewAESKeyWrapper() error = %v", err)
+	}
+	keyID, wrapped, err := wrapper.WrapKey(key)
+	if err != nil {
+		t.Fatalf("WrapKey() error = %v", err)
+	}
+	if keyID != "kek-1" || !bytes.Equal(wrapped, want) {
+		t.Errorf("WrapKey() = %s %X, want kek-1 %X", keyID, wrapped, want)
+	}
+
+	unwrapped, err := wrapper.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapKey() error = %v", err)
+	}
+	if !bytes.Equal(unwrapped, key) {
+		t.Errorf("UnwrapKey() = %X, want %X", unwrapped, key)
+	}
+
+	wrapped[3] ^= 0xff
+	if _, err := wrapper.UnwrapKey(keyID, wrapped); err == nil {
+		t.Error("UnwrapKey() accepted a corrupted key")
+	}
+}
+
+func TestEnvelopeRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	oldRing, err := NewKeyRing("v1", map[string][]byte{"v1": oldKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	oldClient, _ := NewEnvelope(oldRing)
+
+	plain := bytes.Repeat([]byte("backup"), 50000)
+	reader, metadata, err := oldClient.EncryptStream(bytes.NewReader(plain))
+	if err != nil {
+		t.Fatalf("EncryptStream() error = %v", err)
+	}
+	ciphertext, _ := io.ReadAll(reader)
+	if metadata[MetadataKeyKeyID] != "v1" || metadata[MetadataKeyWrappedKey] == "" {
+		t.Fatalf("missing envelope metadata: %v", metadata)
+	}
+
+	// Rotate: the new primary is v2, v1 stays available for unwrapping
+	ring, err := NewKeyRing("v2", map[string][]byte{"v1": oldKey, "v2": newKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	client, _ := NewEnvelope(ring)
+
+	rewrapped, err := client.Rewrap(metadata)
+	if err != nil {
+		t.Fatalf("Rewrap() error = %v", err)
+	}
+	if rewrapped[MetadataKeyKeyID] != "v2" || metadata[MetadataKeyKeyID] != "v1" {
+		t.Errorf("Rewrap() key IDs = %s (original %s)", rewrapped[MetadataKeyKeyID], metadata[MetadataKeyKeyID])
+	}
+
+	// Only the new master key is needed to read the rewrapped object
+	newOnly, _ := NewKeyRing("v2", map[string][]byte{"v2": newKey})
+	newClient, _ := NewEnvelope(newOnly)
+	decrypted, err := newClient.Decrypt(bytes.NewReader(ciphertext), rewrapped)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plain) {
+		t.Error("decrypted content mismatch after rewrap")
+	}
+	if _, err := newClient.Decrypt(bytes.NewReader(ciphertext), metadata); err == nil {
+		t.Error("Decrypt() succeeded with a retired key ID")
+	}
+}
@@ -0,0 +1,373 @@
+package cse
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// AlgorithmAESGCMStream indicates chunked AES-GCM encryption in fixed-size segments.
+	AlgorithmAESGCMStream = "AES-GCM-STREAM"
+	// MetadataKeySegmentSize stores the plaintext segment size of streamed objects.
+	MetadataKeySegmentSize = "rustfs-cse-segment-size"
+	// DefaultSegmentSize is the plaintext size of each encrypted segment.
+	DefaultSegmentSize = 64 * 1024
+
+	// maxSegmentSize bounds the segment size accepted from metadata
+	maxSegmentSize = 16 * 1024 * 1024
+	// tagSize is the AES-GCM authentication tag appended to every segment
+	tagSize = 16
+	// saltSize is the size of the random per-object key derivation salt
+	saltSize = 32
+	// keyInfo is the HKDF info string for per-object stream keys
+	keyInfo = "rustfs-cse-stream-v1"
+)
+
+// The stream format splits plaintext into segments of segmentSize bytes, each
+// sealed with AES-GCM under a per-object key derived from the client key (or
+// the envelope data key) and a random salt. Segment nonces are derived from
+// the sequence number and a final-segment flag, so reordering, dropping or
+// truncating segments fails authentication. An empty plaintext is encoded as
+// a single empty final segment.
+
+// EncryptStream returns a reader producing the ciphertext of reader in the
+// segmented stream format, together with the metadata needed to decrypt it.
+// Data is encrypted as it is read, so objects larger than memory are supported.
+func (c *Client) EncryptStream(reader io.Reader) (io.Reader, map[string]string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, nil, err
+	}
+
+	key, metadata, err := c.newDataKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := streamAEAD(key, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata[MetadataKeyAlgorithm] = AlgorithmAESGCMStream
+	metadata[MetadataKeyNonce] = base64.StdEncoding.EncodeToString(salt)
+	metadata[MetadataKeySegmentSize] = strconv.Itoa(DefaultSegmentSize)
+
+	return &encryptReader{
+		aead:        aead,
+		src:         bufio.NewReaderSize(reader, DefaultSegmentSize+1),
+		segmentSize: DefaultSegmentSize,
+	}, metadata, nil
+}
+
+// DecryptStream returns a reader producing the plaintext of a complete
+// stream-format ciphertext. Each segment is authenticated before it is returned.
+func (c *Client) DecryptStream(reader io.Reader, metadata map[string]string) (io.Reader, error) {
+	aead, segmentSize, err := c.streamParams(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		aead:         aead,
+		src:          bufio.NewReaderSize(reader, segmentSize+tagSize+1),
+		segmentSize:  segmentSize,
+		finalSegment: -1,
+	}, nil
+}
+
+// DecryptRange returns a reader producing length plaintext bytes starting at
+// offset. The reader must yield the ciphertext range returned by SegmentRange
+// for the same arguments; encryptedSize is the size of the whole ciphertext.
+func (c *Client) DecryptRange(reader io.Reader, metadata map[string]string, encryptedSize, offset, length int64) (io.Reader, error) {
+	aead, segmentSize, err := c.streamParams(metadata)
+	if err != nil {
+		return nil, err
+	}
+	plainSize, err := PlaintextSize(metadata, encryptedSize)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || length < 0 || offset+length > plainSize {
+		return nil, fmt.Errorf("range %d+%d out of bounds for size %d", offset, length, plainSize)
+	}
+
+	first := offset / int64(segmentSize)
+	dr := &decryptReader{
+		aead:         aead,
+		src:          bufio.NewReaderSize(reader, segmentSize+tagSize+1),
+		segmentSize:  segmentSize,
+		sequence:     first,
+		finalSegment: segmentCount(encryptedSize, segmentSize) - 1,
+	}
+	if _, err := io.CopyN(io.Discard, dr, offset-first*int64(segmentSize)); err != nil {
+		return nil, err
+	}
+	return io.LimitReader(dr, length), nil
+}
+
+// EncryptedSize returns the ciphertext size for plainSize bytes of plaintext
+// in the stream format, or -1 if plainSize is unknown.
+func EncryptedSize(plainSize int64) int64 {
+	if plainSize < 0 {
+		return -1
+	}
+	segments := (plainSize + DefaultSegmentSize - 1) / DefaultSegmentSize
+	if segments == 0 {
+		segments = 1
+	}
+	return plainSize + segments*tagSize
+}
+
+// PlaintextSize returns the plaintext size of a stream-format ciphertext of encryptedSize bytes.
+func PlaintextSize(metadata map[string]string, encryptedSize int64) (int64, error) {
+	segmentSize, err := parseSegmentSize(metadata)
+	if err != nil {
+		return 0, err
+	}
+	if encryptedSize < tagSize {
+		return 0, errors.New("cse ciphertext too short")
+	}
+	segments := segmentCount(encryptedSize, segmentSize)
+	if lastSize := encryptedSize - (segments-1)*int64(segmentSize+tagSize); lastSize < tagSize {
+		return 0, errors.New("cse ciphertext truncated")
+	}
+	return encryptedSize - segments*tagSize, nil
+}
+
+// SegmentRange maps the plaintext range [offset, offset+length) to the
+// inclusive ciphertext byte range covering the segments that contain it.
+func SegmentRange(metadata map[string]string, encryptedSize, offset, length int64) (start, end int64, err error) {
+	segmentSize, err := parseSegmentSize(metadata)
+	if err != nil {
+		return 0, 0, err
+	}
+	if length <= 0 {
+		return 0, 0, errors.New("cse range length must be positive")
+	}
+	encSegment := int64(segmentSize + tagSize)
+	first := offset / int64(segmentSize)
+	last := (offset + length - 1) / int64(segmentSize)
+	start = first * encSegment
+	end = min((last+1)*encSegment, encryptedSize) - 1
+	return start, end, nil
+}
+
+// IsStream reports whether metadata describes a stream-format object.
+func IsStream(metadata map[string]string) bool {
+	return metadata[MetadataKeyAlgorithm] == AlgorithmAESGCMStream
+}
+
+// streamParams builds the segment AEAD and segment size from object metadata
+func (c *Client) streamParams(metadata map[string]string) (cipher.AEAD, int, error) {
+	if !IsStream(metadata) {
+		return nil, 0, errors.New("unsupported or missing cse stream algorithm")
+	}
+	segmentSize, err := parseSegmentSize(metadata)
+	if err != nil {
+		return nil, 0, err
+	}
+	salt, err := base64.StdEncoding.DecodeString(metadata[MetadataKeyNonce])
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(salt) != saltSize {
+		return nil, 0, errors.New("invalid cse nonce size")
+	}
+	key, err := c.dataKey(metadata)
+	if err != nil {
+		return nil, 0, err
+	}
+	aead, err := streamAEAD(key, salt)
+	if err != nil {
+		return nil, 0, err
+	}
+	return aead, segmentSize, nil
+}
+
+// streamAEAD derives the per-object key from key and salt and returns its AES-GCM AEAD
+func streamAEAD(key, salt []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, key, salt, keyInfo, len(key))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// parseSegmentSize reads and validates the segment size from metadata
+func parseSegmentSize(metadata map[string]string) (int, error) {
+	value := metadata[MetadataKeySegmentSize]
+	if value == "" {
+		return 0, errors.New("missing cse segment size")
+	}
+	segmentSize, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cse segment size: %w", err)
+	}
+	if segmentSize <= 0 || segmentSize > maxSegmentSize {
+		return 0, fmt.Errorf("invalid cse segment size %d", segmentSize)
+	}
+	return segmentSize, nil
+}
+
+// segmentCount returns the number of segments in a ciphertext of encryptedSize bytes
+func segmentCount(encryptedSize int64, segmentSize int) int64 {
+	encSegment := int64(segmentSize + tagSize)
+	count := (encryptedSize + encSegment - 1) / encSegment
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+// segmentNonce builds the nonce for a segment from its sequence number and final flag
+func segmentNonce(size int, sequence int64, final bool) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-9:size-1], uint64(sequence))
+	if final {
+		nonce[size-1] = 1
+	}
+	return nonce
+}
+
+// encryptReader seals plaintext segments as they are read
+type encryptReader struct {
+	aead        cipher.AEAD
+	src         *bufio.Reader
+	segmentSize int
+	sequence    int64
+
+	plain []byte
+	buf   []byte
+	out   []byte
+	done  bool
+	err   error
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.sealNext()
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// sealNext reads and seals the next segment, detecting the final one by peeking
+func (r *encryptReader) sealNext() {
+	if r.plain == nil {
+		r.plain = make([]byte, r.segmentSize)
+	}
+	n, err := io.ReadFull(r.src, r.plain)
+	final := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		r.err = err
+		return
+	default:
+		if _, err := r.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			r.err = err
+			return
+		}
+	}
+
+	nonce := segmentNonce(r.aead.NonceSize(), r.sequence, final)
+	r.buf = r.aead.Seal(r.buf[:0], nonce, r.plain[:n], nil)
+	r.out = r.buf
+	r.sequence++
+	r.done = final
+}
+
+// decryptReader opens ciphertext segments as they are read
+type decryptReader struct {
+	aead        cipher.AEAD
+	src         *bufio.Reader
+	segmentSize int
+	sequence    int64
+
+	// finalSegment is the index of the last segment, or -1 to detect it at EOF
+	finalSegment int64
+
+	segment []byte
+	buf     []byte
+	out     []byte
+	done    bool
+	err     error
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.openNext()
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// openNext reads and authenticates the next segment
+func (r *decryptReader) openNext() {
+	if r.segment == nil {
+		r.segment = make([]byte, r.segmentSize+tagSize)
+	}
+	n, err := io.ReadFull(r.src, r.segment)
+	final := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		r.err = err
+		return
+	case r.finalSegment >= 0:
+		final = r.sequence == r.finalSegment
+	default:
+		if _, err := r.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			r.err = err
+			return
+		}
+	}
+	if r.finalSegment >= 0 && final != (r.sequence == r.finalSegment) {
+		r.err = errors.New("cse ciphertext truncated")
+		return
+	}
+
+	nonce := segmentNonce(r.aead.NonceSize(), r.sequence, final)
+	plain, err := r.aead.Open(r.buf[:0], nonce, r.segment[:n], nil)
+	if err != nil {
+		r.err = fmt.Errorf("cse segment %d: %w", r.sequence, err)
+		return
+	}
+	r.buf = plain
+	r.out = plain
+	r.sequence++
+	r.done = final
+}
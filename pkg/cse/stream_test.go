@@ -0,0 +1,114 @@
+package cse
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	client, err := New(key)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+func encryptAll(t *testing.T, client *Client, plain []byte) ([]byte, map[string]string) {
+	t.Helper()
+	reader, metadata, err := client.EncryptStream(bytes.NewReader(plain))
+	if err != nil {
+		t.Fatalf("EncryptStream() error = %v", err)
+	}
+	ciphertext, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read ciphertext: %v", err)
+	}
+	return ciphertext, metadata
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	client := newTestClient(t)
+
+	for _, size := range []int{0, 1, DefaultSegmentSize - 1, DefaultSegmentSize, DefaultSegmentSize + 1, 3*DefaultSegmentSize + 17} {
+		plain := make([]byte, size)
+		_, _ = rand.Read(plain)
+
+		ciphertext, metadata := encryptAll(t, client, plain)
+		if int64(len(ciphertext)) != EncryptedSize(int64(size)) {
+			t.Errorf("size %d: ciphertext length = %d, want %d", size, len(ciphertext), EncryptedSize(int64(size)))
+		}
+		if got, err := PlaintextSize(metadata, int64(len(ciphertext))); err != nil || got != int64(size) {
+			t.Errorf("size %d: PlaintextSize() = %d, %v", size, got, err)
+		}
+
+		decrypted, err := client.Decrypt(bytes.NewReader(ciphertext), metadata)
+		if err != nil {
+			t.Fatalf("size %d: Decrypt() error = %v", size, err)
+		}
+		if !bytes.Equal(decrypted, plain) {
+			t.Errorf("size %d: round trip mismatch", size)
+		}
+	}
+}
+
+func TestStreamTamperDetection(t *testing.T) {
+	client := newTestClient(t)
+	plain := bytes.Repeat([]byte("x"), 2*DefaultSegmentSize+10)
+	ciphertext, metadata := encryptAll(t, client, plain)
+
+	// Dropping the final segment must not decrypt as a shorter object
+	truncated := ciphertext[:2*(DefaultSegmentSize+tagSize)]
+	if _, err := client.Decrypt(bytes.NewReader(truncated), metadata); err == nil {
+		t.Error("Decrypt() accepted a truncated ciphertext")
+	}
+
+	// Swapping segments must fail authentication
+	swapped := append([]byte{}, ciphertext[DefaultSegmentSize+tagSize:2*(DefaultSegmentSize+tagSize)]...)
+	swapped = append(swapped, ciphertext[:DefaultSegmentSize+tagSize]...)
+	swapped = append(swapped, ciphertext[2*(DefaultSegmentSize+tagSize):]...)
+	if _, err := client.Decrypt(bytes.NewReader(swapped), metadata); err == nil {
+		t.Error("Decrypt() accepted reordered segments")
+	}
+}
+
+func TestDecryptRange(t *testing.T) {
+	client := newTestClient(t)
+	plain := make([]byte, 3*DefaultSegmentSize+100)
+	_, _ = rand.Read(plain)
+	ciphertext, metadata := encryptAll(t, client, plain)
+	encryptedSize := int64(len(ciphertext))
+
+	tests := []struct {
+		offset, length int64
+	}{
+		{0, 10},
+		{DefaultSegmentSize - 5, 10},
+		{DefaultSegmentSize, DefaultSegmentSize},
+		{3 * DefaultSegmentSize, 100},
+		{5, int64(len(plain)) - 5},
+	}
+	for _, tt := range tests {
+		start, end, err := SegmentRange(metadata, encryptedSize, tt.offset, tt.length)
+		if err != nil {
+			t.Fatalf("SegmentRange() error = %v", err)
+		}
+		reader, err := client.DecryptRange(bytes.NewReader(ciphertext[start:end+1]), metadata, encryptedSize, tt.offset, tt.length)
+		if err != nil {
+			t.Fatalf("DecryptRange(%d, %d) error = %v", tt.offset, tt.length, err)
+		}
+		got, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("DecryptRange(%d, %d) read error = %v", tt.offset, tt.length, err)
+		}
+		if !bytes.Equal(got, plain[tt.offset:tt.offset+tt.length]) {
+			t.Errorf("DecryptRange(%d, %d) content mismatch", tt.offset, tt.length)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package faultinject provides an http.RoundTripper that injects storage
+// failures — latency, connection resets, error responses, truncated and
+// stalled bodies — into matching requests, to test how client code copes
+// with them. It plugs into the client through Options.Transport.
+package faultinject
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/internal/core"
+)
+
+// Rule injects faults into the requests it matches. Empty match fields
+// match any request.
+type Rule struct {
+	// Bucket matches the bucket name
+	Bucket string
+	// KeyPrefix matches object keys starting with it; bucket requests never
+	// match a non-empty prefix
+	KeyPrefix string
+	// Method matches the HTTP method
+	Method string
+	// Operation matches the S3 operation, e.g. PutObject or UploadPart
+	Operation string
+
+	// Nth injects into the Nth matching request only, counting from 1
+	Nth int
+	// Probability of injecting into a matching request, in (0, 1], when Nth
+	// is zero
+	// Default: 1
+	Probability float64
+	// Times caps the number of injections; zero means no limit
+	Times int
+
+	// Faults are applied in order, e.g. Latency then SlowDown
+	Faults []Fault
+}
+
+// matches reports whether the rule applies to a request
+func (r *Rule) matches(method string, info core.RequestInfo) bool {
+	return (r.Bucket == "" || r.Bucket == info.BucketName) &&
+		(r.KeyPrefix == "" || (info.ObjectName != "" && strings.HasPrefix(info.ObjectName, r.KeyPrefix))) &&
+		(r.Method == "" || strings.EqualFold(r.Method, method)) &&
+		(r.Operation == "" || r.Operation == info.Operation)
+}
+
+// Config configures a Transport
+type Config struct {
+	// Rules are evaluated in order; the first that fires injects its faults
+	Rules []Rule
+	// Transport sends the requests
+	// Default: http.DefaultTransport
+	Transport http.RoundTripper
+	// Seed makes probabilistic injection reproducible
+	// Default: a random seed
+	Seed int64
+}
+
+// Transport is an http.RoundTripper that injects faults into requests
+// matching its rules. Operations, buckets and keys are known for requests
+// sent by the client; other requests are matched by path-style URL and
+// have no operation. It is safe for concurrent use.
+//
+// Example:
+//
+//	tr := faultinject.New(faultinject.Config{Rules: []faultinject.Rule{
+//		{Operation: "UploadPart", Nth: 2, Faults: []faultinject.Fault{faultinject.Reset()}},
+//	}})
+//	client, err := rustfs.New(endpoint, &rustfs.Options{Transport: tr, ...})
+type Transport struct {
+	next http.RoundTripper
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	rules  []Rule
+	counts []ruleCount
+}
+
+// ruleCount counts the requests a rule matched and injected into
+type ruleCount struct {
+	matched  int
+	injected int
+}
+
+// New returns a Transport injecting faults by config.Rules
+func New(config Config) *Transport {
+	if config.Transport == nil {
+		config.Transport = http.DefaultTransport
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	t := &Transport{
+		next: config.Transport,
+		rand: rand.New(rand.NewSource(config.Seed)),
+	}
+	t.SetRules(config.Rules...)
+	return t
+}
+
+// SetRules replaces the rules and resets their counts
+func (t *Transport) SetRules(rules ...Rule) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rules = append([]Rule(nil), rules...)
+	t.counts = make([]ruleCount, len(rules))
+}
+
+// Injected returns the number of requests each rule injected faults into
+func (t *Transport) Injected() []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	injected := make([]int, len(t.counts))
+	for i, c := range t.counts {
+		injected[i] = c.injected
+	}
+	return injected
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	faults := t.faults(req)
+	next := t.next
+	for i := len(faults) - 1; i >= 0; i-- {
+		fault, rest := faults[i], next
+		next = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return fault(req, rest)
+		})
+	}
+	return next.RoundTrip(req)
+}
+
+// faults returns the faults to inject into req. Every matching rule counts
+// the request; the first that fires wins.
+func (t *Transport) faults(req *http.Request) []Fault {
+	info, ok := core.RequestInfoFromContext(req.Context())
+	if !ok {
+		info = pathStyleInfo(req)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var faults []Fault
+	for i := range t.rules {
+		rule, count := &t.rules[i], &t.counts[i]
+		if !rule.matches(req.Method, info) {
+			continue
+		}
+		count.matched++
+		if faults != nil || (rule.Times > 0 && count.injected >= rule.Times) || !t.fires(rule, count.matched) {
+			continue
+		}
+		count.injected++
+		faults = rule.Faults
+		if faults == nil {
+			faults = []Fault{}
+		}
+	}
+	return faults
+}
+
+// fires decides whether a rule injects into its matched-th request
+func (t *Transport) fires(rule *Rule, matched int) bool {
+	if rule.Nth > 0 {
+		return matched == rule.Nth
+	}
+	return rule.Probability <= 0 || rule.Probability >= 1 || t.rand.Float64() < rule.Probability
+}
+
+// pathStyleInfo derives the bucket and key of a request not sent by the
+// client, such as a presigned URL, from its path
+func pathStyleInfo(req *http.Request) core.RequestInfo {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	return core.RequestInfo{BucketName: bucket, ObjectName: key}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,202 @@
+// Package faultinject pkg/faultinject/faultinject_test.go
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	rustfs "github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/rustfstest"
+)
+
+// newClient returns a client whose requests to a fresh server go through a
+// fault-injecting transport, and a bucket on the server
+func newClient(t *testing.T, rules ...Rule) (*rustfs.Client, *Transport) {
+	t.Helper()
+	server := rustfstest.NewServer(rustfstest.Config{})
+	t.Cleanup(server.Close)
+
+	tr := New(Config{Transport: server.Options().Transport, Seed: 1})
+	opts := server.Options()
+	opts.Transport = tr
+	opts.MaxRetries = 3
+	client, err := rustfs.New(server.Endpoint(), opts)
+	if err != nil {
+		t.Fatalf("rustfs.New() error = %v", err)
+	}
+	if err := client.Bucket().Create(context.Background(), "bucket"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	tr.SetRules(rules...)
+	return client, tr
+}
+
+func TestRetriedFaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault Fault
+	}{
+		{"slow down", SlowDown()},
+		{"internal error", InternalError()},
+		{"service unavailable", ServiceUnavailable()},
+		{"connection reset", Reset()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, tr := newClient(t, Rule{Operation: "PutObject", Nth: 1, Faults: []Fault{tt.fault}})
+			ctx := context.Background()
+
+			// A seekable body is rewound and sent again
+			if _, err := client.Object().Put(ctx, "bucket", "key", strings.NewReader("data"), 4); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if got := tr.Injected(); got[0] != 1 {
+				t.Errorf("Injected() = %v, want [1]", got)
+			}
+			reader, _, err := client.Object().Get(ctx, "bucket", "key")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			defer reader.Close()
+			if data, _ := io.ReadAll(reader); string(data) != "data" {
+				t.Errorf("Get() = %q, want %q", data, "data")
+			}
+		})
+	}
+}
+
+func TestUnreplayableBody(t *testing.T) {
+	client, _ := newClient(t, Rule{Operation: "PutObject", Faults: []Fault{Reset()}})
+
+	// A body that cannot be rewound is not retried
+	body := io.MultiReader(strings.NewReader("data"))
+	_, err := client.Object().Put(context.Background(), "bucket", "key", body, 4)
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Put() error = %v, want a connection reset", err)
+	}
+}
+
+func TestBodyFaults(t *testing.T) {
+	client, tr := newClient(t)
+	ctx := context.Background()
+	if _, err := client.Object().Put(ctx, "bucket", "key", strings.NewReader("0123456789"), 10); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tr.SetRules(Rule{Operation: "GetObject", Faults: []Fault{TruncateBody(4)}})
+	reader, _, err := client.Object().Get(ctx, "bucket", "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "0123" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("reading a truncated body = %q, %v", data, err)
+	}
+
+	tr.SetRules(Rule{Operation: "GetObject", Faults: []Fault{StallBody(2, 0)}})
+	stallCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	reader, _, err = client.Object().Get(stallCtx, "bucket", "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err = io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "01" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("reading a stalled body = %q, %v", data, err)
+	}
+
+	tr.SetRules(Rule{Operation: "GetObject", Faults: []Fault{Latency(30 * time.Millisecond), StallBody(5, 10*time.Millisecond)}})
+	start := time.Now()
+	reader, _, err = client.Object().Get(ctx, "bucket", "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err = io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "0123456789" || err != nil {
+		t.Errorf("reading a delayed body = %q, %v", data, err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("delayed Get() took %v, want at least 40ms", elapsed)
+	}
+}
+
+// staticTransport answers every request with its body
+type staticTransport string
+
+func (body staticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body))), Request: req}, nil
+}
+
+func TestTruncatedBodyStaysTruncated(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://rustfs.test/bucket/key", nil)
+	resp, err := TruncateBody(4)(req, staticTransport("0123456789"))
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if string(data) != "0123" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("reading a truncated body = %q, %v", data, err)
+	}
+	// Retried reads must not pass through to the rest of the body
+	buf := make([]byte, 16)
+	for i := 0; i < 2; i++ {
+		if n, err := resp.Body.Read(buf); n != 0 || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Read() after truncation = %q, %v", buf[:n], err)
+		}
+	}
+}
+
+func TestRuleMatching(t *testing.T) {
+	var sent int
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	tr := New(Config{Transport: upstream, Seed: 1, Rules: []Rule{
+		{Bucket: "other", Faults: []Fault{Reset()}},
+		{KeyPrefix: "logs/", Method: http.MethodPut, Times: 2, Faults: []Fault{SlowDown()}},
+		{Bucket: "bucket", Probability: 0.5, Faults: []Fault{InternalError()}},
+	}})
+
+	do := func(method, path string) int {
+		req, _ := http.NewRequest(method, "http://host"+path, nil)
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip(%s %s) error = %v", method, path, err)
+		}
+		return resp.StatusCode
+	}
+
+	for i := range 2 {
+		if got := do(http.MethodPut, "/bucket/logs/1"); got != http.StatusServiceUnavailable {
+			t.Errorf("PUT logs/1 #%d = %d, want 503", i+1, got)
+		}
+	}
+	// Times is exhausted, so the request is left to the next rule
+	do(http.MethodPut, "/bucket/logs/1")
+	for range 200 {
+		do(http.MethodGet, "/bucket/data")
+	}
+
+	injected := tr.Injected()
+	if injected[0] != 0 || injected[1] != 2 {
+		t.Errorf("Injected() = %v, want no injections for other buckets and 2 for logs/", injected)
+	}
+	if injected[2] < 70 || injected[2] > 130 {
+		t.Errorf("Injected() = %d of about 200 requests at probability 0.5", injected[2])
+	}
+	if sent != 203-injected[1]-injected[2] {
+		t.Errorf("sent %d requests upstream, want %d", sent, 203-injected[1]-injected[2])
+	}
+}
@@ -0,0 +1,187 @@
+// Package faultinject pkg/faultinject/faults.go
+package faultinject
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Fault injects a failure into a round trip. next sends the request on.
+type Fault func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// Latency delays the request by d, or until its context is done, before
+// sending it
+func Latency(d time.Duration) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		if err := sleep(req.Context(), d); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+		return next.RoundTrip(req)
+	}
+}
+
+// Reset fails the request with a connection reset, without sending it
+func Reset() Fault {
+	return func(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+}
+
+// ErrorResponse answers the request with an S3 error response, without
+// sending it
+func ErrorResponse(status int, code string) Fault {
+	return func(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+		closeBody(req)
+		return errorResponse(req, status, code), nil
+	}
+}
+
+// InternalError answers the request with 500 InternalError
+func InternalError() Fault {
+	return ErrorResponse(http.StatusInternalServerError, "InternalError")
+}
+
+// ServiceUnavailable answers the request with 503 ServiceUnavailable
+func ServiceUnavailable() Fault {
+	return ErrorResponse(http.StatusServiceUnavailable, "ServiceUnavailable")
+}
+
+// SlowDown answers the request with 503 SlowDown, the throttling error
+func SlowDown() Fault {
+	return ErrorResponse(http.StatusServiceUnavailable, "SlowDown")
+}
+
+// TruncateBody sends the request and cuts the response body after n bytes
+// with io.ErrUnexpectedEOF
+func TruncateBody(n int64) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = &faultyBody{body: resp.Body, remaining: n, fail: func() error {
+			return io.ErrUnexpectedEOF
+		}}
+		return resp, nil
+	}
+}
+
+// StallBody sends the request and stalls the response body after n bytes
+// for d, or until the request's context is done when d is zero. Reads then
+// continue, or fail with the context's error.
+func StallBody(n int64, d time.Duration) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		ctx := req.Context()
+		resp.Body = &faultyBody{body: resp.Body, remaining: n, fail: func() error {
+			if d == 0 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return sleep(ctx, d)
+		}}
+		return resp, nil
+	}
+}
+
+// faultyBody reads remaining bytes from body, then calls fail once. An error
+// from fail is returned by every later Read; reading goes on after fail
+// returns nil.
+type faultyBody struct {
+	body      io.ReadCloser
+	remaining int64
+	fail      func() error
+	failed    bool
+	err       error
+}
+
+func (b *faultyBody) Read(p []byte) (int, error) {
+	if !b.failed && b.remaining <= 0 {
+		b.failed = true
+		b.err = b.fail()
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	if !b.failed && int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *faultyBody) Close() error {
+	return b.body.Close()
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeBody closes the body of a request that is not sent, as
+// http.RoundTripper requires
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// s3Error is the body of an injected error response
+type s3Error struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+// errorResponse builds an S3 error response to req
+func errorResponse(req *http.Request, status int, code string) *http.Response {
+	body, _ := xml.Marshal(s3Error{
+		Code:      code,
+		Message:   "Injected fault: " + http.StatusText(status),
+		Resource:  req.URL.Path,
+		RequestID: "faultinject",
+	})
+	body = append([]byte(xml.Header), body...)
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/xml")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	header.Set("x-amz-request-id", "faultinject")
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(string(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,161 @@
+// Package hedge decides when to hedge a slow read: once the first request
+// has waited longer than a percentile of recent read latencies, an identical
+// request is sent and whichever answers first is used.
+package hedge
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultPercentile is the latency percentile after which reads are hedged
+	DefaultPercentile = 0.95
+	// DefaultInitialDelay is the hedge delay until enough latencies are known
+	DefaultInitialDelay = 100 * time.Millisecond
+	// DefaultMinDelay is the shortest hedge delay
+	DefaultMinDelay = 5 * time.Millisecond
+	// DefaultMaxDelay is the longest hedge delay
+	DefaultMaxDelay = 2 * time.Second
+	// DefaultWindow is the number of recent latencies the delay is based on
+	DefaultWindow = 1000
+	// DefaultMinSamples is the number of latencies needed before the
+	// percentile replaces the initial delay
+	DefaultMinSamples = 20
+)
+
+// Config configures a Hedger. Zero values select the defaults.
+type Config struct {
+	// Percentile of recent time-to-headers latencies, in (0, 1), after
+	// which a second request is sent
+	Percentile float64
+	// InitialDelay is used until MinSamples latencies have been observed
+	InitialDelay time.Duration
+	// MinDelay and MaxDelay bound the hedge delay
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// Window is the number of recent latencies kept
+	Window int
+	// MinSamples is the number of latencies needed to use the percentile
+	MinSamples int
+}
+
+// Stats counts the reads a Hedger was consulted for
+type Stats struct {
+	// Requests is the number of reads eligible for hedging
+	Requests int64
+	// Hedged is the number of reads for which a second request was sent
+	Hedged int64
+	// Won is the number of hedged reads answered first by the second request
+	Won int64
+}
+
+// HedgeRate returns the fraction of eligible reads that were hedged
+func (s Stats) HedgeRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Hedged) / float64(s.Requests)
+}
+
+// Hedger tracks read latencies to compute the hedge delay, and counts
+// hedged reads. It is safe for concurrent use and meant to be shared by all
+// requests of a client.
+type Hedger struct {
+	config Config
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	// delay caches the percentile, recomputed after every stale new samples
+	delay time.Duration
+	stale int
+
+	requests atomic.Int64
+	hedged   atomic.Int64
+	won      atomic.Int64
+}
+
+// New returns a Hedger without latency samples
+func New(config Config) *Hedger {
+	if config.Percentile <= 0 || config.Percentile >= 1 {
+		config.Percentile = DefaultPercentile
+	}
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = DefaultInitialDelay
+	}
+	if config.MinDelay <= 0 {
+		config.MinDelay = DefaultMinDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultMaxDelay
+	}
+	config.MaxDelay = max(config.MaxDelay, config.MinDelay)
+	if config.Window <= 0 {
+		config.Window = DefaultWindow
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = DefaultMinSamples
+	}
+	config.MinSamples = min(config.MinSamples, config.Window)
+	return &Hedger{config: config, samples: make([]time.Duration, 0, config.Window)}
+}
+
+// Delay returns how long to wait for the first request's headers before
+// hedging
+func (h *Hedger) Delay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < h.config.MinSamples {
+		return min(max(h.config.InitialDelay, h.config.MinDelay), h.config.MaxDelay)
+	}
+	if h.delay == 0 || h.stale >= recomputeEvery {
+		sorted := slices.Clone(h.samples)
+		slices.Sort(sorted)
+		h.delay = sorted[int(h.config.Percentile*float64(len(sorted)-1))]
+		h.stale = 0
+	}
+	return min(max(h.delay, h.config.MinDelay), h.config.MaxDelay)
+}
+
+// recomputeEvery is the number of new samples after which the cached
+// percentile is recomputed
+const recomputeEvery = 16
+
+// Observe records the time a read took to return headers
+func (h *Hedger) Observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < h.config.Window {
+		h.samples = append(h.samples, latency)
+	} else {
+		h.samples[h.next] = latency
+		h.next = (h.next + 1) % h.config.Window
+	}
+	h.stale++
+}
+
+// Record counts a read eligible for hedging, whether it was hedged and
+// whether the hedge answered first
+func (h *Hedger) Record(hedged, won bool) {
+	h.requests.Add(1)
+	if hedged {
+		h.hedged.Add(1)
+	}
+	if won {
+		h.won.Add(1)
+	}
+}
+
+// Stats returns the hedging counters
+func (h *Hedger) Stats() Stats {
+	return Stats{
+		Requests: h.requests.Load(),
+		Hedged:   h.hedged.Load(),
+		Won:      h.won.Load(),
+	}
+}
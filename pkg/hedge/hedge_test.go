@@ -0,0 +1,60 @@
+package hedge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHedgerDelay(t *testing.T) {
+	h := New(Config{Percentile: 0.9, InitialDelay: 50 * time.Millisecond, MinSamples: 10, MaxDelay: time.Second})
+	if got := h.Delay(); got != 50*time.Millisecond {
+		t.Errorf("Delay() without samples = %v, want the initial delay", got)
+	}
+
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if got := h.Delay(); got < 85*time.Millisecond || got > 95*time.Millisecond {
+		t.Errorf("Delay() = %v, want about the 90th percentile", got)
+	}
+
+	for i := 0; i < 200; i++ {
+		h.Observe(time.Minute)
+	}
+	if got := h.Delay(); got != time.Second {
+		t.Errorf("Delay() = %v, want MaxDelay", got)
+	}
+}
+
+func TestHedgerWindow(t *testing.T) {
+	h := New(Config{Window: 20, MinSamples: 20, MinDelay: time.Millisecond})
+	for i := 0; i < 20; i++ {
+		h.Observe(time.Second)
+	}
+	// Newer samples replace the oldest ones
+	for i := 0; i < 20; i++ {
+		h.Observe(2 * time.Millisecond)
+	}
+	if got := h.Delay(); got != 2*time.Millisecond {
+		t.Errorf("Delay() = %v, want 2ms from the recent window", got)
+	}
+}
+
+func TestHedgerStats(t *testing.T) {
+	h := New(Config{})
+	h.Record(false, false)
+	h.Record(true, false)
+	h.Record(true, true)
+	h.Record(false, false)
+
+	stats := h.Stats()
+	if stats.Requests != 4 || stats.Hedged != 2 || stats.Won != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if rate := stats.HedgeRate(); rate != 0.5 {
+		t.Errorf("HedgeRate() = %v, want 0.5", rate)
+	}
+	if (Stats{}).HedgeRate() != 0 {
+		t.Error("HedgeRate() of no requests should be 0")
+	}
+}
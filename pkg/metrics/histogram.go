@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultBounds are the histogram bucket upper bounds used by NewMemory:
+// powers of two from 1ms to about 65s
+var DefaultBounds = func() []time.Duration {
+	bounds := make([]time.Duration, 0, 17)
+	for d := time.Millisecond; d <= 65536*time.Millisecond; d *= 2 {
+		bounds = append(bounds, d)
+	}
+	return bounds
+}()
+
+// Histogram counts durations in buckets with fixed upper bounds
+type Histogram struct {
+	// Bounds are the inclusive upper bounds of the buckets, in increasing order
+	Bounds []time.Duration
+	// Counts has one entry per bound plus a final overflow bucket
+	Counts []int64
+	// Count and Sum are the number and total of the observed durations
+	Count int64
+	Sum   time.Duration
+	// Min and Max are the extreme observed durations
+	Min time.Duration
+	Max time.Duration
+}
+
+// newHistogram returns an empty histogram with the given bounds
+func newHistogram(bounds []time.Duration) Histogram {
+	return Histogram{Bounds: bounds, Counts: make([]int64, len(bounds)+1)}
+}
+
+// Observe adds a duration to the histogram
+func (h *Histogram) Observe(d time.Duration) {
+	i := sort.Search(len(h.Bounds), func(i int) bool { return d <= h.Bounds[i] })
+	h.Counts[i]++
+	if h.Count == 0 || d < h.Min {
+		h.Min = d
+	}
+	if d > h.Max {
+		h.Max = d
+	}
+	h.Count++
+	h.Sum += d
+}
+
+// Merge adds the observations of other, which must have the same bounds
+func (h *Histogram) Merge(other Histogram) {
+	if other.Count == 0 {
+		return
+	}
+	if h.Count == 0 || other.Min < h.Min {
+		h.Min = other.Min
+	}
+	if other.Max > h.Max {
+		h.Max = other.Max
+	}
+	for i := range h.Counts {
+		h.Counts[i] += other.Counts[i]
+	}
+	h.Count += other.Count
+	h.Sum += other.Sum
+}
+
+// Mean returns the average observed duration
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile estimates the q-quantile (0 <= q <= 1) of the observed
+// durations by interpolating within the bucket that contains it
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+
+	rank := q * float64(h.Count)
+	var cumulative int64
+	for i, count := range h.Counts {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		lower, upper := h.Min, h.Max
+		if i > 0 {
+			lower = max(lower, h.Bounds[i-1])
+		}
+		if i < len(h.Bounds) {
+			upper = min(upper, h.Bounds[i])
+		}
+		fraction := (rank - float64(cumulative)) / float64(count)
+		return lower + time.Duration(fraction*float64(upper-lower))
+	}
+	return h.Max
+}
+
+// clone returns a deep copy of the histogram
+func (h Histogram) clone() Histogram {
+	h.Counts = append([]int64(nil), h.Counts...)
+	return h
+}
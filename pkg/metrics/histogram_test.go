@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	h := newHistogram([]time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second})
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	if h.Count != 100 || h.Min != time.Millisecond || h.Max != 100*time.Millisecond {
+		t.Fatalf("Count = %d, Min = %v, Max = %v", h.Count, h.Min, h.Max)
+	}
+	if got := h.Counts; got[0] != 10 || got[1] != 90 || got[2] != 0 || got[3] != 0 {
+		t.Errorf("Counts = %v, want [10 90 0 0]", got)
+	}
+	if mean := h.Mean(); mean != 50500*time.Microsecond {
+		t.Errorf("Mean() = %v, want 50.5ms", mean)
+	}
+
+	// Estimates fall within the bucket holding the quantile
+	if p05 := h.Quantile(0.05); p05 < time.Millisecond || p05 > 10*time.Millisecond {
+		t.Errorf("Quantile(0.05) = %v", p05)
+	}
+	if p99 := h.Quantile(0.99); p99 < 90*time.Millisecond || p99 > 100*time.Millisecond {
+		t.Errorf("Quantile(0.99) = %v", p99)
+	}
+	if p100 := h.Quantile(1); p100 != 100*time.Millisecond {
+		t.Errorf("Quantile(1) = %v, want the maximum", p100)
+	}
+
+	var empty Histogram
+	if empty.Quantile(0.5) != 0 || empty.Mean() != 0 {
+		t.Error("empty histogram should report zero")
+	}
+}
+
+func TestHistogramOverflowAndMerge(t *testing.T) {
+	bounds := []time.Duration{time.Millisecond}
+	a, b := newHistogram(bounds), newHistogram(bounds)
+	a.Observe(500 * time.Microsecond)
+	b.Observe(3 * time.Second)
+
+	a.Merge(b)
+	if a.Count != 2 || a.Counts[1] != 1 || a.Max != 3*time.Second || a.Min != 500*time.Microsecond {
+		t.Fatalf("merged histogram = %+v", a)
+	}
+	if got := a.Quantile(1); got != 3*time.Second {
+		t.Errorf("Quantile(1) = %v, want 3s", got)
+	}
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Key identifies a series of requests in a Memory collector
+type Key struct {
+	Operation   string
+	Method      string
+	Bucket      string
+	StatusClass string
+	ErrorCode   string
+}
+
+// Stats aggregates the requests of a series
+type Stats struct {
+	Key
+
+	// Requests is the number of requests and Failed the number that
+	// ended with an error or error response
+	Requests int64
+	Failed   int64
+	// Retries is the number of attempts beyond the first
+	Retries int64
+	// Hedged is the number of requests whose final attempt was hedged
+	Hedged int64
+
+	RequestBytes  int64
+	ResponseBytes int64
+
+	// Latency is the distribution of request durations, TimeToFirstByte
+	// that of the final attempts' time to first byte
+	Latency         Histogram
+	TimeToFirstByte Histogram
+}
+
+// merge adds the requests of other to s
+func (s *Stats) merge(other Stats) {
+	s.Requests += other.Requests
+	s.Failed += other.Failed
+	s.Retries += other.Retries
+	s.Hedged += other.Hedged
+	s.RequestBytes += other.RequestBytes
+	s.ResponseBytes += other.ResponseBytes
+	s.Latency.Merge(other.Latency)
+	s.TimeToFirstByte.Merge(other.TimeToFirstByte)
+}
+
+// Memory is a Collector that aggregates requests in memory per operation,
+// method, bucket, status class and error code
+type Memory struct {
+	mu     sync.Mutex
+	bounds []time.Duration
+	series map[Key]*Stats
+}
+
+// NewMemory returns an empty Memory collector whose histograms use bounds,
+// or DefaultBounds if none are given
+func NewMemory(bounds ...time.Duration) *Memory {
+	if len(bounds) == 0 {
+		bounds = DefaultBounds
+	} else {
+		bounds = append([]time.Duration(nil), bounds...)
+		sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+	}
+	return &Memory{bounds: bounds, series: make(map[Key]*Stats)}
+}
+
+// RecordRequest implements Collector
+func (m *Memory) RecordRequest(r Request) {
+	key := Key{
+		Operation:   r.Operation,
+		Method:      r.Method,
+		Bucket:      r.Bucket,
+		StatusClass: r.StatusClass(),
+		ErrorCode:   r.ErrorCode,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.series[key]
+	if !ok {
+		stats = m.newStats(key)
+		m.series[key] = stats
+	}
+	stats.Requests++
+	if r.Failed() {
+		stats.Failed++
+	}
+	if r.Attempts > 1 {
+		stats.Retries += int64(r.Attempts - 1)
+	}
+	if r.Hedged {
+		stats.Hedged++
+	}
+	stats.RequestBytes += max(r.RequestBytes, 0)
+	stats.ResponseBytes += max(r.ResponseBytes, 0)
+	stats.Latency.Observe(r.Duration)
+	if r.TimeToFirstByte > 0 {
+		stats.TimeToFirstByte.Observe(r.TimeToFirstByte)
+	}
+}
+
+// Snapshot returns a copy of all series, sorted by key
+func (m *Memory) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := Snapshot{bounds: m.bounds, Series: make([]Stats, 0, len(m.series))}
+	for _, stats := range m.series {
+		copied := *stats
+		copied.Latency = stats.Latency.clone()
+		copied.TimeToFirstByte = stats.TimeToFirstByte.clone()
+		snapshot.Series = append(snapshot.Series, copied)
+	}
+	sort.Slice(snapshot.Series, func(i, j int) bool {
+		a, b := snapshot.Series[i].Key, snapshot.Series[j].Key
+		if a.Bucket != b.Bucket {
+			return a.Bucket < b.Bucket
+		}
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.StatusClass != b.StatusClass {
+			return a.StatusClass < b.StatusClass
+		}
+		return a.ErrorCode < b.ErrorCode
+	})
+	return snapshot
+}
+
+// Reset discards all series
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.series = make(map[Key]*Stats)
+}
+
+func (m *Memory) newStats(key Key) *Stats {
+	return &Stats{
+		Key:             key,
+		Latency:         newHistogram(m.bounds),
+		TimeToFirstByte: newHistogram(m.bounds),
+	}
+}
+
+// Snapshot is a point-in-time copy of a Memory collector
+type Snapshot struct {
+	Series []Stats
+
+	bounds []time.Duration
+}
+
+// Aggregate merges the series whose keys match into one Stats. A nil match
+// merges every series. The Key of the result is left empty.
+//
+// For example, the SlowDown responses of a bucket:
+//
+//	snapshot.Aggregate(func(k metrics.Key) bool {
+//		return k.Bucket == "photos" && k.ErrorCode == "SlowDown"
+//	}).Requests
+func (s Snapshot) Aggregate(match func(Key) bool) Stats {
+	total := Stats{
+		Latency:         newHistogram(s.bounds),
+		TimeToFirstByte: newHistogram(s.bounds),
+	}
+	for _, stats := range s.Series {
+		if match == nil || match(stats.Key) {
+			total.merge(stats)
+		}
+	}
+	return total
+}
+
+// ByBucket aggregates the series of each bucket
+func (s Snapshot) ByBucket() map[string]Stats {
+	buckets := make(map[string]Stats)
+	for _, stats := range s.Series {
+		if _, ok := buckets[stats.Bucket]; ok {
+			continue
+		}
+		bucket := stats.Bucket
+		buckets[bucket] = s.Aggregate(func(k Key) bool { return k.Bucket == bucket })
+	}
+	return buckets
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemorySnapshot(t *testing.T) {
+	m := NewMemory()
+
+	for i := 0; i < 3; i++ {
+		m.RecordRequest(Request{
+			Operation: "PutObject", Method: "PUT", Bucket: "photos", StatusCode: 200,
+			Attempts: 1, RequestBytes: 100, Duration: 20 * time.Millisecond, TimeToFirstByte: 5 * time.Millisecond,
+		})
+	}
+	m.RecordRequest(Request{
+		Operation: "PutObject", Method: "PUT", Bucket: "photos", StatusCode: 503, ErrorCode: "SlowDown",
+		Attempts: 4, RequestBytes: 100, Duration: time.Second,
+	})
+	m.RecordRequest(Request{
+		Operation: "GetObject", Method: "GET", Bucket: "logs", Attempts: 2, Err: errors.New("reset"),
+		Duration: time.Millisecond,
+	})
+
+	snapshot := m.Snapshot()
+	if len(snapshot.Series) != 3 {
+		t.Fatalf("Snapshot has %d series, want 3", len(snapshot.Series))
+	}
+	if first := snapshot.Series[0].Key; first.Bucket != "logs" || first.StatusClass != "error" {
+		t.Errorf("first series key = %+v", first)
+	}
+
+	slowDown := snapshot.Aggregate(func(k Key) bool { return k.Bucket == "photos" && k.ErrorCode == "SlowDown" })
+	if slowDown.Requests != 1 || slowDown.Failed != 1 || slowDown.Retries != 3 {
+		t.Errorf("SlowDown stats = %+v", slowDown)
+	}
+
+	photos := snapshot.ByBucket()["photos"]
+	if photos.Requests != 4 || photos.RequestBytes != 400 || photos.TimeToFirstByte.Count != 3 {
+		t.Errorf("photos stats = %+v", photos)
+	}
+	if p99 := photos.Latency.Quantile(0.99); p99 < 512*time.Millisecond || p99 > time.Second {
+		t.Errorf("photos p99 = %v", p99)
+	}
+
+	total := snapshot.Aggregate(nil)
+	if total.Requests != 5 || total.Failed != 2 {
+		t.Errorf("total = %d requests, %d failed", total.Requests, total.Failed)
+	}
+
+	// Snapshots are copies
+	m.Reset()
+	if len(m.Snapshot().Series) != 0 || snapshot.Series[1].Latency.Count != 3 {
+		t.Error("Reset should clear the collector but not earlier snapshots")
+	}
+}
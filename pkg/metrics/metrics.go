@@ -0,0 +1,59 @@
+// Package metrics defines the collector the SDK reports request metrics to,
+// and an in-memory implementation with latency histograms.
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// Request describes a completed request, including all of its attempts
+type Request struct {
+	// Operation is the S3 operation, e.g. PutObject
+	Operation string
+	// Method is the HTTP method
+	Method string
+	// Bucket is the bucket addressed, empty for service-level requests
+	Bucket string
+	// StatusCode is the HTTP status of the final attempt, 0 if none was received
+	StatusCode int
+	// ErrorCode is the S3 error code of the final attempt, if it failed
+	ErrorCode string
+	// Err is the transport error of the final attempt, if any
+	Err error
+	// Attempts is the number of attempts made
+	Attempts int
+	// RequestBytes is the size of the request body
+	RequestBytes int64
+	// ResponseBytes is the size of the response body announced by the server
+	ResponseBytes int64
+	// Duration is the time from the first attempt until the final response
+	// headers, including retries and backoff
+	Duration time.Duration
+	// TimeToFirstByte is the time from sending the final attempt until the
+	// first response byte
+	TimeToFirstByte time.Duration
+	// Hedged reports whether a second request was sent for the final attempt
+	// because the first was slow to answer
+	Hedged bool
+}
+
+// StatusClass returns the class of the status code, e.g. "2xx", or "error"
+// if no response was received
+func (r Request) StatusClass() string {
+	if r.StatusCode < 100 || r.StatusCode > 599 {
+		return "error"
+	}
+	return strconv.Itoa(r.StatusCode/100) + "xx"
+}
+
+// Failed reports whether the request ended with an error or an error response
+func (r Request) Failed() bool {
+	return r.Err != nil || r.ErrorCode != "" || r.StatusCode >= 400 || r.StatusCode == 0
+}
+
+// Collector receives the metrics of every request the SDK makes.
+// Implementations must be safe for concurrent use.
+type Collector interface {
+	RecordRequest(r Request)
+}
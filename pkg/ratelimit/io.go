@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+)
+
+// NewReader returns a reader that takes a token from l for every byte read
+// from r, pacing reads to the limiter's rate
+func NewReader(ctx context.Context, r io.Reader, l *Limiter) io.Reader {
+	return &reader{ctx: ctx, r: r, l: l}
+}
+
+// NewReadCloser is NewReader for bodies that must be closed
+func NewReadCloser(ctx context.Context, rc io.ReadCloser, l *Limiter) io.ReadCloser {
+	return readCloser{reader: &reader{ctx: ctx, r: rc, l: l}, Closer: rc}
+}
+
+// NewWriter returns a writer that takes a token from l for every byte
+// before writing it to w
+func NewWriter(ctx context.Context, w io.Writer, l *Limiter) io.Writer {
+	return &writer{ctx: ctx, w: w, l: l}
+}
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	// Read at most one burst at a time so that waits stay short
+	if burst := r.l.Burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.r.Read(p)
+	if waitErr := r.l.WaitN(r.ctx, n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+type readCloser struct {
+	*reader
+	io.Closer
+}
+
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+	l   *Limiter
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if burst := w.l.Burst(); burst > 0 && len(chunk) > burst {
+			chunk = chunk[:burst]
+		}
+		if err := w.l.WaitN(w.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
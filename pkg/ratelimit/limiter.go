@@ -0,0 +1,145 @@
+// Package ratelimit provides token-bucket limiters for request rates and
+// body bandwidth that can be shared by goroutines and adjusted at runtime.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket refilled at a fixed rate. Callers may take more
+// tokens than are available and then wait for the debt to be repaid, so a
+// single large read is paced rather than rejected. A nil Limiter, or one
+// with a rate of zero, does not limit.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a full limiter refilled at rate tokens per second,
+// holding at most burst tokens. A burst of zero allows one second's worth.
+func NewLimiter(rate float64, burst int) *Limiter {
+	l := &Limiter{}
+	l.SetLimit(rate, burst)
+	return l
+}
+
+// SetLimit changes the rate and burst, taking effect for waits that start
+// afterwards. A rate of zero or less removes the limit.
+func (l *Limiter) SetLimit(rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	limited := l.rate > 0
+	l.advance(now)
+	l.rate = max(rate, 0)
+	l.burst = float64(burst)
+	if burst <= 0 {
+		l.burst = max(math.Ceil(l.rate), 1)
+	}
+	if !limited {
+		l.tokens = l.burst
+	}
+	l.tokens = min(l.tokens, l.burst)
+	l.last = now
+}
+
+// Rate returns the tokens added per second, zero when unlimited
+func (l *Limiter) Rate() float64 {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Burst returns the maximum number of tokens the limiter holds, zero when
+// unlimited
+func (l *Limiter) Burst() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return 0
+	}
+	return int(l.burst)
+}
+
+// Allow takes one token if one is available now, without waiting
+func (l *Limiter) Allow() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return true
+	}
+	l.advance(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait takes one token, blocking until it is available
+func (l *Limiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN takes n tokens, blocking until the limiter has refilled enough to
+// cover them or ctx is done. Tokens are returned if ctx ends the wait.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	l.advance(now)
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.advance(time.Now())
+		l.tokens = min(l.tokens+float64(n), l.burst)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// advance refills the tokens accrued since the last update
+func (l *Limiter) advance(now time.Time) {
+	if l.rate > 0 && now.After(l.last) {
+		l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	}
+	l.last = now
+}
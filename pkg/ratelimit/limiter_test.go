@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimiterPacesWaits(t *testing.T) {
+	l := NewLimiter(100, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// The first token is in the bucket, the other five take 10ms each
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("6 waits at 100/s took %v, want at least 50ms", elapsed)
+	}
+}
+
+func TestLimiterUnlimitedAndRuntimeChanges(t *testing.T) {
+	var nilLimiter *Limiter
+	if err := nilLimiter.WaitN(context.Background(), 1<<30); err != nil {
+		t.Fatalf("nil limiter WaitN() error = %v", err)
+	}
+
+	l := NewLimiter(0, 0)
+	if err := l.WaitN(context.Background(), 1<<30); err != nil || l.Burst() != 0 {
+		t.Fatalf("unlimited WaitN() error = %v, Burst() = %d", err, l.Burst())
+	}
+
+	l.SetLimit(10, 1)
+	if l.Rate() != 10 || l.Burst() != 1 {
+		t.Fatalf("Rate() = %v, Burst() = %d", l.Rate(), l.Burst())
+	}
+	_ = l.Wait(context.Background())
+
+	// The next token is 100ms away
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want deadline exceeded", err)
+	}
+
+	if l.Allow() {
+		t.Error("Allow() should not take a token that is not available")
+	}
+
+	l.SetLimit(0, 0)
+	if !l.Allow() {
+		t.Error("Allow() should always succeed without a limit")
+	}
+	start := time.Now()
+	if err := l.WaitN(context.Background(), 1000); err != nil || time.Since(start) > 10*time.Millisecond {
+		t.Errorf("WaitN() after removing the limit: err = %v, took %v", err, time.Since(start))
+	}
+}
+
+func TestReaderAndWriter(t *testing.T) {
+	ctx := context.Background()
+	data := strings.Repeat("x", 3000)
+
+	// 10000 bytes/s with a 1000 byte burst: 2000 bytes of debt take 200ms
+	start := time.Now()
+	got, err := io.ReadAll(NewReader(ctx, strings.NewReader(data), NewLimiter(10000, 1000)))
+	if err != nil || string(got) != data {
+		t.Fatalf("ReadAll() = %d bytes, %v", len(got), err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("limited read took %v, want about 200ms", elapsed)
+	}
+
+	var buf bytes.Buffer
+	start = time.Now()
+	n, err := NewWriter(ctx, &buf, NewLimiter(10000, 1000)).Write([]byte(data))
+	if err != nil || n != len(data) || buf.String() != data {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("limited write took %v, want about 200ms", elapsed)
+	}
+}
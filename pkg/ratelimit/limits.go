@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Class groups operations that share a request-rate limit
+type Class string
+
+const (
+	// ClassRead covers GET and HEAD requests other than listings
+	ClassRead Class = "read"
+	// ClassWrite covers PUT and POST requests other than deletions
+	ClassWrite Class = "write"
+	// ClassList covers List* operations
+	ClassList Class = "list"
+	// ClassDelete covers DELETE requests and DeleteObjects
+	ClassDelete Class = "delete"
+)
+
+// ClassOf returns the class of an S3 operation sent with method
+func ClassOf(method, operation string) Class {
+	switch {
+	case strings.HasPrefix(operation, "List"):
+		return ClassList
+	case method == http.MethodDelete || operation == "DeleteObjects":
+		return ClassDelete
+	case method == http.MethodGet || method == http.MethodHead:
+		return ClassRead
+	default:
+		return ClassWrite
+	}
+}
+
+// Config holds initial limits. Zero values leave a limit unset.
+type Config struct {
+	// UploadBytesPerSecond limits request bodies
+	UploadBytesPerSecond int64
+	// DownloadBytesPerSecond limits response bodies
+	DownloadBytesPerSecond int64
+	// RequestsPerSecond limits the attempts of each operation class
+	RequestsPerSecond map[Class]float64
+}
+
+// Limits holds the bandwidth and request-rate limiters of a client. All
+// limits can be changed at runtime and apply to every goroutine using the
+// client, including transfers already in progress. A nil Limits does not
+// limit.
+type Limits struct {
+	upload   *Limiter
+	download *Limiter
+
+	mu       sync.RWMutex
+	requests map[Class]*Limiter
+}
+
+// New returns the limits described by config
+func New(config Config) *Limits {
+	l := &Limits{
+		upload:   NewLimiter(0, 0),
+		download: NewLimiter(0, 0),
+		requests: make(map[Class]*Limiter),
+	}
+	l.SetUploadBandwidth(config.UploadBytesPerSecond)
+	l.SetDownloadBandwidth(config.DownloadBytesPerSecond)
+	for class, rate := range config.RequestsPerSecond {
+		l.SetRequestRate(class, rate)
+	}
+	return l
+}
+
+// SetUploadBandwidth limits request bodies to bytesPerSecond, or removes
+// the limit if it is zero or less
+func (l *Limits) SetUploadBandwidth(bytesPerSecond int64) {
+	l.upload.SetLimit(float64(bytesPerSecond), 0)
+}
+
+// SetDownloadBandwidth limits response bodies to bytesPerSecond, or removes
+// the limit if it is zero or less
+func (l *Limits) SetDownloadBandwidth(bytesPerSecond int64) {
+	l.download.SetLimit(float64(bytesPerSecond), 0)
+}
+
+// SetRequestRate limits the attempts of class to perSecond, or removes the
+// limit if it is zero or less
+func (l *Limits) SetRequestRate(class Class, perSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter, ok := l.requests[class]; ok {
+		limiter.SetLimit(perSecond, 0)
+		return
+	}
+	if perSecond > 0 {
+		l.requests[class] = NewLimiter(perSecond, 0)
+	}
+}
+
+// UploadBandwidth returns the upload limit in bytes per second, zero when unlimited
+func (l *Limits) UploadBandwidth() int64 {
+	if l == nil {
+		return 0
+	}
+	return int64(l.upload.Rate())
+}
+
+// DownloadBandwidth returns the download limit in bytes per second, zero when unlimited
+func (l *Limits) DownloadBandwidth() int64 {
+	if l == nil {
+		return 0
+	}
+	return int64(l.download.Rate())
+}
+
+// RequestRate returns the request limit of class per second, zero when unlimited
+func (l *Limits) RequestRate(class Class) float64 {
+	return l.requestLimiter(class).Rate()
+}
+
+// WaitRequest blocks until a request of class may be sent
+func (l *Limits) WaitRequest(ctx context.Context, class Class) error {
+	return l.requestLimiter(class).Wait(ctx)
+}
+
+// AllowRequest reports whether a request of class may be sent now without
+// waiting, taking its token if so. It suits optional requests such as hedges.
+func (l *Limits) AllowRequest(class Class) bool {
+	return l.requestLimiter(class).Allow()
+}
+
+// Upload wraps a request body in the upload limit
+func (l *Limits) Upload(ctx context.Context, body io.ReadCloser) io.ReadCloser {
+	if l == nil || body == nil || body == http.NoBody {
+		return body
+	}
+	return NewReadCloser(ctx, body, l.upload)
+}
+
+// Download wraps a response body in the download limit
+func (l *Limits) Download(ctx context.Context, body io.ReadCloser) io.ReadCloser {
+	if l == nil || body == nil || body == http.NoBody {
+		return body
+	}
+	return NewReadCloser(ctx, body, l.download)
+}
+
+func (l *Limits) requestLimiter(class Class) *Limiter {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.requests[class]
+}
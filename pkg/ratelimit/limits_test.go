@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		method, operation string
+		want              Class
+	}{
+		{http.MethodGet, "GetObject", ClassRead},
+		{http.MethodHead, "HeadObject", ClassRead},
+		{http.MethodGet, "ListObjectsV2", ClassList},
+		{http.MethodGet, "ListParts", ClassList},
+		{http.MethodPut, "PutObject", ClassWrite},
+		{http.MethodPost, "CompleteMultipartUpload", ClassWrite},
+		{http.MethodDelete, "DeleteObject", ClassDelete},
+		{http.MethodPost, "DeleteObjects", ClassDelete},
+	}
+	for _, tt := range tests {
+		if got := ClassOf(tt.method, tt.operation); got != tt.want {
+			t.Errorf("ClassOf(%s, %s) = %s, want %s", tt.method, tt.operation, got, tt.want)
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	limits := New(Config{
+		UploadBytesPerSecond: 1 << 20,
+		RequestsPerSecond:    map[Class]float64{ClassWrite: 50},
+	})
+	if limits.UploadBandwidth() != 1<<20 || limits.DownloadBandwidth() != 0 {
+		t.Errorf("bandwidth = %d up, %d down", limits.UploadBandwidth(), limits.DownloadBandwidth())
+	}
+	if limits.RequestRate(ClassWrite) != 50 || limits.RequestRate(ClassRead) != 0 {
+		t.Errorf("request rates = %v write, %v read", limits.RequestRate(ClassWrite), limits.RequestRate(ClassRead))
+	}
+
+	// A body wrapped before a change follows the new limit
+	body := limits.Download(context.Background(), io.NopCloser(strings.NewReader(strings.Repeat("x", 1200))))
+	limits.SetDownloadBandwidth(1000)
+	start := time.Now()
+	if _, err := io.ReadAll(body); err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("download after SetDownloadBandwidth took %v, want about 200ms", elapsed)
+	}
+
+	limits.SetRequestRate(ClassRead, 100)
+	limits.SetRequestRate(ClassWrite, 0)
+	if limits.RequestRate(ClassRead) != 100 || limits.RequestRate(ClassWrite) != 0 {
+		t.Errorf("request rates after change = %v read, %v write", limits.RequestRate(ClassRead), limits.RequestRate(ClassWrite))
+	}
+
+	var none *Limits
+	if err := none.WaitRequest(context.Background(), ClassRead); err != nil || none.Upload(context.Background(), http.NoBody) != http.NoBody {
+		t.Error("nil Limits should not limit")
+	}
+}
@@ -0,0 +1,65 @@
+package retry
+
+import "sync"
+
+const (
+	// DefaultBudgetCapacity is the number of tokens in a new default budget
+	DefaultBudgetCapacity = 500
+	// DefaultRetryCost is the number of tokens a retry withdraws
+	DefaultRetryCost = 5
+)
+
+// Budget is a token bucket shared by the requests of a client. Each retry
+// withdraws tokens and successful requests put them back, so a failing
+// endpoint can exhaust the budget and stop retries from multiplying load,
+// while occasional failures are retried freely.
+type Budget struct {
+	mu       sync.Mutex
+	tokens   int
+	capacity int
+	cost     int
+}
+
+// NewBudget returns a full budget of capacity tokens where each retry costs cost
+func NewBudget(capacity, cost int) *Budget {
+	if cost <= 0 {
+		cost = 1
+	}
+	return &Budget{
+		tokens:   capacity,
+		capacity: capacity,
+		cost:     cost,
+	}
+}
+
+// Acquire withdraws the cost of one retry, reporting false if the budget
+// cannot afford it
+func (b *Budget) Acquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < b.cost {
+		return false
+	}
+	b.tokens -= b.cost
+	return true
+}
+
+// Release returns n tokens to the budget, up to its capacity
+func (b *Budget) Release(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += n
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// Available returns the number of tokens left
+func (b *Budget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokens
+}
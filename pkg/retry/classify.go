@@ -0,0 +1,151 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// retryableCodes are S3 error codes reporting transient conditions
+var retryableCodes = map[string]bool{
+	"SlowDown":               true,
+	"RequestTimeout":         true,
+	"InternalError":          true,
+	"ServiceUnavailable":     true,
+	"Throttling":             true,
+	"ThrottlingException":    true,
+	"RequestThrottled":       true,
+	"RequestLimitExceeded":   true,
+	"TooManyRequests":        true,
+	"BandwidthLimitExceeded": true,
+}
+
+// throttleCodes are S3 error codes rejecting a request before processing it
+var throttleCodes = map[string]bool{
+	"SlowDown":               true,
+	"ServiceUnavailable":     true,
+	"Throttling":             true,
+	"ThrottlingException":    true,
+	"RequestThrottled":       true,
+	"RequestLimitExceeded":   true,
+	"TooManyRequests":        true,
+	"BandwidthLimitExceeded": true,
+}
+
+// Retryable reports whether a failed attempt hit a transient condition:
+// a retryable transport error, S3 error code or HTTP status
+func Retryable(attempt Attempt) bool {
+	if attempt.Err != nil {
+		return RetryableError(attempt.Err)
+	}
+	return RetryableCode(attempt.Code) || RetryableStatus(attempt.StatusCode)
+}
+
+// RetryableCode reports whether an S3 error code is transient
+func RetryableCode(code string) bool {
+	return retryableCodes[code]
+}
+
+// RetryableStatus reports whether an HTTP status is transient: 429 and
+// server errors other than 501 Not Implemented and 505
+func RetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return status >= 500 && status < 600
+}
+
+// RetryableError reports whether a transport error is transient:
+// timeouts, refused or reset connections and truncated responses
+func RetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if notConnected(err) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// Errors that lost their type on the way up still name the condition
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "TLS handshake timeout") ||
+		strings.Contains(msg, "i/o timeout")
+}
+
+// NotProcessed reports whether the server cannot have acted on a failed
+// attempt: the connection was never established, or the server throttled
+// the request. Such attempts are safe to retry for any operation.
+func NotProcessed(attempt Attempt) bool {
+	if attempt.Err != nil {
+		return notConnected(attempt.Err)
+	}
+	return throttleCodes[attempt.Code] ||
+		(attempt.Code == "" && (attempt.StatusCode == http.StatusTooManyRequests ||
+			attempt.StatusCode == http.StatusServiceUnavailable))
+}
+
+// ConnectionFailed reports whether a transport error shows the server
+// could not be reached or dropped the connection: dial and DNS failures,
+// refused or reset connections and unreachable networks. Timeouts, TLS and
+// proxy errors and errors of the request body say nothing about the server
+// and are not connection failures.
+func ConnectionFailed(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if notConnected(err) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") || strings.Contains(msg, "no route to host")
+}
+
+// notConnected reports whether err shows the request was never sent
+func notConnected(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(err.Error(), "connection refused")
+}
+
+// ParseRetryAfter returns the delay requested by a Retry-After header value,
+// given in seconds or as an HTTP date, or zero if there is none
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
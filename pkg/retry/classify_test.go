@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testNetError simulates network error
+type testNetError struct {
+	msg     string
+	timeout bool
+}
+
+func (e *testNetError) Error() string   { return e.msg }
+func (e *testNetError) Temporary() bool { return false }
+func (e *testNetError) Timeout() bool   { return e.timeout }
+
+func TestRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Nil error", err: nil, want: false},
+		{name: "Connection refused", err: &url.Error{Op: "Get", Err: &testNetError{msg: "connection refused"}}, want: true},
+		{name: "Refused dial", err: &url.Error{Op: "Put", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, want: true},
+		{name: "Connection reset", err: &url.Error{Op: "Put", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, want: true},
+		{name: "Timeout error", err: &testNetError{msg: "i/o timeout", timeout: true}, want: true},
+		{name: "Net error timeout", err: &testNetError{msg: "net error", timeout: true}, want: true},
+		{name: "Truncated response", err: &url.Error{Op: "Get", Err: io.ErrUnexpectedEOF}, want: true},
+		{name: "Canceled", err: &url.Error{Op: "Get", Err: context.Canceled}, want: false},
+		{name: "Non-retryable error", err: io.EOF, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RetryableError(tt.err); got != tt.want {
+				t.Errorf("RetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryableResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		code       string
+		want       bool
+	}{
+		{name: "500 Internal Server Error", statusCode: 500, want: true},
+		{name: "502 Bad Gateway", statusCode: 502, want: true},
+		{name: "503 Service Unavailable", statusCode: 503, want: true},
+		{name: "429 Too Many Requests", statusCode: 429, want: true},
+		{name: "501 Not Implemented", statusCode: 501, code: "NotImplemented", want: false},
+		{name: "200 OK", statusCode: 200, want: false},
+		{name: "404 Not Found", statusCode: 404, code: "NoSuchKey", want: false},
+		{name: "400 RequestTimeout", statusCode: 400, code: "RequestTimeout", want: true},
+		{name: "200 with InternalError body", statusCode: 200, code: "InternalError", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt := Attempt{StatusCode: tt.statusCode, Code: tt.code}
+			if got := Retryable(attempt); got != tt.want {
+				t.Errorf("Retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotProcessed(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt Attempt
+		want    bool
+	}{
+		{name: "dial error", attempt: Attempt{Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, want: true},
+		{name: "read error", attempt: Attempt{Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, want: false},
+		{name: "SlowDown", attempt: Attempt{StatusCode: 503, Code: "SlowDown"}, want: true},
+		{name: "bare 429", attempt: Attempt{StatusCode: 429}, want: true},
+		{name: "InternalError", attempt: Attempt{StatusCode: 500, Code: "InternalError"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotProcessed(tt.attempt); got != tt.want {
+				t.Errorf("NotProcessed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Nil error", err: nil, want: false},
+		{name: "Refused dial", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, want: true},
+		{name: "DNS failure", err: &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "node"}}, want: true},
+		{name: "Connection reset", err: &url.Error{Op: "Put", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, want: true},
+		{name: "No route", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "read", Err: syscall.EHOSTUNREACH}}, want: true},
+		{name: "Timeout", err: &url.Error{Op: "Get", Err: &testNetError{msg: "timeout awaiting response headers", timeout: true}}, want: false},
+		{name: "TLS handshake", err: &url.Error{Op: "Get", Err: &testNetError{msg: "tls: handshake failure"}}, want: false},
+		{name: "Proxy", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "proxyconnect", Err: io.EOF}}, want: false},
+		{name: "Body read", err: &url.Error{Op: "Put", Err: io.ErrUnexpectedEOF}, want: false},
+		{name: "Canceled", err: &url.Error{Op: "Get", Err: context.Canceled}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConnectionFailed(tt.err); got != tt.want {
+				t.Errorf("ConnectionFailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "3", want: 3 * time.Second},
+		{value: "-1", want: 0},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package retry decides whether and when failed S3 requests are retried.
+package retry
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts, including the first, made by New(0)
+	DefaultMaxAttempts = 10
+	// DefaultBaseDelay is the backoff ceiling of the first retry
+	DefaultBaseDelay = 100 * time.Millisecond
+	// DefaultMaxDelay caps the backoff ceiling
+	DefaultMaxDelay = 10 * time.Second
+	// DefaultMaxRetryAfter caps the delay honoured from a Retry-After header
+	DefaultMaxRetryAfter = 30 * time.Second
+)
+
+// Attempt describes one attempt of a request
+type Attempt struct {
+	// Operation is the S3 API name, e.g. CompleteMultipartUpload
+	Operation string
+	// Method is the HTTP method
+	Method string
+	// Number is the zero-based index of the attempt
+	Number int
+	// Err is the transport error, nil when a response was received
+	Err error
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Code is the S3 error code parsed from the response body
+	Code string
+	// RetryAfter is the delay requested by the server's Retry-After header
+	RetryAfter time.Duration
+}
+
+// Policy decides whether failed attempts are retried
+type Policy interface {
+	// Retry is called after a failed attempt and reports whether the request
+	// should be retried and how long to wait first
+	Retry(attempt Attempt) (time.Duration, bool)
+
+	// Success is called when an attempt succeeds
+	Success(attempt Attempt)
+}
+
+// Standard is the default Policy. It retries transient failures with
+// full-jitter exponential backoff, honours Retry-After on throttled
+// responses, only repeats requests that may have reached the server when
+// the operation is idempotent, and draws every retry from a shared Budget.
+type Standard struct {
+	// MaxAttempts is the number of attempts including the first; 1 disables retries
+	MaxAttempts int
+
+	// BaseDelay and MaxDelay bound the backoff ceiling, BaseDelay*2^n up to MaxDelay.
+	// The delay is drawn uniformly between zero and the ceiling.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// MaxRetryAfter caps the delay requested by a Retry-After header
+	MaxRetryAfter time.Duration
+
+	// Idempotent overrides, per operation name, whether an operation may be
+	// repeated after a failure that may have been processed by the server
+	Idempotent map[string]bool
+
+	// Budget limits retries across all requests sharing the policy; nil is unlimited
+	Budget *Budget
+}
+
+// New returns a Standard policy making up to maxAttempts attempts
+// (DefaultMaxAttempts if maxAttempts <= 0) with a default retry budget.
+func New(maxAttempts int) *Standard {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Standard{
+		MaxAttempts:   maxAttempts,
+		BaseDelay:     DefaultBaseDelay,
+		MaxDelay:      DefaultMaxDelay,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+		Budget:        NewBudget(DefaultBudgetCapacity, DefaultRetryCost),
+	}
+}
+
+// Retry implements Policy
+func (p *Standard) Retry(attempt Attempt) (time.Duration, bool) {
+	if attempt.Number+1 >= p.MaxAttempts || !Retryable(attempt) {
+		return 0, false
+	}
+	if !NotProcessed(attempt) && !p.IsIdempotent(attempt.Operation, attempt.Method) {
+		return 0, false
+	}
+	if p.Budget != nil && !p.Budget.Acquire() {
+		return 0, false
+	}
+	return p.Delay(attempt), true
+}
+
+// Success implements Policy. A request that succeeds after retrying
+// refunds one retry to the budget; one that succeeds at once adds a token.
+func (p *Standard) Success(attempt Attempt) {
+	if p.Budget == nil {
+		return
+	}
+	if attempt.Number > 0 {
+		p.Budget.Release(p.Budget.cost)
+	} else {
+		p.Budget.Release(1)
+	}
+}
+
+// IsIdempotent reports whether operation may be repeated, consulting the
+// Idempotent overrides before the defaults of the package-level IsIdempotent
+func (p *Standard) IsIdempotent(operation, method string) bool {
+	if idempotent, ok := p.Idempotent[operation]; ok {
+		return idempotent
+	}
+	return IsIdempotent(operation, method)
+}
+
+// Delay returns the wait before retrying attempt: the server's Retry-After
+// when given, otherwise a full-jitter exponential backoff
+func (p *Standard) Delay(attempt Attempt) time.Duration {
+	if attempt.RetryAfter > 0 {
+		if p.MaxRetryAfter > 0 && attempt.RetryAfter > p.MaxRetryAfter {
+			return p.MaxRetryAfter
+		}
+		return attempt.RetryAfter
+	}
+
+	ceiling := p.BaseDelay
+	for i := 0; i < attempt.Number && (p.MaxDelay <= 0 || ceiling < p.MaxDelay); i++ {
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// nonIdempotent lists operations whose repetition can have a different
+// effect from a single call
+var nonIdempotent = map[string]bool{
+	"CreateMultipartUpload":   true,
+	"CompleteMultipartUpload": true,
+	"AppendObject":            true,
+}
+
+// idempotentPost lists POST operations that are safe to repeat
+var idempotentPost = map[string]bool{
+	"DeleteObjects":       true,
+	"RestoreObject":       true,
+	"SelectObjectContent": true,
+}
+
+// IsIdempotent reports whether repeating the operation has the same effect
+// as a single call. Operations sent with POST are not idempotent unless
+// known otherwise; other methods are, except for known operations such as
+// AppendObject.
+func IsIdempotent(operation, method string) bool {
+	if nonIdempotent[operation] {
+		return false
+	}
+	if method == http.MethodPost {
+		return idempotentPost[operation]
+	}
+	return true
+}
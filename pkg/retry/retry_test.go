@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStandardMaxAttempts(t *testing.T) {
+	policy := New(3)
+	failed := Attempt{Operation: "GetObject", Method: http.MethodGet, StatusCode: 500}
+
+	for number, want := range []bool{true, true, false} {
+		failed.Number = number
+		if _, got := policy.Retry(failed); got != want {
+			t.Errorf("attempt %d: Retry() = %v, want %v", number, got, want)
+		}
+	}
+
+	if _, ok := New(1).Retry(Attempt{Method: http.MethodGet, StatusCode: 503}); ok {
+		t.Error("New(1) should not retry")
+	}
+}
+
+func TestStandardIdempotency(t *testing.T) {
+	policy := New(5)
+
+	tests := []struct {
+		name    string
+		attempt Attempt
+		want    bool
+	}{
+		{
+			name:    "complete after InternalError",
+			attempt: Attempt{Operation: "CompleteMultipartUpload", Method: http.MethodPost, StatusCode: 500, Code: "InternalError"},
+			want:    false,
+		},
+		{
+			name:    "complete after SlowDown",
+			attempt: Attempt{Operation: "CompleteMultipartUpload", Method: http.MethodPost, StatusCode: 503, Code: "SlowDown"},
+			want:    true,
+		},
+		{
+			name:    "delete objects after InternalError",
+			attempt: Attempt{Operation: "DeleteObjects", Method: http.MethodPost, StatusCode: 500, Code: "InternalError"},
+			want:    true,
+		},
+		{
+			name:    "append after bad gateway",
+			attempt: Attempt{Operation: "AppendObject", Method: http.MethodPut, StatusCode: 502},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := policy.Retry(tt.attempt); got != tt.want {
+				t.Errorf("Retry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Overrides take precedence over the defaults
+	policy.Idempotent = map[string]bool{"CompleteMultipartUpload": true}
+	if _, ok := policy.Retry(tests[0].attempt); !ok {
+		t.Error("Retry() should honour the idempotency override")
+	}
+}
+
+func TestStandardDelay(t *testing.T) {
+	policy := &Standard{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, MaxRetryAfter: 5 * time.Second}
+
+	for number := 0; number < 8; number++ {
+		ceiling := min(100*time.Millisecond<<number, time.Second)
+		for range 20 {
+			if delay := policy.Delay(Attempt{Number: number}); delay < 0 || delay > ceiling {
+				t.Fatalf("attempt %d: delay %v outside [0, %v]", number, delay, ceiling)
+			}
+		}
+	}
+
+	if delay := policy.Delay(Attempt{RetryAfter: 2 * time.Second}); delay != 2*time.Second {
+		t.Errorf("Retry-After delay = %v, want 2s", delay)
+	}
+	if delay := policy.Delay(Attempt{RetryAfter: time.Minute}); delay != 5*time.Second {
+		t.Errorf("capped Retry-After delay = %v, want 5s", delay)
+	}
+}
+
+func TestStandardBudget(t *testing.T) {
+	policy := New(10)
+	policy.Budget = NewBudget(10, 5)
+	failed := Attempt{Operation: "GetObject", Method: http.MethodGet, StatusCode: 503}
+
+	for i := 0; i < 2; i++ {
+		if _, ok := policy.Retry(failed); !ok {
+			t.Fatalf("retry %d should fit in the budget", i)
+		}
+	}
+	if _, ok := policy.Retry(failed); ok {
+		t.Fatal("Retry() should stop once the budget is exhausted")
+	}
+
+	// A success after a retry refunds it; first-try successes add a token each
+	policy.Success(Attempt{Number: 1})
+	if got := policy.Budget.Available(); got != 5 {
+		t.Errorf("Available() = %d, want 5", got)
+	}
+	for i := 0; i < 10; i++ {
+		policy.Success(Attempt{})
+	}
+	if got := policy.Budget.Available(); got != 10 {
+		t.Errorf("Available() = %d, want capacity 10", got)
+	}
+}
@@ -27,14 +27,29 @@ const (
 
 // NewSigner creates a signer instance
 func NewSigner(signerType SignerType) Signer {
+	return NewSignerWithClock(signerType, nil)
+}
+
+// NewSignerWithClock creates a signer instance that takes the signing time
+// from now, e.g. a clock corrected for skew against the server. A nil now
+// uses time.Now.
+func NewSignerWithClock(signerType SignerType, now func() time.Time) Signer {
 	switch signerType {
 	case SignerV2:
-		return &V2Signer{}
+		return &V2Signer{Now: now}
 	case SignerAnonymous:
 		return &AnonymousSigner{}
 	default:
-		return &V4Signer{}
+		return &V4Signer{Now: now}
+	}
+}
+
+// signingTime returns the current UTC time from now, or time.Now if nil
+func signingTime(now func() time.Time) time.Time {
+	if now == nil {
+		return time.Now().UTC()
 	}
+	return now().UTC()
 }
 
 // AnonymousSigner signs anonymously
@@ -105,38 +105,33 @@ func (s *StreamingReader) readNextChunk() error {
 		return io.EOF
 	}
 
-	// Read data
+	// Read data, never past the declared content length
 	s.chunkLen = 0
-	for s.chunkLen < PayloadChunkSize {
-		n, err := s.baseReader.Read(s.chunkBuf[s.chunkLen:])
+	for s.chunkLen < PayloadChunkSize && s.bytesRead < s.contentLen {
+		want := min(int64(PayloadChunkSize-s.chunkLen), s.contentLen-s.bytesRead)
+		n, err := s.baseReader.Read(s.chunkBuf[s.chunkLen : int64(s.chunkLen)+want])
 		if n > 0 {
 			s.chunkLen += n
 			s.bytesRead += int64(n)
 		}
 
 		if err != nil {
-			if err == io.EOF {
-				s.done = true
-				break
+			if err == io.EOF && s.bytesRead < s.contentLen {
+				return fmt.Errorf("content length mismatch: expected %d, got %d", s.contentLen, s.bytesRead)
+			}
+			if err != io.EOF {
+				return err
 			}
-			return err
-		}
-
-		// Break if chunk full or all data read
-		if s.chunkLen >= PayloadChunkSize || s.bytesRead >= s.contentLen {
-			break
 		}
 	}
-
-	// Validate bytes read
-	if s.done && s.bytesRead != s.contentLen {
-		return fmt.Errorf("content length mismatch: expected %d, got %d", s.contentLen, s.bytesRead)
-	}
+	s.done = s.bytesRead >= s.contentLen
 
 	// Sign chunk
-	s.signChunk(s.chunkLen)
+	if s.chunkLen > 0 {
+		s.signChunk(s.chunkLen)
+	}
 
-	// If finished, write final empty chunk
+	// If finished, write the single final empty chunk
 	if s.done {
 		s.signChunk(0)
 	}
@@ -268,7 +263,7 @@ func GetStreamLength(dataLen int64, chunkSize int64) int64 {
 func PrepareStreamingRequest(req *http.Request, sessionToken string, dataLen int64) {
 	// Set required headers
 	req.Header.Set("X-Amz-Content-Sha256", StreamingSignAlgorithm)
-	req.Header.Set("Content-Encoding", "aws-chunked")
+	setAWSChunkedEncoding(req)
 	req.Header.Set("X-Amz-Decoded-Content-Length", strconv.FormatInt(dataLen, 10))
 
 	if sessionToken != "" {
@@ -278,3 +273,42 @@ func PrepareStreamingRequest(req *http.Request, sessionToken string, dataLen int
 	// Set content length to streaming-signed length
 	req.ContentLength = GetStreamLength(dataLen, PayloadChunkSize)
 }
+
+// SignV4Streaming signs req for an aws-chunked upload of dataLen bytes.
+// The seed signature covers the headers; req.Body is wrapped so that every
+// 64KiB chunk is signed in turn, chaining from the seed signature.
+func SignV4Streaming(req *http.Request, accessKey, secretKey, sessionToken, region string, dataLen int64) *http.Request {
+	return SignV4StreamingAt(req, accessKey, secretKey, sessionToken, region, dataLen, time.Now())
+}
+
+// SignV4StreamingAt is SignV4Streaming with the signing time t
+func SignV4StreamingAt(req *http.Request, accessKey, secretKey, sessionToken, region string, dataLen int64, t time.Time) *http.Request {
+	if accessKey == "" || secretKey == "" {
+		return req
+	}
+
+	PrepareStreamingRequest(req, sessionToken, dataLen)
+
+	t = t.UTC()
+	seedSignature := (&V4Signer{}).sign(req, accessKey, secretKey, sessionToken, region, t)
+
+	body := req.Body
+	if body == nil {
+		body = http.NoBody
+	}
+	req.Body = NewStreamingReader(body, accessKey, secretKey, sessionToken, region, dataLen, t, seedSignature)
+	req.GetBody = nil
+	return req
+}
+
+// setAWSChunkedEncoding adds aws-chunked in front of any existing content encoding
+func setAWSChunkedEncoding(req *http.Request) {
+	encoding := req.Header.Get("Content-Encoding")
+	if encoding == "" || encoding == "aws-chunked" {
+		req.Header.Set("Content-Encoding", "aws-chunked")
+		return
+	}
+	if !strings.HasPrefix(encoding, "aws-chunked,") {
+		req.Header.Set("Content-Encoding", "aws-chunked,"+encoding)
+	}
+}
@@ -0,0 +1,142 @@
+// Package signer internal/signer/trailer.go
+package signer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnsignedPayloadTrailer is the payload hash of aws-chunked uploads whose
+// chunks are unsigned and whose body ends with trailing checksum headers
+const UnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+// TrailerReader encodes a body as unsigned aws-chunked data followed by
+// trailing headers. Each trailer value is the base64 sum of its hash, which
+// is fed the body as it streams, so checksums need no extra pass over the data.
+type TrailerReader struct {
+	baseReader io.ReadCloser
+	trailers   map[string]hash.Hash
+
+	contentLen int64
+	bytesRead  int64
+
+	buf      bytes.Buffer
+	chunkBuf []byte
+	done     bool
+}
+
+// NewTrailerReader creates a reader encoding contentLen bytes of reader with
+// the given trailers, keyed by lowercase header name (e.g. x-amz-checksum-crc32c)
+func NewTrailerReader(reader io.ReadCloser, contentLen int64, trailers map[string]hash.Hash) *TrailerReader {
+	return &TrailerReader{
+		baseReader: reader,
+		trailers:   trailers,
+		contentLen: contentLen,
+		chunkBuf:   make([]byte, PayloadChunkSize),
+	}
+}
+
+// Read implements io.Reader
+func (r *TrailerReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.readNextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	return r.buf.Read(p)
+}
+
+// readNextChunk encodes the next chunk, and the final chunk with trailers at the end
+func (r *TrailerReader) readNextChunk() error {
+	want := min(int64(PayloadChunkSize), r.contentLen-r.bytesRead)
+	if want > 0 {
+		n, err := io.ReadFull(r.baseReader, r.chunkBuf[:want])
+		r.bytesRead += int64(n)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("content length mismatch: expected %d, got %d", r.contentLen, r.bytesRead)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, h := range r.trailers {
+			h.Write(r.chunkBuf[:n])
+		}
+		fmt.Fprintf(&r.buf, "%x\r\n", n)
+		r.buf.Write(r.chunkBuf[:n])
+		r.buf.WriteString("\r\n")
+	}
+
+	if r.bytesRead >= r.contentLen {
+		r.buf.WriteString("0\r\n")
+		for _, name := range sortedTrailerNames(r.trailers) {
+			r.buf.WriteString(name + ":" + base64.StdEncoding.EncodeToString(r.trailers[name].Sum(nil)) + "\r\n")
+		}
+		r.buf.WriteString("\r\n")
+		r.done = true
+	}
+	return nil
+}
+
+// Close closes underlying reader
+func (r *TrailerReader) Close() error {
+	return r.baseReader.Close()
+}
+
+// GetTrailerStreamLength calculates the encoded length of dataLen bytes with trailers
+func GetTrailerStreamLength(dataLen int64, trailers map[string]hash.Hash) int64 {
+	var streamLen int64
+
+	// Data chunks: <hex-size>\r\n<data>\r\n
+	fullChunks := dataLen / PayloadChunkSize
+	streamLen += fullChunks * int64(len(strconv.FormatInt(PayloadChunkSize, 16))+2+PayloadChunkSize+2)
+	if remainder := dataLen % PayloadChunkSize; remainder > 0 {
+		streamLen += int64(len(strconv.FormatInt(remainder, 16))) + 2 + remainder + 2
+	}
+
+	// Final chunk: 0\r\n, trailers as <name>:<base64>\r\n, then \r\n
+	streamLen += 3
+	for name, h := range trailers {
+		streamLen += int64(len(name)+1+base64.StdEncoding.EncodedLen(h.Size())) + 2
+	}
+	streamLen += 2
+
+	return streamLen
+}
+
+// PrepareTrailerRequest sets the headers of an unsigned-payload trailer
+// upload and wraps req.Body. The request must be signed afterwards.
+func PrepareTrailerRequest(req *http.Request, dataLen int64, trailers map[string]hash.Hash) {
+	req.Header.Set("X-Amz-Content-Sha256", UnsignedPayloadTrailer)
+	setAWSChunkedEncoding(req)
+	req.Header.Set("X-Amz-Decoded-Content-Length", strconv.FormatInt(dataLen, 10))
+	req.Header.Set("X-Amz-Trailer", strings.Join(sortedTrailerNames(trailers), ","))
+
+	body := req.Body
+	if body == nil {
+		body = http.NoBody
+	}
+	req.Body = NewTrailerReader(body, dataLen, trailers)
+	req.GetBody = nil
+	req.ContentLength = GetTrailerStreamLength(dataLen, trailers)
+}
+
+// sortedTrailerNames returns trailer names in a stable order
+func sortedTrailerNames(trailers map[string]hash.Hash) []string {
+	names := make([]string, 0, len(trailers))
+	for name := range trailers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
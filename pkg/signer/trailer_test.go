@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"encoding/base64"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTrailerReader(t *testing.T) {
+	for _, size := range []int{1, PayloadChunkSize, PayloadChunkSize*2 + 7} {
+		testData := strings.Repeat("D", size)
+		req, err := http.NewRequest(http.MethodPut, "https://example.com/bucket/object", io.NopCloser(strings.NewReader(testData)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		trailers := map[string]hash.Hash{
+			"x-amz-checksum-crc32c": crc32.New(crc32.MakeTable(crc32.Castagnoli)),
+		}
+		PrepareTrailerRequest(req, int64(size), trailers)
+
+		if got := req.Header.Get("X-Amz-Content-Sha256"); got != UnsignedPayloadTrailer {
+			t.Errorf("X-Amz-Content-Sha256 = %q", got)
+		}
+		if got := req.Header.Get("X-Amz-Trailer"); got != "x-amz-checksum-crc32c" {
+			t.Errorf("X-Amz-Trailer = %q", got)
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("Failed to read trailer body: %v", err)
+		}
+		if int64(len(body)) != req.ContentLength {
+			t.Errorf("size %d: body length = %d, ContentLength = %d", size, len(body), req.ContentLength)
+		}
+
+		sum := crc32.Checksum([]byte(testData), crc32.MakeTable(crc32.Castagnoli))
+		want := "0\r\nx-amz-checksum-crc32c:" + base64.StdEncoding.EncodeToString([]byte{
+			byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum),
+		}) + "\r\n\r\n"
+		if !strings.HasSuffix(string(body), want) {
+			t.Errorf("size %d: body ends with %q, want %q", size, body[max(0, len(body)-len(want)):], want)
+		}
+		if got := strings.Count(string(body), "\r\n0\r\n"); got != 1 {
+			t.Errorf("size %d: expected 1 final chunk, got %d", size, got)
+		}
+	}
+}
+
+func TestTrailerReader_ShortBody(t *testing.T) {
+	reader := NewTrailerReader(io.NopCloser(strings.NewReader("short")), 10, nil)
+	if _, err := io.Copy(io.Discard, reader); err == nil {
+		t.Fatal("Expected content length mismatch error")
+	}
+}
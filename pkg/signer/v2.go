@@ -52,6 +52,9 @@ var v2ResourceList = []string{
 // V2Signer implements AWS Signature Version 2 signing
 type V2Signer struct {
 	virtualHost bool
+
+	// Now returns the signing time; nil uses time.Now
+	Now func() time.Time
 }
 
 // Sign signs request using V2 algorithm
@@ -64,7 +67,7 @@ func (s *V2Signer) Sign(req *http.Request, accessKey, secretKey, sessionToken, r
 	}
 
 	// Initialize time
-	d := time.Now().UTC()
+	d := signingTime(s.Now)
 
 	// Add Date header if missing
 	if date := req.Header.Get("Date"); date == "" {
@@ -101,7 +104,7 @@ func (s *V2Signer) Presign(req *http.Request, accessKey, secretKey, sessionToken
 		return req
 	}
 
-	d := time.Now().UTC()
+	d := signingTime(s.Now)
 	// Compute expiration (Unix timestamp)
 	epochExpires := d.Unix() + int64(expires.Seconds())
 
@@ -32,7 +32,10 @@ var v4IgnoredHeaders = map[string]bool{
 }
 
 // V4Signer AWS Signature Version 4 signer
-type V4Signer struct{}
+type V4Signer struct {
+	// Now returns the signing time; nil uses time.Now
+	Now func() time.Time
+}
 
 // Sign signs a request with Signature V4
 func (s *V4Signer) Sign(req *http.Request, accessKey, secretKey, sessionToken, region string) *http.Request {
@@ -41,8 +44,13 @@ func (s *V4Signer) Sign(req *http.Request, accessKey, secretKey, sessionToken, r
 		return req
 	}
 
+	s.sign(req, accessKey, secretKey, sessionToken, region, signingTime(s.Now))
+	return req
+}
+
+// sign signs req at time t and returns the request signature
+func (s *V4Signer) sign(req *http.Request, accessKey, secretKey, sessionToken, region string, t time.Time) string {
 	// Set timestamp
-	t := time.Now().UTC()
 	req.Header.Set("X-Amz-Date", t.Format(iso8601DateFormat))
 
 	// Attach session token
@@ -68,7 +76,7 @@ func (s *V4Signer) Sign(req *http.Request, accessKey, secretKey, sessionToken, r
 	auth := s.buildAuthorizationHeader(req, accessKey, region, signature, t)
 	req.Header.Set("Authorization", auth)
 
-	return req
+	return signature
 }
 
 // Presign generates a Signature V4 presigned request
@@ -80,7 +88,7 @@ func (s *V4Signer) Presign(req *http.Request, accessKey, secretKey, sessionToken
 	}
 
 	// Initialize timestamp
-	t := time.Now().UTC()
+	t := signingTime(s.Now)
 
 	// Build credential string
 	credential := s.getCredential(accessKey, region, t)
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// recordedSpanKey is the context key of the current RecordedSpan
+type recordedSpanKey struct{}
+
+// Recorder is an in-memory Tracer that keeps ended spans, for tests and debugging
+type Recorder struct {
+	mu    sync.Mutex
+	ended []*RecordedSpan
+}
+
+// RecordedSpan is a span kept by a Recorder
+type RecordedSpan struct {
+	Name       string
+	Parent     *RecordedSpan
+	Attributes map[string]any
+	Err        error
+	StartTime  time.Time
+	EndTime    time.Time
+
+	recorder *Recorder
+}
+
+// NewRecorder returns an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Start implements Tracer
+func (r *Recorder) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	parent, _ := ctx.Value(recordedSpanKey{}).(*RecordedSpan)
+	span := &RecordedSpan{
+		Name:       name,
+		Parent:     parent,
+		Attributes: make(map[string]any),
+		StartTime:  time.Now(),
+		recorder:   r,
+	}
+	span.SetAttributes(attrs...)
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+// Spans returns the ended spans in the order they ended
+func (r *Recorder) Spans() []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*RecordedSpan(nil), r.ended...)
+}
+
+// SetAttributes implements Span
+func (s *RecordedSpan) SetAttributes(attrs ...Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, attr := range attrs {
+		s.Attributes[attr.Key] = attr.Value
+	}
+}
+
+// RecordError implements Span
+func (s *RecordedSpan) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.Err = err
+}
+
+// End implements Span
+func (s *RecordedSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.EndTime = time.Now()
+	s.recorder.ended = append(s.recorder.ended, s)
+}
@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+
+	ctx, parent := recorder.Start(context.Background(), "Object.Put", String(AttrBucket, "bucket"))
+	_, child := recorder.Start(ctx, "PUT", Int(AttrResendCount, 0))
+	child.SetAttributes(Int(AttrStatusCode, 200))
+	child.End()
+	parent.RecordError(errors.New("failed"))
+	parent.End()
+
+	spans := recorder.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if spans[0].Name != "PUT" || spans[0].Parent != spans[1] {
+		t.Errorf("child span = %s with parent %v", spans[0].Name, spans[0].Parent)
+	}
+	if got := spans[0].Attributes[AttrStatusCode]; got != int64(200) {
+		t.Errorf("status attribute = %v, want 200", got)
+	}
+	if spans[1].Attributes[AttrBucket] != "bucket" || spans[1].Err == nil {
+		t.Errorf("parent span = %+v", spans[1])
+	}
+}
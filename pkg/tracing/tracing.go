@@ -0,0 +1,101 @@
+// Package tracing defines the tracer interface the SDK reports spans to.
+//
+// Every SDK call is traced as a span with one child span per HTTP attempt.
+// The interface is small enough to bridge to OpenTelemetry or another
+// tracing system without the SDK depending on it:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attrs)...))
+//		return ctx, otelSpan{span}
+//	}
+package tracing
+
+import (
+	"context"
+	"time"
+)
+
+// Attribute keys set on spans. Where one exists, the key follows the
+// OpenTelemetry semantic conventions.
+const (
+	// AttrOperation is the S3 operation, e.g. PutObject
+	AttrOperation = "rpc.method"
+	// AttrBucket is the bucket name
+	AttrBucket = "aws.s3.bucket"
+	// AttrKey is the object name
+	AttrKey = "aws.s3.key"
+	// AttrMethod is the HTTP method of an attempt
+	AttrMethod = "http.request.method"
+	// AttrURL is the URL of an attempt, without query string
+	AttrURL = "url.full"
+	// AttrStatusCode is the HTTP status of the response
+	AttrStatusCode = "http.response.status_code"
+	// AttrErrorCode is the S3 error code of a failed request
+	AttrErrorCode = "aws.s3.error_code"
+	// AttrRequestID is the x-amz-request-id of the response
+	AttrRequestID = "aws.request_id"
+	// AttrResendCount is the zero-based number of an attempt
+	AttrResendCount = "http.request.resend_count"
+	// AttrRetryCount is the number of retries made during a call
+	AttrRetryCount = "rustfs.retry_count"
+	// AttrBytesSent is the number of request body bytes sent
+	AttrBytesSent = "rustfs.bytes_sent"
+	// AttrBytesReceived is the number of response body bytes announced by the server
+	AttrBytesReceived = "rustfs.bytes_received"
+	// AttrTimingPrefix prefixes connection timings of an attempt, in milliseconds,
+	// e.g. rustfs.timing.dns_lookup_ms
+	AttrTimingPrefix = "rustfs.timing."
+)
+
+// Attribute is a key/value pair attached to a span. Value is a string,
+// bool, int64 or float64.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 returns an integer attribute
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Milliseconds returns a duration attribute in milliseconds
+func Milliseconds(key string, value time.Duration) Attribute {
+	return Attribute{Key: key, Value: float64(value) / float64(time.Millisecond)}
+}
+
+// Tracer starts spans
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns
+	// a context carrying the new span
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation being traced
+type Span interface {
+	// SetAttributes adds attributes to the span
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError marks the span as failed with err
+	RecordError(err error)
+
+	// End completes the span
+	End()
+}
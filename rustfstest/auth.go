@@ -0,0 +1,324 @@
+// Package rustfstest rustfstest/auth.go
+package rustfstest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+)
+
+const (
+	signV4Algorithm   = "AWS4-HMAC-SHA256"
+	iso8601DateFormat = "20060102T150405Z"
+
+	unsignedPayload        = "UNSIGNED-PAYLOAD"
+	streamingPayload       = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	unsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	emptySHA256            = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// authorization is a verified SigV4 signature, kept to verify the chunk
+// signatures of a streaming upload
+type authorization struct {
+	signingKey []byte
+	time       time.Time
+	scope      string
+	signature  string
+}
+
+// authenticate verifies the SigV4 signature of r, in the Authorization
+// header or presigned query. It returns nil for anonymous requests.
+func (s *Server) authenticate(r *http.Request) (*authorization, error) {
+	header := r.Header.Get("Authorization")
+	query := r.URL.Query()
+	switch {
+	case strings.HasPrefix(header, signV4Algorithm+" "):
+		return s.verifyHeader(r, header)
+	case query.Get("X-Amz-Algorithm") == signV4Algorithm:
+		return s.verifyPresigned(r, query)
+	case header != "" || query.Get("X-Amz-Algorithm") != "" || query.Get("Signature") != "":
+		return nil, errorf(http.StatusBadRequest, "InvalidRequest", "Only AWS4-HMAC-SHA256 signatures are supported.")
+	default:
+		return nil, nil
+	}
+}
+
+// verifyHeader verifies a signature in the Authorization header
+func (s *Server) verifyHeader(r *http.Request, header string) (*authorization, error) {
+	fields := make(map[string]string)
+	for _, field := range strings.Split(strings.TrimPrefix(header, signV4Algorithm+" "), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		fields[name] = value
+	}
+	signedAt, err := time.Parse(iso8601DateFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Missing or invalid X-Amz-Date.")
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return nil, errorf(http.StatusBadRequest, "InvalidRequest", "Missing required header for this request: x-amz-content-sha256.")
+	}
+	return s.verify(r, r.URL.Query(), fields["Credential"], fields["SignedHeaders"], fields["Signature"], signedAt, payloadHash)
+}
+
+// verifyPresigned verifies the signature of a presigned URL
+func (s *Server) verifyPresigned(r *http.Request, query url.Values) (*authorization, error) {
+	signedAt, err := time.Parse(iso8601DateFormat, query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationQueryParametersError", "Invalid X-Amz-Date.")
+	}
+	expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil || expires < 0 {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationQueryParametersError", "Invalid X-Amz-Expires.")
+	}
+	if s.config.Now().After(signedAt.Add(time.Duration(expires) * time.Second)) {
+		return nil, errorf(http.StatusForbidden, "AccessDenied", "Request has expired.")
+	}
+
+	signature := query.Get("X-Amz-Signature")
+	canonicalQuery := make(url.Values, len(query))
+	for name, values := range query {
+		if name != "X-Amz-Signature" {
+			canonicalQuery[name] = values
+		}
+	}
+	return s.verify(r, canonicalQuery, query.Get("X-Amz-Credential"), query.Get("X-Amz-SignedHeaders"), signature, signedAt, unsignedPayload)
+}
+
+// verify checks the credential scope and signature of a request signed at
+// signedAt over query, the signed headers and payloadHash
+func (s *Server) verify(r *http.Request, query url.Values, credential, signedHeaders, signature string, signedAt time.Time, payloadHash string) (*authorization, error) {
+	// Credential is <access key>/<date>/<region>/s3/aws4_request
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[3] != "s3" || parts[4] != "aws4_request" {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Malformed credential %q.", credential)
+	}
+	if parts[0] != s.config.AccessKey {
+		return nil, errInvalidAccessKeyID
+	}
+	if parts[1] != signedAt.Format("20060102") {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Credential date %s does not match the signing date.", parts[1])
+	}
+	if parts[2] != s.config.Region {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "The region '%s' is wrong; expecting '%s'.", parts[2], s.config.Region)
+	}
+	if skew := s.config.Now().Sub(signedAt); skew > maxClockSkew || skew < -maxClockSkew {
+		return nil, errRequestTimeTooSkewed
+	}
+	if signedHeaders == "" || signature == "" {
+		return nil, errorf(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Missing SignedHeaders or Signature.")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		canonicalURI(r.URL.Path),
+		strings.ReplaceAll(query.Encode(), "+", "%20"),
+		canonicalHeaders(r, strings.Split(signedHeaders, ";")),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join(parts[1:], "/")
+	stringToSign := strings.Join([]string{signV4Algorithm, signedAt.Format(iso8601DateFormat), scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretKey), []byte(parts[1]))
+	for _, part := range parts[2:] {
+		signingKey = hmacSHA256(signingKey, []byte(part))
+	}
+	if !hmac.Equal([]byte(hex.EncodeToString(hmacSHA256(signingKey, []byte(stringToSign)))), []byte(signature)) {
+		return nil, errSignatureMismatch
+	}
+	return &authorization{signingKey: signingKey, time: signedAt, scope: scope, signature: signature}, nil
+}
+
+// canonicalURI encodes each path segment as the SDK does when signing
+func canonicalURI(path string) string {
+	var b strings.Builder
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			b.WriteString("/" + url.PathEscape(segment))
+		}
+	}
+	if b.Len() == 0 {
+		return "/"
+	}
+	if strings.HasSuffix(path, "/") {
+		b.WriteString("/")
+	}
+	return b.String()
+}
+
+// canonicalHeaders returns the canonical form of the signed headers
+func canonicalHeaders(r *http.Request, names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":")
+		if name == "host" {
+			b.WriteString(r.Host)
+		} else {
+			values := r.Header.Values(name)
+			for i, value := range values {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(strings.Join(strings.Fields(value), " "))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// readBody reads the payload of r, decoding aws-chunked uploads and
+// verifying the payload hash, chunk signatures, Content-MD5 and checksums
+func (s *Server) readBody(r *http.Request, auth *authorization) ([]byte, error) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "IncompleteBody", "%v", err)
+	}
+
+	body := raw
+	trailer := make(http.Header)
+	switch payloadHash := r.Header.Get("X-Amz-Content-Sha256"); {
+	case payloadHash == streamingPayload:
+		if auth == nil {
+			return nil, errAccessDenied
+		}
+		if body, err = decodeChunks(raw, auth, trailer); err != nil {
+			return nil, err
+		}
+	case payloadHash == unsignedPayloadTrailer:
+		if body, err = decodeChunks(raw, nil, trailer); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(payloadHash, "STREAMING-"):
+		return nil, errNotImplemented
+	case auth == nil || payloadHash == "" || payloadHash == unsignedPayload:
+	case payloadHash != sha256Hex(raw):
+		return nil, errorf(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed.")
+	}
+
+	if decoded := r.Header.Get("X-Amz-Decoded-Content-Length"); decoded != "" && decoded != strconv.Itoa(len(body)) {
+		return nil, errorf(http.StatusBadRequest, "IncompleteBody", "Expected %s decoded bytes, got %d.", decoded, len(body))
+	}
+	if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+		sum := md5.Sum(body)
+		if contentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+			return nil, errorf(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received.")
+		}
+	}
+
+	// Trailing checksums are verified and then handled like headers
+	for name, values := range trailer {
+		r.Header[name] = values
+	}
+	for name := range r.Header {
+		algorithm, ok := checksum.FromHeaderKey(name)
+		if !ok {
+			continue
+		}
+		h := checksum.New(algorithm)
+		h.Write(body)
+		if r.Header.Get(name) != checksum.Encode(h.Sum(nil)) {
+			return nil, errorf(http.StatusBadRequest, "BadDigest", "The %s you specified did not match the calculated checksum.", algorithm)
+		}
+	}
+	return body, nil
+}
+
+// decodeChunks decodes an aws-chunked payload, verifying the chain of chunk
+// signatures when auth is set and collecting trailing headers into trailer
+func decodeChunks(raw []byte, auth *authorization, trailer http.Header) ([]byte, error) {
+	malformed := errorf(http.StatusBadRequest, "IncompleteBody", "The aws-chunked payload is malformed.")
+	reader := bufio.NewReader(bytes.NewReader(raw))
+	previous := ""
+	if auth != nil {
+		previous = auth.signature
+	}
+
+	var body bytes.Buffer
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil || !strings.HasSuffix(line, "\r\n") {
+			return nil, malformed
+		}
+		sizeHex, extension, _ := strings.Cut(strings.TrimSuffix(line, "\r\n"), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || size < 0 || size > int64(len(raw)) {
+			return nil, malformed
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, malformed
+		}
+
+		if auth != nil {
+			signature, ok := strings.CutPrefix(extension, "chunk-signature=")
+			stringToSign := strings.Join([]string{signV4Algorithm + "-PAYLOAD", auth.time.Format(iso8601DateFormat), auth.scope, previous, emptySHA256, sha256Hex(chunk)}, "\n")
+			expected := hex.EncodeToString(hmacSHA256(auth.signingKey, []byte(stringToSign)))
+			if !ok || !hmac.Equal([]byte(signature), []byte(expected)) {
+				return nil, errSignatureMismatch
+			}
+			previous = signature
+		}
+		body.Write(chunk)
+
+		if size == 0 {
+			break
+		}
+		if crlf, err := reader.ReadString('\n'); err != nil || crlf != "\r\n" {
+			return nil, malformed
+		}
+	}
+
+	// The final chunk is followed by trailers, if any, and an empty line
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, malformed
+		}
+		line = strings.TrimSuffix(line, "\r\n")
+		if line == "" {
+			return body.Bytes(), nil
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, malformed
+		}
+		trailer.Set(name, strings.TrimSpace(value))
+	}
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+// Package rustfstest rustfstest/auth_test.go
+package rustfstest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	rustfs "github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/errors"
+	objectapi "github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+)
+
+func TestSignatureMismatch(t *testing.T) {
+	s := NewServer(Config{})
+	defer s.Close()
+
+	opts := s.Options()
+	opts.Credentials = credentials.NewStaticV4(DefaultAccessKey, "wrong-secret", "")
+	opts.MaxRetries = 1
+	client, err := rustfs.New(s.Endpoint(), opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := client.Bucket().Create(context.Background(), "bucket"); errCode(err) != "SignatureDoesNotMatch" {
+		t.Errorf("Create() error = %v, want SignatureDoesNotMatch", err)
+	}
+
+	opts.Credentials = credentials.NewStaticV4("unknown", DefaultSecretKey, "")
+	client, err = rustfs.New(s.Endpoint(), opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := client.Bucket().Create(context.Background(), "bucket"); errCode(err) != "InvalidAccessKeyId" {
+		t.Errorf("Create() error = %v, want InvalidAccessKeyId", err)
+	}
+}
+
+func TestStreamingUpload(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+	data := strings.Repeat("streamed ", 1<<16)
+
+	tests := []struct {
+		name string
+		opts []objectapi.PutOption
+	}{
+		{name: "signed chunks"},
+		{name: "checksum trailer", opts: []objectapi.PutOption{objectapi.WithChecksumAlgorithm("SHA256")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A reader that cannot seek is sent aws-chunked
+			body := io.MultiReader(strings.NewReader(data))
+			if _, err := client.Object().Put(ctx, bucket, "streamed", body, int64(len(data)), tt.opts...); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if got, _ := get(t, client, bucket, "streamed"); got != data {
+				t.Errorf("Get() returned %d bytes, want %d", len(got), len(data))
+			}
+		})
+	}
+}
+
+func TestPresign(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+	put(t, client, bucket, "shared", "presigned")
+
+	u, _, err := client.Object().PresignGet(ctx, bucket, "shared", time.Minute, nil)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	resp, err := http.Get(u.String())
+	if err != nil {
+		t.Fatalf("GET %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "presigned" {
+		t.Errorf("GET presigned URL = %d %q", resp.StatusCode, body)
+	}
+
+	tampered := strings.Replace(u.String(), "shared", "other", 1)
+	resp, err = http.Get(tampered)
+	if err != nil {
+		t.Fatalf("GET %s: %v", tampered, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET tampered URL status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestAnonymousPolicy(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(Config{})
+	defer s.Close()
+	client := s.NewClient(t)
+	if err := client.Bucket().Create(ctx, "public"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	put(t, client, "public", "index.html", "<html></html>")
+
+	status := func() int {
+		resp, err := http.Get(s.URL + "/public/index.html")
+		if err != nil {
+			t.Fatalf("anonymous GET: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := status(); got != http.StatusForbidden {
+		t.Errorf("anonymous GET without policy = %d, want 403", got)
+	}
+
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::public/*"]}]}`
+	if err := client.Bucket().SetPolicy(ctx, "public", policy); err != nil {
+		t.Fatalf("SetPolicy() error = %v", err)
+	}
+	if got := status(); got != http.StatusOK {
+		t.Errorf("anonymous GET with policy = %d, want 200", got)
+	}
+	resp, err := http.Post(s.URL+"/public/index.html?uploads", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("anonymous POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("anonymous POST = %d, want 403", resp.StatusCode)
+	}
+
+	if err := client.Bucket().SetPolicy(ctx, "public", "not json"); errCode(err) != "MalformedPolicy" {
+		t.Errorf("SetPolicy() of invalid JSON error = %v, want MalformedPolicy", err)
+	}
+	if _, err := client.Bucket().GetPolicy(ctx, "missing"); !errors.IsBucketNotFound(err) {
+		t.Errorf("GetPolicy() of a missing bucket error = %v", err)
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	s := NewServer(Config{Now: func() time.Time { return time.Now().Add(time.Hour) }})
+	defer s.Close()
+	client := s.NewClient(t)
+
+	if err := client.Bucket().Create(context.Background(), "skewed"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if offset := client.ClockOffset(); offset < 59*time.Minute || offset > 61*time.Minute {
+		t.Errorf("ClockOffset() = %v, want about an hour", offset)
+	}
+}
@@ -0,0 +1,454 @@
+// Package rustfstest rustfstest/bucket.go
+package rustfstest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// bucket is a bucket with its object versions and multipart uploads
+type bucket struct {
+	name    string
+	created time.Time
+	// versioning is "", Enabled or Suspended
+	versioning string
+	policy     []byte
+	tags       map[string]string
+	// objects holds the versions of each key, oldest first
+	objects map[string][]*object
+	uploads map[string]*upload
+}
+
+// object is a version of an object, or a delete marker
+type object struct {
+	key string
+	// versionID is empty for objects written before versioning was
+	// configured and "null" for those written while it was suspended
+	versionID    string
+	deleteMarker bool
+	data         []byte
+	etag         string
+	modified     time.Time
+	// header holds the content headers, user metadata and checksums
+	header http.Header
+	tags   map[string]string
+}
+
+// latest returns the current version of key, nil if there is none
+func (b *bucket) latest(key string) *object {
+	versions := b.objects[key]
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions[len(versions)-1]
+}
+
+// version returns the version of key with versionID; "null" also matches
+// objects written before versioning was configured
+func (b *bucket) version(key, versionID string) *object {
+	for _, obj := range b.objects[key] {
+		if obj.versionID == versionID || (versionID == "null" && obj.versionID == "") {
+			return obj
+		}
+	}
+	return nil
+}
+
+// put adds obj as the current version of its key. Without versioning
+// enabled it replaces the null version.
+func (b *bucket) put(obj *object) {
+	versions := b.objects[obj.key]
+	if b.versioning == "Enabled" {
+		obj.versionID = newID()
+	} else {
+		if b.versioning == "Suspended" {
+			obj.versionID = "null"
+		}
+		kept := versions[:0]
+		for _, v := range versions {
+			if v.versionID != "" && v.versionID != "null" {
+				kept = append(kept, v)
+			}
+		}
+		versions = kept
+	}
+	b.objects[obj.key] = append(versions, obj)
+}
+
+// remove deletes a version of key, reporting the removed version
+func (b *bucket) remove(key, versionID string) *object {
+	versions := b.objects[key]
+	for i, obj := range versions {
+		if obj.versionID == versionID || (versionID == "null" && obj.versionID == "") {
+			versions = append(versions[:i], versions[i+1:]...)
+			if len(versions) == 0 {
+				delete(b.objects, key)
+			} else {
+				b.objects[key] = versions
+			}
+			return obj
+		}
+	}
+	return nil
+}
+
+// bucket returns the bucket named name
+func (s *Server) bucket(name string) (*bucket, error) {
+	b, ok := s.buckets[name]
+	if !ok {
+		return nil, errNoSuchBucket
+	}
+	return b, nil
+}
+
+// bucketNamePattern matches DNS-compatible bucket names
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+func validBucketName(name string) bool {
+	return bucketNamePattern.MatchString(name) && !strings.Contains(name, "..") && net.ParseIP(name) == nil
+}
+
+// serveBucket dispatches a bucket request
+func (s *Server) serveBucket(w http.ResponseWriter, r *request) error {
+	q := r.query
+	switch r.Method {
+	case http.MethodHead:
+		_, err := s.bucket(r.bucket)
+		if err == nil {
+			w.WriteHeader(http.StatusOK)
+		}
+		return err
+	case http.MethodPut:
+		switch {
+		case q.Has("versioning"):
+			return s.putVersioning(w, r)
+		case q.Has("policy"):
+			return s.putPolicy(w, r)
+		case q.Has("tagging"):
+			return s.putBucketTagging(w, r)
+		case hasUnsupported(q):
+			return errNotImplemented
+		}
+		return s.createBucket(w, r)
+	case http.MethodGet:
+		switch {
+		case q.Has("versioning"):
+			return s.getVersioning(w, r)
+		case q.Has("policy"):
+			return s.getPolicy(w, r)
+		case q.Has("tagging"):
+			return s.getBucketTagging(w, r)
+		case q.Has("location"):
+			return s.getLocation(w, r)
+		case q.Has("uploads"):
+			return s.listUploads(w, r)
+		case q.Has("versions"):
+			return s.listVersions(w, r)
+		case hasUnsupported(q):
+			return errNotImplemented
+		case q.Get("list-type") == "2":
+			return s.listObjectsV2(w, r)
+		}
+		return s.listObjects(w, r)
+	case http.MethodDelete:
+		switch {
+		case q.Has("policy"):
+			return s.deletePolicy(w, r)
+		case q.Has("tagging"):
+			return s.deleteBucketTagging(w, r)
+		case hasUnsupported(q):
+			return errNotImplemented
+		}
+		return s.deleteBucket(w, r)
+	case http.MethodPost:
+		if q.Has("delete") {
+			return s.deleteObjects(w, r)
+		}
+		return errNotImplemented
+	}
+	return errMethodNotAllowed
+}
+
+// supportedParams are the query parameters that do not select a subresource
+var supportedParams = map[string]bool{
+	"prefix": true, "delimiter": true, "max-keys": true, "list-type": true,
+	"start-after": true, "continuation-token": true, "encoding-type": true,
+	"fetch-owner": true, "marker": true, "metadata": true,
+	"versionId": true, "partNumber": true, "uploadId": true,
+	"part-number-marker": true, "max-parts": true,
+	"key-marker": true, "version-id-marker": true,
+	"upload-id-marker": true, "max-uploads": true,
+	"response-content-type": true, "response-content-language": true,
+	"response-expires": true, "response-cache-control": true,
+	"response-content-disposition": true, "response-content-encoding": true,
+}
+
+// hasUnsupported reports whether the query selects a subresource the
+// server does not implement
+func hasUnsupported(q map[string][]string) bool {
+	for name := range q {
+		if !supportedParams[name] && !strings.HasPrefix(name, "X-Amz-") {
+			return true
+		}
+	}
+	return false
+}
+
+// owner is the owner of all buckets and objects
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+func (s *Server) owner() owner {
+	return owner{ID: s.config.AccessKey, DisplayName: s.config.AccessKey}
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name      `xml:"ListAllMyBucketsResult"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Owner   owner         `xml:"Owner"`
+	Buckets []bucketEntry `xml:"Buckets>Bucket"`
+}
+
+type bucketEntry struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+func (s *Server) listBuckets(w http.ResponseWriter, r *request) error {
+	if hasUnsupported(r.query) {
+		return errNotImplemented
+	}
+	result := listAllMyBucketsResult{Xmlns: s3Namespace, Owner: s.owner()}
+	for _, name := range sortedKeys(s.buckets) {
+		result.Buckets = append(result.Buckets, bucketEntry{Name: name, CreationDate: iso8601(s.buckets[name].created)})
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+type createBucketConfiguration struct {
+	XMLName  xml.Name `xml:"CreateBucketConfiguration"`
+	Location string   `xml:"LocationConstraint"`
+}
+
+func (s *Server) createBucket(w http.ResponseWriter, r *request) error {
+	if !validBucketName(r.bucket) {
+		return errorf(http.StatusBadRequest, "InvalidBucketName", "The specified bucket is not valid.")
+	}
+	if len(r.body) > 0 {
+		var config createBucketConfiguration
+		if err := decodeXML(r.body, &config); err != nil {
+			return err
+		}
+		if config.Location != "" && config.Location != s.config.Region {
+			return errorf(http.StatusBadRequest, "InvalidLocationConstraint", "The specified location-constraint is not valid for this server's region %s.", s.config.Region)
+		}
+	}
+	if _, ok := s.buckets[r.bucket]; ok && r.Header.Get("x-rustfs-force-create") != "true" {
+		return errorf(http.StatusConflict, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.")
+	}
+
+	b := &bucket{
+		name:    r.bucket,
+		created: s.now(),
+		objects: make(map[string][]*object),
+		uploads: make(map[string]*upload),
+	}
+	if r.Header.Get("x-amz-bucket-object-lock-enabled") == "true" {
+		b.versioning = "Enabled"
+	}
+	s.buckets[r.bucket] = b
+	w.Header().Set("Location", "/"+r.bucket)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (s *Server) deleteBucket(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	if (len(b.objects) > 0 || len(b.uploads) > 0) && r.Header.Get("x-rustfs-force-delete") != "true" {
+		return errorf(http.StatusConflict, "BucketNotEmpty", "The bucket you tried to delete is not empty.")
+	}
+	delete(s.buckets, r.bucket)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"LocationConstraint"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:",chardata"`
+}
+
+func (s *Server) getLocation(w http.ResponseWriter, r *request) error {
+	if _, err := s.bucket(r.bucket); err != nil {
+		return err
+	}
+	// us-east-1 is reported as an empty location constraint
+	location := s.config.Region
+	if location == "us-east-1" {
+		location = ""
+	}
+	writeXML(w, http.StatusOK, locationConstraint{Xmlns: s3Namespace, Location: location})
+	return nil
+}
+
+type versioningConfiguration struct {
+	XMLName   xml.Name `xml:"VersioningConfiguration"`
+	Xmlns     string   `xml:"xmlns,attr"`
+	Status    string   `xml:"Status,omitempty"`
+	MFADelete string   `xml:"MfaDelete,omitempty"`
+}
+
+func (s *Server) putVersioning(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	var config versioningConfiguration
+	if err := decodeXML(r.body, &config); err != nil {
+		return err
+	}
+	if config.Status != "Enabled" && config.Status != "Suspended" {
+		return errMalformedXML
+	}
+	b.versioning = config.Status
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (s *Server) getVersioning(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	writeXML(w, http.StatusOK, versioningConfiguration{Xmlns: s3Namespace, Status: b.versioning})
+	return nil
+}
+
+func (s *Server) putPolicy(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	var doc policyDocument
+	if err := json.Unmarshal(r.body, &doc); err != nil || len(doc.Statement) == 0 {
+		return errorf(http.StatusBadRequest, "MalformedPolicy", "Policies must be valid JSON with at least one statement.")
+	}
+	b.policy = append([]byte(nil), r.body...)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	if b.policy == nil {
+		return errorf(http.StatusNotFound, "NoSuchBucketPolicy", "The bucket policy does not exist.")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b.policy)
+	return nil
+}
+
+func (s *Server) deletePolicy(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	b.policy = nil
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) putBucketTagging(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	tags, err := decodeTagging(r.body, maxBucketTags)
+	if err != nil {
+		return err
+	}
+	b.tags = tags
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) getBucketTagging(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	if len(b.tags) == 0 {
+		return errorf(http.StatusNotFound, "NoSuchTagSet", "The TagSet does not exist.")
+	}
+	writeXML(w, http.StatusOK, encodeTagging(b.tags))
+	return nil
+}
+
+func (s *Server) deleteBucketTagging(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	b.tags = nil
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+const (
+	maxBucketTags = 50
+	maxObjectTags = 10
+)
+
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	TagSet  []tag    `xml:"TagSet>Tag"`
+}
+
+type tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// decodeTagging decodes a Tagging document of at most limit tags
+func decodeTagging(body []byte, limit int) (map[string]string, error) {
+	var doc tagging
+	if err := decodeXML(body, &doc); err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(doc.TagSet))
+	for _, t := range doc.TagSet {
+		if _, dup := tags[t.Key]; dup || t.Key == "" {
+			return nil, errorf(http.StatusBadRequest, "InvalidTag", "Tag keys must be unique and not empty.")
+		}
+		tags[t.Key] = t.Value
+	}
+	if len(tags) > limit {
+		return nil, errorf(http.StatusBadRequest, "InvalidTag", "Object tags cannot be greater than %d.", limit)
+	}
+	return tags, nil
+}
+
+// encodeTagging returns the Tagging document of tags, sorted by key
+func encodeTagging(tags map[string]string) tagging {
+	doc := tagging{Xmlns: s3Namespace, TagSet: []tag{}}
+	for _, key := range sortedKeys(tags) {
+		doc.TagSet = append(doc.TagSet, tag{Key: key, Value: tags[key]})
+	}
+	return doc
+}
@@ -0,0 +1,8 @@
+// Package rustfstest provides an in-memory S3-compatible server for testing
+// code built on the RustFS Go SDK without a running RustFS instance.
+//
+// NewClient starts a server for the duration of a test and returns a client
+// configured for it. The server keeps buckets, object versions, multipart
+// uploads, tags and bucket policies in memory and verifies request
+// signatures, so tests exercise the same code paths as against RustFS.
+package rustfstest
@@ -0,0 +1,396 @@
+// Package rustfstest rustfstest/list.go
+package rustfstest
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxListEntries is the default and largest page size of a listing
+const maxListEntries = 1000
+
+// pager counts the entries of a listing page
+type pager struct {
+	max       int
+	count     int
+	truncated bool
+}
+
+// add reports whether another entry fits on the page, marking the page
+// truncated when it does not
+func (p *pager) add() bool {
+	if p.count >= p.max {
+		p.truncated = true
+		return false
+	}
+	p.count++
+	return true
+}
+
+// maxEntries parses a page size query parameter such as max-keys
+func maxEntries(r *request, name string) (int, error) {
+	value := r.query.Get(name)
+	if value == "" {
+		return maxListEntries, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errorf(http.StatusBadRequest, "InvalidArgument", "Argument %s must be an integer between 0 and 2147483647.", name)
+	}
+	return min(n, maxListEntries), nil
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// commonPrefixOf returns the common prefix key is rolled up into, or ""
+// when it is listed on its own
+func commonPrefixOf(key, prefix, delimiter string) string {
+	if delimiter == "" {
+		return ""
+	}
+	rest := strings.TrimPrefix(key, prefix)
+	i := strings.Index(rest, delimiter)
+	if i < 0 {
+		return ""
+	}
+	return prefix + rest[:i+len(delimiter)]
+}
+
+// storageClass returns the storage class stored in header
+func storageClass(header http.Header) string {
+	if class := header.Get("X-Amz-Storage-Class"); class != "" {
+		return class
+	}
+	return "STANDARD"
+}
+
+type objectEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+	Owner        *owner `xml:"Owner,omitempty"`
+}
+
+// listing is a page of current objects and common prefixes
+type listing struct {
+	contents []*object
+	prefixes []string
+	// next is the last key or prefix on a truncated page
+	next      string
+	truncated bool
+}
+
+// listLatest lists the current objects of b after marker
+func (b *bucket) listLatest(prefix, delimiter, marker string, maxKeys int) listing {
+	var l listing
+	page := pager{max: maxKeys}
+	var last string
+	for _, key := range sortedKeys(b.objects) {
+		obj := b.latest(key)
+		if obj.deleteMarker || !strings.HasPrefix(key, prefix) || key <= marker {
+			continue
+		}
+		if cp := commonPrefixOf(key, prefix, delimiter); cp != "" {
+			if cp == last || strings.HasPrefix(marker, cp) {
+				continue
+			}
+			if !page.add() {
+				break
+			}
+			l.prefixes = append(l.prefixes, cp)
+			last = cp
+			continue
+		}
+		if !page.add() {
+			break
+		}
+		l.contents = append(l.contents, obj)
+		last = key
+	}
+	if l.truncated = page.truncated; l.truncated {
+		l.next = last
+	}
+	return l
+}
+
+// listEncoder encodes keys as requested by encoding-type
+func listEncoder(r *request) (func(string) string, error) {
+	switch encoding := r.query.Get("encoding-type"); encoding {
+	case "":
+		return func(s string) string { return s }, nil
+	case "url":
+		return func(s string) string { return strings.ReplaceAll(url.QueryEscape(s), "+", "%20") }, nil
+	default:
+		return nil, errorf(http.StatusBadRequest, "InvalidArgument", "Invalid Encoding Method specified in Request.")
+	}
+}
+
+func (s *Server) objectEntry(obj *object, encode func(string) string, withOwner bool) objectEntry {
+	entry := objectEntry{
+		Key:          encode(obj.key),
+		LastModified: iso8601(obj.modified),
+		ETag:         `"` + obj.etag + `"`,
+		Size:         len(obj.data),
+		StorageClass: storageClass(obj.header),
+	}
+	if withOwner {
+		o := s.owner()
+		entry.Owner = &o
+	}
+	return entry
+}
+
+type listBucketResult struct {
+	XMLName        xml.Name       `xml:"ListBucketResult"`
+	Xmlns          string         `xml:"xmlns,attr"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Marker         string         `xml:"Marker"`
+	NextMarker     string         `xml:"NextMarker,omitempty"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	Delimiter      string         `xml:"Delimiter,omitempty"`
+	EncodingType   string         `xml:"EncodingType,omitempty"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	Contents       []objectEntry  `xml:"Contents"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+}
+
+// listObjects implements ListObjects (version 1)
+func (s *Server) listObjects(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	encode, err := listEncoder(r)
+	if err != nil {
+		return err
+	}
+	maxKeys, err := maxEntries(r, "max-keys")
+	if err != nil {
+		return err
+	}
+	q := r.query
+	prefix, delimiter, marker := q.Get("prefix"), q.Get("delimiter"), q.Get("marker")
+
+	l := b.listLatest(prefix, delimiter, marker, maxKeys)
+	result := listBucketResult{
+		Xmlns:        s3Namespace,
+		Name:         b.name,
+		Prefix:       encode(prefix),
+		Marker:       encode(marker),
+		NextMarker:   encode(l.next),
+		MaxKeys:      maxKeys,
+		Delimiter:    encode(delimiter),
+		EncodingType: q.Get("encoding-type"),
+		IsTruncated:  l.truncated,
+	}
+	for _, obj := range l.contents {
+		result.Contents = append(result.Contents, s.objectEntry(obj, encode, true))
+	}
+	for _, cp := range l.prefixes {
+		result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: encode(cp)})
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+type listBucketV2Result struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Xmlns                 string         `xml:"xmlns,attr"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	EncodingType          string         `xml:"EncodingType,omitempty"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	Contents              []objectEntry  `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+// listObjectsV2 implements ListObjectsV2. Continuation tokens encode the
+// last key or prefix of the previous page.
+func (s *Server) listObjectsV2(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	encode, err := listEncoder(r)
+	if err != nil {
+		return err
+	}
+	maxKeys, err := maxEntries(r, "max-keys")
+	if err != nil {
+		return err
+	}
+	q := r.query
+	prefix, delimiter, startAfter, token := q.Get("prefix"), q.Get("delimiter"), q.Get("start-after"), q.Get("continuation-token")
+
+	marker := startAfter
+	if q.Has("continuation-token") {
+		decoded, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil || token == "" {
+			return errorf(http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect.")
+		}
+		marker = max(marker, string(decoded))
+	}
+
+	l := b.listLatest(prefix, delimiter, marker, maxKeys)
+	result := listBucketV2Result{
+		Xmlns:             s3Namespace,
+		Name:              b.name,
+		Prefix:            encode(prefix),
+		StartAfter:        encode(startAfter),
+		ContinuationToken: token,
+		KeyCount:          len(l.contents) + len(l.prefixes),
+		MaxKeys:           maxKeys,
+		Delimiter:         encode(delimiter),
+		EncodingType:      q.Get("encoding-type"),
+		IsTruncated:       l.truncated,
+	}
+	if l.truncated {
+		result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(l.next))
+	}
+	withOwner := q.Get("fetch-owner") == "true"
+	for _, obj := range l.contents {
+		result.Contents = append(result.Contents, s.objectEntry(obj, encode, withOwner))
+	}
+	for _, cp := range l.prefixes {
+		result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: encode(cp)})
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+type listVersionsResult struct {
+	XMLName             xml.Name       `xml:"ListVersionsResult"`
+	Xmlns               string         `xml:"xmlns,attr"`
+	Name                string         `xml:"Name"`
+	Prefix              string         `xml:"Prefix"`
+	KeyMarker           string         `xml:"KeyMarker"`
+	VersionIDMarker     string         `xml:"VersionIdMarker"`
+	NextKeyMarker       string         `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string         `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int            `xml:"MaxKeys"`
+	Delimiter           string         `xml:"Delimiter,omitempty"`
+	EncodingType        string         `xml:"EncodingType,omitempty"`
+	IsTruncated         bool           `xml:"IsTruncated"`
+	Versions            []versionEntry `xml:"Version"`
+	DeleteMarkers       []versionEntry `xml:"DeleteMarker"`
+	CommonPrefixes      []commonPrefix `xml:"CommonPrefixes"`
+}
+
+// versionEntry is a Version or, without ETag and Size, a DeleteMarker
+type versionEntry struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag,omitempty"`
+	Size         *int   `xml:"Size,omitempty"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+	Owner        owner  `xml:"Owner"`
+}
+
+// displayVersionID returns the version ID reported for obj
+func displayVersionID(obj *object) string {
+	if obj.versionID == "" {
+		return "null"
+	}
+	return obj.versionID
+}
+
+// listVersions implements ListObjectVersions, listing each key's versions
+// newest first
+func (s *Server) listVersions(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	encode, err := listEncoder(r)
+	if err != nil {
+		return err
+	}
+	maxKeys, err := maxEntries(r, "max-keys")
+	if err != nil {
+		return err
+	}
+	q := r.query
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	keyMarker, versionMarker := q.Get("key-marker"), q.Get("version-id-marker")
+
+	result := listVersionsResult{
+		Xmlns:           s3Namespace,
+		Name:            b.name,
+		Prefix:          encode(prefix),
+		KeyMarker:       encode(keyMarker),
+		VersionIDMarker: versionMarker,
+		MaxKeys:         maxKeys,
+		Delimiter:       encode(delimiter),
+		EncodingType:    q.Get("encoding-type"),
+	}
+	page := pager{max: maxKeys}
+	var last string
+keys:
+	for _, key := range sortedKeys(b.objects) {
+		if !strings.HasPrefix(key, prefix) || key < keyMarker || (key == keyMarker && versionMarker == "") {
+			continue
+		}
+		if cp := commonPrefixOf(key, prefix, delimiter); cp != "" {
+			if cp == last || strings.HasPrefix(keyMarker, cp) {
+				continue
+			}
+			if !page.add() {
+				break
+			}
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: encode(cp)})
+			result.NextKeyMarker, result.NextVersionIDMarker, last = encode(cp), "", cp
+			continue
+		}
+
+		// A version ID marker resumes after that version of the key marker
+		skipping := key == keyMarker
+		versions := b.objects[key]
+		for i := len(versions) - 1; i >= 0; i-- {
+			obj := versions[i]
+			if skipping {
+				skipping = displayVersionID(obj) != versionMarker
+				continue
+			}
+			if !page.add() {
+				break keys
+			}
+			entry := versionEntry{
+				Key:          encode(key),
+				VersionID:    displayVersionID(obj),
+				IsLatest:     i == len(versions)-1,
+				LastModified: iso8601(obj.modified),
+				Owner:        s.owner(),
+			}
+			if obj.deleteMarker {
+				result.DeleteMarkers = append(result.DeleteMarkers, entry)
+			} else {
+				size := len(obj.data)
+				entry.ETag, entry.Size, entry.StorageClass = `"`+obj.etag+`"`, &size, storageClass(obj.header)
+				result.Versions = append(result.Versions, entry)
+			}
+			result.NextKeyMarker, result.NextVersionIDMarker, last = encode(key), entry.VersionID, key
+		}
+	}
+	if result.IsTruncated = page.truncated; !result.IsTruncated {
+		result.NextKeyMarker, result.NextVersionIDMarker = "", ""
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
@@ -0,0 +1,532 @@
+// Package memory rustfstest/memory/bucket.go
+package memory
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Scorpio69t/rustfs-go/bucket"
+	apierrors "github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/pkg/acl"
+	"github.com/Scorpio69t/rustfs-go/pkg/cors"
+	"github.com/Scorpio69t/rustfs-go/pkg/objectlock"
+	"github.com/Scorpio69t/rustfs-go/pkg/replication"
+	"github.com/Scorpio69t/rustfs-go/pkg/sse"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const (
+	// emptyNotification and emptyLogging are read back from buckets without
+	// a notification or logging configuration, as S3 returns them
+	emptyNotification = `<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></NotificationConfiguration>`
+	emptyLogging      = `<BucketLoggingStatus xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></BucketLoggingStatus>`
+)
+
+// bucketService serves bucket.Service from a Store
+type bucketService struct {
+	store *Store
+}
+
+var _ bucket.Service = (*bucketService)(nil)
+
+// validateBucket checks a bucket name as the bucket client does
+func validateBucket(bucketName string) error {
+	if len(bucketName) < 3 || len(bucketName) > 63 {
+		return bucket.ErrInvalidBucketName
+	}
+	return nil
+}
+
+// withBucket validates the bucket name and calls fn with the bucket while
+// holding s.mu
+func (s *Store) withBucket(bucketName string, fn func(b *bucketData) error) error {
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	return fn(b)
+}
+
+// Create creates a bucket. Creating a bucket that exists fails unless
+// ForceCreate is set; ObjectLocking also enables versioning.
+func (bs *bucketService) Create(ctx context.Context, bucketName string, opts ...bucket.CreateOption) error {
+	options := applyOptions(bucket.CreateOptions{}, opts)
+	bs.store.record("Bucket.Create", bucketName, "", options)
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if options.Region == "" {
+		options.Region = DefaultRegion
+	}
+
+	s := bs.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucketName]; ok {
+		if options.ForceCreate {
+			return nil
+		}
+		return apiError(apierrors.ErrCodeBucketAlreadyOwnedByYou, http.StatusConflict, "Your previous request to create the named bucket succeeded and you already own it.", bucketName, "")
+	}
+	b := &bucketData{
+		name:       bucketName,
+		region:     options.Region,
+		created:    s.now(),
+		objectLock: options.ObjectLocking,
+		objects:    make(map[string][]*version),
+		uploads:    make(map[string]*upload),
+	}
+	if options.ObjectLocking {
+		b.versioning.Status = versioningEnabled
+		b.lockConfig = &objectlock.Config{ObjectLockEnabled: objectlock.ObjectLockEnabledValue}
+	}
+	s.buckets[bucketName] = b
+	return nil
+}
+
+// Delete removes a bucket. A bucket with objects or versions is only
+// removed with ForceDelete.
+func (bs *bucketService) Delete(ctx context.Context, bucketName string, opts ...bucket.DeleteOption) error {
+	options := applyOptions(bucket.DeleteOptions{}, opts)
+	bs.store.record("Bucket.Delete", bucketName, "", options)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if !b.empty() && !options.ForceDelete {
+			return apiError(apierrors.ErrCodeBucketNotEmpty, http.StatusConflict, "The bucket you tried to delete is not empty", bucketName, "")
+		}
+		delete(bs.store.buckets, bucketName)
+		return nil
+	})
+}
+
+// Exists reports whether a bucket exists
+func (bs *bucketService) Exists(ctx context.Context, bucketName string) (bool, error) {
+	bs.store.record("Bucket.Exists", bucketName, "", nil)
+	err := bs.store.withBucket(bucketName, func(*bucketData) error { return nil })
+	if apierrors.IsBucketNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns the buckets sorted by name
+func (bs *bucketService) List(ctx context.Context) ([]types.BucketInfo, error) {
+	bs.store.record("Bucket.List", "", "", nil)
+
+	s := bs.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buckets := make([]types.BucketInfo, 0, len(s.buckets))
+	for _, b := range s.buckets {
+		buckets = append(buckets, types.BucketInfo{Name: b.name, CreationDate: b.created, Region: b.region})
+	}
+	slices.SortFunc(buckets, func(a, b types.BucketInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return buckets, nil
+}
+
+// GetLocation returns the region of a bucket
+func (bs *bucketService) GetLocation(ctx context.Context, bucketName string) (string, error) {
+	bs.store.record("Bucket.GetLocation", bucketName, "", nil)
+	var region string
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		region = b.region
+		return nil
+	})
+	return region, err
+}
+
+// SetPolicy stores the policy of a bucket
+func (bs *bucketService) SetPolicy(ctx context.Context, bucketName, policy string) error {
+	bs.store.record("Bucket.SetPolicy", bucketName, "", nil, policy)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.policy = policy
+		return nil
+	})
+}
+
+// GetPolicy returns the policy of a bucket
+func (bs *bucketService) GetPolicy(ctx context.Context, bucketName string) (string, error) {
+	bs.store.record("Bucket.GetPolicy", bucketName, "", nil)
+	var policy string
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if b.policy == "" {
+			return apiError("NoSuchBucketPolicy", http.StatusNotFound, "The bucket policy does not exist", bucketName, "")
+		}
+		policy = b.policy
+		return nil
+	})
+	return policy, err
+}
+
+// DeletePolicy removes the policy of a bucket
+func (bs *bucketService) DeletePolicy(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeletePolicy", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.policy = ""
+		return nil
+	})
+}
+
+// SetLifecycle stores the lifecycle configuration of a bucket
+func (bs *bucketService) SetLifecycle(ctx context.Context, bucketName string, config []byte) error {
+	bs.store.record("Bucket.SetLifecycle", bucketName, "", nil, slices.Clone(config))
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.lifecycle = slices.Clone(config)
+		return nil
+	})
+}
+
+// GetLifecycle returns the lifecycle configuration of a bucket
+func (bs *bucketService) GetLifecycle(ctx context.Context, bucketName string) ([]byte, error) {
+	bs.store.record("Bucket.GetLifecycle", bucketName, "", nil)
+	var config []byte
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if len(b.lifecycle) == 0 {
+			return apiError("NoSuchLifecycleConfiguration", http.StatusNotFound, "The lifecycle configuration does not exist", bucketName, "")
+		}
+		config = slices.Clone(b.lifecycle)
+		return nil
+	})
+	return config, err
+}
+
+// DeleteLifecycle removes the lifecycle configuration of a bucket
+func (bs *bucketService) DeleteLifecycle(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteLifecycle", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.lifecycle = nil
+		return nil
+	})
+}
+
+// SetVersioning enables or suspends versioning. Versioning cannot be
+// suspended on a bucket with object lock.
+func (bs *bucketService) SetVersioning(ctx context.Context, bucketName string, cfg types.VersioningConfig) error {
+	bs.store.record("Bucket.SetVersioning", bucketName, "", nil, cfg)
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if cfg.Status != versioningEnabled && cfg.Status != versioningSuspended {
+		return bucket.ErrInvalidVersioningStatus
+	}
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if b.objectLock && cfg.Status == versioningSuspended {
+			return apiError("InvalidBucketState", http.StatusConflict, "An Object Lock configuration is present on this bucket, so the versioning state cannot be changed.", bucketName, "")
+		}
+		b.versioning = cfg
+		return nil
+	})
+}
+
+// GetVersioning returns the versioning configuration of a bucket, with an
+// empty status until versioning is first set
+func (bs *bucketService) GetVersioning(ctx context.Context, bucketName string) (types.VersioningConfig, error) {
+	bs.store.record("Bucket.GetVersioning", bucketName, "", nil)
+	var cfg types.VersioningConfig
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		cfg = b.versioning
+		return nil
+	})
+	return cfg, err
+}
+
+// SetReplication stores the replication configuration of a bucket
+func (bs *bucketService) SetReplication(ctx context.Context, bucketName string, config []byte) error {
+	bs.store.record("Bucket.SetReplication", bucketName, "", nil, slices.Clone(config))
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if len(config) == 0 {
+		return bucket.ErrEmptyBucketConfig
+	}
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if b.versioning.Status != versioningEnabled {
+			return apiError(apierrors.ErrCodeInvalidRequest, http.StatusBadRequest, "Versioning must be 'Enabled' on the bucket to apply a replication configuration", bucketName, "")
+		}
+		b.replication = slices.Clone(config)
+		return nil
+	})
+}
+
+// GetReplication returns the replication configuration of a bucket
+func (bs *bucketService) GetReplication(ctx context.Context, bucketName string) ([]byte, error) {
+	bs.store.record("Bucket.GetReplication", bucketName, "", nil)
+	var config []byte
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if len(b.replication) == 0 {
+			return apiError("ReplicationConfigurationNotFoundError", http.StatusNotFound, "The replication configuration was not found", bucketName, "")
+		}
+		config = slices.Clone(b.replication)
+		return nil
+	})
+	return config, err
+}
+
+// DeleteReplication removes the replication configuration of a bucket
+func (bs *bucketService) DeleteReplication(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteReplication", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.replication = nil
+		return nil
+	})
+}
+
+// GetReplicationMetrics returns empty metrics: the store replicates nothing
+func (bs *bucketService) GetReplicationMetrics(ctx context.Context, bucketName string) (replication.Metrics, error) {
+	bs.store.record("Bucket.GetReplicationMetrics", bucketName, "", nil)
+	err := bs.store.withBucket(bucketName, func(*bucketData) error { return nil })
+	return replication.Metrics{}, err
+}
+
+// SetNotification stores the notification configuration of a bucket. It is
+// not interpreted; ListenNotification receives events regardless.
+func (bs *bucketService) SetNotification(ctx context.Context, bucketName string, config []byte) error {
+	bs.store.record("Bucket.SetNotification", bucketName, "", nil, slices.Clone(config))
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if len(config) == 0 {
+		return bucket.ErrEmptyBucketConfig
+	}
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.notification = slices.Clone(config)
+		return nil
+	})
+}
+
+// GetNotification returns the notification configuration of a bucket, or
+// an empty configuration
+func (bs *bucketService) GetNotification(ctx context.Context, bucketName string) ([]byte, error) {
+	bs.store.record("Bucket.GetNotification", bucketName, "", nil)
+	var config []byte
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		config = []byte(emptyNotification)
+		if len(b.notification) > 0 {
+			config = slices.Clone(b.notification)
+		}
+		return nil
+	})
+	return config, err
+}
+
+// DeleteNotification removes the notification configuration of a bucket
+func (bs *bucketService) DeleteNotification(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteNotification", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.notification = nil
+		return nil
+	})
+}
+
+// SetLogging stores the logging configuration of a bucket
+func (bs *bucketService) SetLogging(ctx context.Context, bucketName string, config []byte) error {
+	bs.store.record("Bucket.SetLogging", bucketName, "", nil, slices.Clone(config))
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if len(config) == 0 {
+		return bucket.ErrEmptyBucketConfig
+	}
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.logging = slices.Clone(config)
+		return nil
+	})
+}
+
+// GetLogging returns the logging configuration of a bucket, or an empty
+// configuration
+func (bs *bucketService) GetLogging(ctx context.Context, bucketName string) ([]byte, error) {
+	bs.store.record("Bucket.GetLogging", bucketName, "", nil)
+	var config []byte
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		config = []byte(emptyLogging)
+		if len(b.logging) > 0 {
+			config = slices.Clone(b.logging)
+		}
+		return nil
+	})
+	return config, err
+}
+
+// DeleteLogging removes the logging configuration of a bucket
+func (bs *bucketService) DeleteLogging(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteLogging", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.logging = nil
+		return nil
+	})
+}
+
+// SetEncryption stores the default encryption of a bucket
+func (bs *bucketService) SetEncryption(ctx context.Context, bucketName string, config sse.Configuration) error {
+	bs.store.record("Bucket.SetEncryption", bucketName, "", nil, config)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.encryption = &config
+		return nil
+	})
+}
+
+// GetEncryption returns the default encryption of a bucket, or
+// sse.ErrNoEncryptionConfig
+func (bs *bucketService) GetEncryption(ctx context.Context, bucketName string) (sse.Configuration, error) {
+	bs.store.record("Bucket.GetEncryption", bucketName, "", nil)
+	var config sse.Configuration
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if b.encryption == nil {
+			return sse.ErrNoEncryptionConfig
+		}
+		config = *b.encryption
+		return nil
+	})
+	return config, err
+}
+
+// DeleteEncryption removes the default encryption of a bucket
+func (bs *bucketService) DeleteEncryption(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteEncryption", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.encryption = nil
+		return nil
+	})
+}
+
+// SetCORS stores the CORS configuration of a bucket
+func (bs *bucketService) SetCORS(ctx context.Context, bucketName string, config cors.Config) error {
+	bs.store.record("Bucket.SetCORS", bucketName, "", nil, config)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.cors = &config
+		return nil
+	})
+}
+
+// GetCORS returns the CORS configuration of a bucket, or cors.ErrNoCORSConfig
+func (bs *bucketService) GetCORS(ctx context.Context, bucketName string) (cors.Config, error) {
+	bs.store.record("Bucket.GetCORS", bucketName, "", nil)
+	var config cors.Config
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if b.cors == nil {
+			return cors.ErrNoCORSConfig
+		}
+		config = *b.cors
+		return nil
+	})
+	return config, err
+}
+
+// DeleteCORS removes the CORS configuration of a bucket
+func (bs *bucketService) DeleteCORS(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteCORS", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.cors = nil
+		return nil
+	})
+}
+
+// SetTagging replaces the tags of a bucket
+func (bs *bucketService) SetTagging(ctx context.Context, bucketName string, tags map[string]string) error {
+	bs.store.record("Bucket.SetTagging", bucketName, "", nil, cloneMap(tags))
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.tags = cloneMap(tags)
+		return nil
+	})
+}
+
+// GetTagging returns the tags of a bucket
+func (bs *bucketService) GetTagging(ctx context.Context, bucketName string) (map[string]string, error) {
+	bs.store.record("Bucket.GetTagging", bucketName, "", nil)
+	var tags map[string]string
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if len(b.tags) == 0 {
+			return apiError("NoSuchTagSet", http.StatusNotFound, "The TagSet does not exist", bucketName, "")
+		}
+		tags = cloneMap(b.tags)
+		return nil
+	})
+	return tags, err
+}
+
+// DeleteTagging removes the tags of a bucket
+func (bs *bucketService) DeleteTagging(ctx context.Context, bucketName string) error {
+	bs.store.record("Bucket.DeleteTagging", bucketName, "", nil)
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.tags = nil
+		return nil
+	})
+}
+
+// SetACL replaces the ACL of a bucket
+func (bs *bucketService) SetACL(ctx context.Context, bucketName string, policy acl.ACL) error {
+	bs.store.record("Bucket.SetACL", bucketName, "", nil, policy)
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if err := policy.Normalize(); err != nil {
+		return err
+	}
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		b.acl = &policy
+		return nil
+	})
+}
+
+// GetACL returns the ACL of a bucket; buckets are private to the store
+// owner until SetACL
+func (bs *bucketService) GetACL(ctx context.Context, bucketName string) (acl.ACL, error) {
+	bs.store.record("Bucket.GetACL", bucketName, "", nil)
+	var policy acl.ACL
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		policy = expandACL(b.acl)
+		return nil
+	})
+	return policy, err
+}
+
+// SetObjectLockConfig sets the default retention of a bucket created with
+// object lock. New object versions get the default retention.
+func (bs *bucketService) SetObjectLockConfig(ctx context.Context, bucketName string, config objectlock.Config) error {
+	bs.store.record("Bucket.SetObjectLockConfig", bucketName, "", nil, config)
+	if err := validateBucket(bucketName); err != nil {
+		return err
+	}
+	if err := config.Normalize(); err != nil {
+		return err
+	}
+	return bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if !b.objectLock {
+			return apiError("InvalidBucketState", http.StatusConflict, "Object Lock configuration cannot be enabled on existing buckets", bucketName, "")
+		}
+		if config.Rule != nil {
+			rule := *config.Rule
+			config.Rule = &rule
+		}
+		b.lockConfig = &config
+		return nil
+	})
+}
+
+// GetObjectLockConfig returns the object lock configuration of a bucket,
+// or objectlock.ErrNoObjectLockConfig for a bucket without object lock
+func (bs *bucketService) GetObjectLockConfig(ctx context.Context, bucketName string) (objectlock.Config, error) {
+	bs.store.record("Bucket.GetObjectLockConfig", bucketName, "", nil)
+	var config objectlock.Config
+	err := bs.store.withBucket(bucketName, func(b *bucketData) error {
+		if b.lockConfig == nil {
+			return objectlock.ErrNoObjectLockConfig
+		}
+		config = *b.lockConfig
+		if config.Rule != nil {
+			rule := *config.Rule
+			config.Rule = &rule
+		}
+		return nil
+	})
+	return config, err
+}
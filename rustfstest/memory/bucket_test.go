@@ -0,0 +1,155 @@
+// Package memory rustfstest/memory/bucket_test.go
+package memory
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/bucket"
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/cors"
+	"github.com/Scorpio69t/rustfs-go/pkg/notification"
+	"github.com/Scorpio69t/rustfs-go/pkg/objectlock"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+func TestBuckets(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	svc := store.Bucket()
+
+	if err := svc.Create(ctx, "test-bucket"); !errors.IsBucketExists(err) {
+		t.Errorf("Create() twice error = %v, want BucketAlreadyOwnedByYou", err)
+	}
+	if err := svc.Create(ctx, "other-bucket", bucket.WithRegion("eu-west-1")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	buckets, err := svc.List(ctx)
+	if err != nil || len(buckets) != 2 || buckets[0].Name != "other-bucket" || buckets[0].Region != "eu-west-1" {
+		t.Errorf("List() = %+v, %v", buckets, err)
+	}
+	if exists, err := svc.Exists(ctx, "missing"); exists || err != nil {
+		t.Errorf("Exists(missing) = %v, %v", exists, err)
+	}
+
+	put(t, store, "key", "data")
+	if err := svc.Delete(ctx, "test-bucket"); !errors.IsBucketNotEmpty(err) {
+		t.Errorf("Delete() non-empty error = %v, want BucketNotEmpty", err)
+	}
+	if err := svc.Delete(ctx, "test-bucket", bucket.WithForceDelete(true)); err != nil {
+		t.Errorf("Delete(force) error = %v", err)
+	}
+	if exists, err := svc.Exists(ctx, "test-bucket"); exists || err != nil {
+		t.Errorf("Exists() after delete = %v, %v", exists, err)
+	}
+	if err := svc.Create(ctx, "ab"); !stderrors.Is(err, bucket.ErrInvalidBucketName) {
+		t.Errorf("Create(ab) error = %v, want ErrInvalidBucketName", err)
+	}
+}
+
+func TestBucketConfig(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	svc := store.Bucket()
+
+	if _, err := svc.GetCORS(ctx, "test-bucket"); !stderrors.Is(err, cors.ErrNoCORSConfig) {
+		t.Errorf("GetCORS() error = %v, want ErrNoCORSConfig", err)
+	}
+	if _, err := svc.GetPolicy(ctx, "test-bucket"); errCode(err) != "NoSuchBucketPolicy" {
+		t.Errorf("GetPolicy() error = %v, want NoSuchBucketPolicy", err)
+	}
+	if err := svc.SetPolicy(ctx, "test-bucket", `{"Version":"2012-10-17"}`); err != nil {
+		t.Fatalf("SetPolicy() error = %v", err)
+	}
+	if policy, err := svc.GetPolicy(ctx, "test-bucket"); err != nil || policy != `{"Version":"2012-10-17"}` {
+		t.Errorf("GetPolicy() = %q, %v", policy, err)
+	}
+	if err := svc.SetLogging(ctx, "test-bucket", nil); !stderrors.Is(err, bucket.ErrEmptyBucketConfig) {
+		t.Errorf("SetLogging(nil) error = %v, want ErrEmptyBucketConfig", err)
+	}
+	if err := svc.SetVersioning(ctx, "test-bucket", types.VersioningConfig{Status: "On"}); !stderrors.Is(err, bucket.ErrInvalidVersioningStatus) {
+		t.Errorf("SetVersioning(On) error = %v, want ErrInvalidVersioningStatus", err)
+	}
+	if err := svc.SetTagging(ctx, "test-bucket", map[string]string{"env": "test"}); err != nil {
+		t.Fatalf("SetTagging() error = %v", err)
+	}
+	if tags, err := svc.GetTagging(ctx, "test-bucket"); err != nil || tags["env"] != "test" {
+		t.Errorf("GetTagging() = %v, %v", tags, err)
+	}
+}
+
+func TestObjectLockConfig(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	svc := store.Bucket()
+
+	if _, err := svc.GetObjectLockConfig(ctx, "test-bucket"); !stderrors.Is(err, objectlock.ErrNoObjectLockConfig) {
+		t.Errorf("GetObjectLockConfig() error = %v, want ErrNoObjectLockConfig", err)
+	}
+
+	if err := svc.Create(ctx, "locked-bucket", bucket.WithObjectLocking(true)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	config := objectlock.Config{Rule: &objectlock.Rule{DefaultRetention: objectlock.DefaultRetention{
+		Mode: objectlock.RetentionCompliance,
+		Days: 1,
+	}}}
+	if err := svc.SetObjectLockConfig(ctx, "locked-bucket", config); err != nil {
+		t.Fatalf("SetObjectLockConfig() error = %v", err)
+	}
+	if err := svc.SetVersioning(ctx, "locked-bucket", types.VersioningConfig{Status: "Suspended"}); errCode(err) != "InvalidBucketState" {
+		t.Errorf("SetVersioning(Suspended) error = %v, want InvalidBucketState", err)
+	}
+
+	info, err := store.Object().Put(ctx, "locked-bucket", "key", strings.NewReader("data"), 4)
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	mode, until, err := store.Object().GetRetention(ctx, "locked-bucket", "key")
+	if err != nil || mode != objectlock.RetentionCompliance || until.Before(time.Now()) {
+		t.Errorf("GetRetention() = %v, %v, %v", mode, until, err)
+	}
+	err = store.Object().Delete(ctx, "locked-bucket", "key", func(o *object.DeleteOptions) { o.VersionID = info.VersionID })
+	if !errors.IsAccessDenied(err) {
+		t.Errorf("Delete() under default retention error = %v, want AccessDenied", err)
+	}
+}
+
+func TestListenNotification(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := newStore(t)
+
+	events := store.Bucket().ListenNotification(ctx, "test-bucket", "logs/", ".txt", []notification.EventType{notification.ObjectCreatedAll})
+	put(t, store, "other/a.txt", "x")
+	put(t, store, "logs/a.json", "x")
+	put(t, store, "logs/a.txt", "x")
+	if err := store.Object().Delete(ctx, "test-bucket", "logs/a.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	put(t, store, "logs/b.txt", "x")
+
+	for _, want := range []string{"logs/a.txt", "logs/b.txt"} {
+		select {
+		case info := <-events:
+			if info.Err != nil || len(info.Records) != 1 {
+				t.Fatalf("event = %+v", info)
+			}
+			record := info.Records[0]
+			if record.S3.Object.Key != want || record.EventName != string(notification.ObjectCreatedPut) {
+				t.Errorf("event = %s %s, want %s Put", record.EventName, record.S3.Object.Key, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no event for %s", want)
+		}
+	}
+
+	cancel()
+	for info := range events {
+		t.Errorf("event after cancel = %+v", info)
+	}
+}
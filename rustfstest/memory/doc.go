@@ -0,0 +1,15 @@
+// Package memory provides in-memory implementations of object.Service and
+// bucket.Service for unit tests that take the service interfaces and want no
+// HTTP at all.
+//
+// A Store keeps buckets, object versions, multipart uploads and bucket
+// configuration in memory. Its services follow the semantics of the client
+// against RustFS: List rolls keys up into common prefixes, versioned buckets
+// assign version IDs and delete markers, Get and Stat honour ranges and
+// conditional headers, retention and legal holds block deletes, and failures
+// are the same typed errors, so errors.IsObjectNotFound and friends work.
+// Every call is recorded for tests to assert on with Store.Calls.
+//
+// For tests that need the full client, including signing and transport
+// behaviour, use rustfstest.NewClient instead.
+package memory
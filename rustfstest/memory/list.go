@@ -0,0 +1,262 @@
+// Package memory rustfstest/memory/list.go
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// pager counts the entries of a listing page
+type pager struct {
+	max       int
+	count     int
+	truncated bool
+}
+
+// newPager returns a pager of max entries, or 1000 when max is not positive
+func newPager(max int) *pager {
+	if max <= 0 {
+		max = 1000
+	}
+	return &pager{max: max}
+}
+
+// add reports whether the page has room for another entry; once it has not,
+// the page is truncated
+func (p *pager) add() bool {
+	if p.count == p.max {
+		p.truncated = true
+		return false
+	}
+	p.count++
+	return true
+}
+
+// commonPrefix returns the common prefix that rolls up key under delimiter
+func commonPrefix(key, prefix, delimiter string) (string, bool) {
+	if delimiter == "" {
+		return "", false
+	}
+	i := strings.Index(key[len(prefix):], delimiter)
+	if i < 0 {
+		return "", false
+	}
+	return key[:len(prefix)+i+len(delimiter)], true
+}
+
+// listPage is a page of a listing: entries as the client sends them, with
+// contents or versions before common prefixes or delete markers
+type listPage [][]types.ObjectInfo
+
+// List lists objects in pages of MaxKeys entries. Without Recursive, keys
+// are rolled up into common prefixes at "/".
+func (o *objectService) List(ctx context.Context, bucketName string, opts ...object.ListOption) <-chan types.ObjectInfo {
+	options := applyOptions(object.ListOptions{MaxKeys: 1000}, opts)
+	o.store.record("Object.List", bucketName, "", options)
+	if options.WithVersions {
+		return o.listVersions(ctx, bucketName, options)
+	}
+	return o.stream(ctx, options, func() ([]listPage, error) {
+		return o.store.listObjects(bucketName, options)
+	})
+}
+
+// ListVersions lists versions and delete markers, newest first per key
+func (o *objectService) ListVersions(ctx context.Context, bucketName string, opts ...object.ListOption) <-chan types.ObjectInfo {
+	options := applyOptions(object.ListOptions{MaxKeys: 1000}, opts)
+	options.WithVersions = true
+	o.store.record("Object.ListVersions", bucketName, "", options)
+	return o.listVersions(ctx, bucketName, options)
+}
+
+// listVersions streams a version listing
+func (o *objectService) listVersions(ctx context.Context, bucketName string, options object.ListOptions) <-chan types.ObjectInfo {
+	return o.stream(ctx, options, func() ([]listPage, error) {
+		return o.store.listVersions(bucketName, options)
+	})
+}
+
+// stream sends the pages of a listing, stopping with an error entry when
+// ctx is done or options.StopChan is closed between entries
+func (o *objectService) stream(ctx context.Context, options object.ListOptions, list func() ([]listPage, error)) <-chan types.ObjectInfo {
+	objectCh := make(chan types.ObjectInfo)
+	go func() {
+		defer close(objectCh)
+
+		stopped := func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			select {
+			case <-options.StopChan:
+				return object.ErrListStopped
+			default:
+				return nil
+			}
+		}
+		send := func(info types.ObjectInfo) bool {
+			if info.Err != nil {
+				select {
+				case objectCh <- info:
+				case <-ctx.Done():
+				}
+				return false
+			}
+			select {
+			case objectCh <- info:
+				return true
+			case <-ctx.Done():
+				objectCh <- types.ObjectInfo{Err: ctx.Err()}
+			case <-options.StopChan:
+				objectCh <- types.ObjectInfo{Err: object.ErrListStopped}
+			}
+			return false
+		}
+
+		pages, err := list()
+		if err != nil {
+			send(types.ObjectInfo{Err: err})
+			return
+		}
+		for _, page := range pages {
+			if err := stopped(); err != nil {
+				send(types.ObjectInfo{Err: err})
+				return
+			}
+			for _, entries := range page {
+				for _, info := range entries {
+					if !send(info) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return objectCh
+}
+
+// listObjects returns the pages of an object listing
+func (s *Store) listObjects(bucketName string, options object.ListOptions) ([]listPage, error) {
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
+	}
+	delimiter := "/"
+	if options.Recursive {
+		delimiter = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pages      []listPage
+		page       = listPage{nil, nil}
+		pager      = newPager(options.MaxKeys)
+		lastPrefix string
+	)
+	for _, key := range b.keys(options.Prefix, options.StartAfter) {
+		v := b.latest(key)
+		if v.deleteMarker {
+			continue
+		}
+		prefix, rolledUp := commonPrefix(key, options.Prefix, delimiter)
+		if rolledUp && prefix == lastPrefix {
+			continue
+		}
+		if !pager.add() {
+			pages = append(pages, page)
+			page, pager = listPage{nil, nil}, newPager(options.MaxKeys)
+			pager.add()
+		}
+		if rolledUp {
+			lastPrefix = prefix
+			page[1] = append(page[1], types.ObjectInfo{Key: prefix, IsPrefix: true})
+			continue
+		}
+		page[0] = append(page[0], types.ObjectInfo{
+			Key:          key,
+			Size:         int64(len(v.data)),
+			ETag:         v.etag,
+			LastModified: v.modified,
+			Owner:        types.Owner{ID: OwnerID, DisplayName: OwnerID},
+			StorageClass: v.storageClass,
+		})
+	}
+	return append(pages, page), nil
+}
+
+// listVersions returns the pages of a version listing. The client does not
+// send common prefixes of version listings, so keys that roll up into one
+// are left out.
+func (s *Store) listVersions(bucketName string, options object.ListOptions) ([]listPage, error) {
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
+	}
+	delimiter := "/"
+	if options.Recursive {
+		delimiter = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pages []listPage
+		page  = listPage{nil, nil}
+		pager = newPager(options.MaxKeys)
+	)
+	for _, key := range b.keys(options.Prefix, options.StartAfter) {
+		if _, rolledUp := commonPrefix(key, options.Prefix, delimiter); rolledUp {
+			continue
+		}
+		versions := b.objects[key]
+		for i, v := range slices.Backward(versions) {
+			if !pager.add() {
+				pages = append(pages, page)
+				page, pager = listPage{nil, nil}, newPager(options.MaxKeys)
+				pager.add()
+			}
+			info := types.ObjectInfo{
+				Key:            key,
+				LastModified:   v.modified,
+				Owner:          types.Owner{ID: OwnerID, DisplayName: OwnerID},
+				VersionID:      b.versionLabel(v),
+				IsLatest:       i == len(versions)-1,
+				IsDeleteMarker: v.deleteMarker,
+			}
+			if v.deleteMarker {
+				page[1] = append(page[1], info)
+				continue
+			}
+			info.Size = int64(len(v.data))
+			info.ETag = v.etag
+			info.StorageClass = v.storageClass
+			page[0] = append(page[0], info)
+		}
+	}
+	return append(pages, page), nil
+}
+
+// keys returns the sorted keys with prefix that sort after startAfter
+func (b *bucketData) keys(prefix, startAfter string) []string {
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) && key > startAfter {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
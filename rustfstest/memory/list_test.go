@@ -0,0 +1,144 @@
+// Package memory rustfstest/memory/list_test.go
+package memory
+
+import (
+	"context"
+	stderrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/Scorpio69t/rustfs-go/object"
+)
+
+// listKeys collects the keys of a listing, marking prefixes with a trailing
+// "*"
+func listKeys(t *testing.T, store *Store, opts ...object.ListOption) []string {
+	t.Helper()
+	var keys []string
+	for info := range store.Object().List(context.Background(), "test-bucket", opts...) {
+		if info.Err != nil {
+			t.Fatalf("List() error = %v", info.Err)
+		}
+		if info.IsPrefix {
+			keys = append(keys, info.Key+"*")
+			continue
+		}
+		keys = append(keys, info.Key)
+	}
+	return keys
+}
+
+func TestListDelimiter(t *testing.T) {
+	store := newStore(t)
+	for _, key := range []string{"a.txt", "dir/b.txt", "dir/sub/c.txt", "dir2/d.txt", "z.txt"} {
+		put(t, store, key, "x")
+	}
+
+	tests := []struct {
+		name string
+		opts []object.ListOption
+		want []string
+	}{
+		{"top level", nil, []string{"a.txt", "z.txt", "dir/*", "dir2/*"}},
+		{"prefix", []object.ListOption{object.WithListPrefix("dir/")}, []string{"dir/b.txt", "dir/sub/*"}},
+		{"recursive", []object.ListOption{object.WithListRecursive(true)}, []string{"a.txt", "dir/b.txt", "dir/sub/c.txt", "dir2/d.txt", "z.txt"}},
+		{"start after", []object.ListOption{object.WithListRecursive(true), func(o *object.ListOptions) { o.StartAfter = "dir/sub/c.txt" }}, []string{"dir2/d.txt", "z.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listKeys(t, store, tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListPages(t *testing.T) {
+	store := newStore(t)
+	for _, key := range []string{"a", "b/1", "c", "d/1", "e"} {
+		put(t, store, key, "x")
+	}
+
+	// Each page sends its objects before its common prefixes
+	got := listKeys(t, store, object.WithListMaxKeys(2))
+	want := []string{"a", "b/*", "c", "d/*", "e"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List(max 2) = %v, want %v", got, want)
+	}
+	got = listKeys(t, store, object.WithListMaxKeys(4))
+	want = []string{"a", "c", "b/*", "d/*", "e"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List(max 4) = %v, want %v", got, want)
+	}
+}
+
+func TestListStop(t *testing.T) {
+	store := newStore(t)
+	for _, key := range []string{"a", "b", "c"} {
+		put(t, store, key, "x")
+	}
+
+	stop := make(chan struct{})
+	ch := store.Object().List(context.Background(), "test-bucket", object.WithListStopChan(stop))
+	if info := <-ch; info.Err != nil || info.Key != "a" {
+		t.Fatalf("first entry = %+v", info)
+	}
+	close(stop)
+	var last error
+	for info := range ch {
+		last = info.Err
+	}
+	if !stderrors.Is(last, object.ErrListStopped) {
+		t.Errorf("last entry error = %v, want ErrListStopped", last)
+	}
+}
+
+func TestListMissingBucket(t *testing.T) {
+	store := New(Config{})
+	var errs int
+	for info := range store.Object().List(context.Background(), "missing") {
+		if errCode(info.Err) != "NoSuchBucket" {
+			t.Errorf("List() entry = %+v, want NoSuchBucket", info)
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("List() sent %d entries, want 1", errs)
+	}
+}
+
+func TestListMultipartUploads(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	svc := store.Object()
+	for _, key := range []string{"a", "dir/b", "dir/c"} {
+		if _, err := svc.InitiateMultipartUpload(ctx, "test-bucket", key); err != nil {
+			t.Fatalf("InitiateMultipartUpload(%q) error = %v", key, err)
+		}
+	}
+
+	result, err := svc.ListMultipartUploads(ctx, "test-bucket", object.WithMultipartDelimiter("/"))
+	if err != nil {
+		t.Fatalf("ListMultipartUploads() error = %v", err)
+	}
+	if len(result.Uploads) != 1 || result.Uploads[0].Key != "a" || len(result.CommonPrefixes) != 1 || result.CommonPrefixes[0].Prefix != "dir/" {
+		t.Errorf("ListMultipartUploads() = %+v", result)
+	}
+
+	result, err = svc.ListMultipartUploads(ctx, "test-bucket", object.WithMultipartMaxUploads(2))
+	if err != nil {
+		t.Fatalf("ListMultipartUploads() error = %v", err)
+	}
+	if len(result.Uploads) != 2 || !result.IsTruncated || result.NextKeyMarker != "dir/b" {
+		t.Fatalf("ListMultipartUploads(max 2) = %+v", result)
+	}
+	result, err = svc.ListMultipartUploads(ctx, "test-bucket",
+		object.WithMultipartKeyMarker(result.NextKeyMarker),
+		object.WithMultipartUploadIDMarker(result.NextUploadIDMarker))
+	if err != nil {
+		t.Fatalf("ListMultipartUploads() error = %v", err)
+	}
+	if len(result.Uploads) != 1 || result.Uploads[0].Key != "dir/c" || result.IsTruncated {
+		t.Errorf("ListMultipartUploads(next page) = %+v", result)
+	}
+}
@@ -0,0 +1,138 @@
+// Package memory rustfstest/memory/lock.go
+package memory
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	apierrors "github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/objectlock"
+)
+
+// lockedVersion returns the version a lock operation targets, in a bucket
+// with object lock enabled; the caller holds s.mu
+func (s *Store) lockedVersion(bucketName, objectName, versionID string) (*version, error) {
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if !b.objectLock {
+		return nil, errLockNotEnabled(bucketName, objectName)
+	}
+	_, v, err := s.find(bucketName, objectName, versionID)
+	return v, err
+}
+
+// SetLegalHold turns the legal hold of an object version on or off
+func (o *objectService) SetLegalHold(ctx context.Context, bucketName, objectName string, hold objectlock.LegalHoldStatus, opts ...object.LegalHoldOption) error {
+	options := applyOptions(object.LegalHoldOptions{}, opts)
+	o.store.record("Object.SetLegalHold", bucketName, objectName, options, hold)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if !hold.IsValid() {
+		return objectlock.ErrInvalidLegalHoldStatus
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.lockedVersion(bucketName, objectName, options.VersionID)
+	if err != nil {
+		return err
+	}
+	v.legalHold = hold
+	return nil
+}
+
+// GetLegalHold returns the legal hold status of an object version
+func (o *objectService) GetLegalHold(ctx context.Context, bucketName, objectName string, opts ...object.LegalHoldOption) (objectlock.LegalHoldStatus, error) {
+	options := applyOptions(object.LegalHoldOptions{}, opts)
+	o.store.record("Object.GetLegalHold", bucketName, objectName, options)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return "", err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.lockedVersion(bucketName, objectName, options.VersionID)
+	if err != nil {
+		return "", err
+	}
+	if v.legalHold == "" {
+		return "", errNoLockConfiguration(bucketName, objectName)
+	}
+	return v.legalHold, nil
+}
+
+// SetRetention sets the retention of an object version. Compliance
+// retention can only be extended; shortening governance retention or
+// changing its mode needs WithGovernanceBypass.
+func (o *objectService) SetRetention(ctx context.Context, bucketName, objectName string, mode objectlock.RetentionMode, retainUntil time.Time, opts ...object.RetentionOption) error {
+	options := applyOptions(object.RetentionOptions{}, opts)
+	o.store.record("Object.SetRetention", bucketName, objectName, options, mode, retainUntil)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if !mode.IsValid() {
+		return objectlock.ErrInvalidRetentionMode
+	}
+	if retainUntil.IsZero() {
+		return objectlock.ErrInvalidRetentionDate
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.lockedVersion(bucketName, objectName, options.VersionID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	if !retainUntil.After(now) {
+		return apiError(apierrors.ErrCodeInvalidArgument, http.StatusBadRequest, "The retain until date must be in the future", bucketName, objectName)
+	}
+	if v.retention != "" && now.Before(v.retainUntil) {
+		weakened := mode != v.retention || retainUntil.Before(v.retainUntil)
+		if weakened && (v.retention == objectlock.RetentionCompliance || !options.GovernanceBypass) {
+			return apiError(apierrors.ErrCodeAccessDenied, http.StatusForbidden, "Access Denied because object protected by object lock", bucketName, objectName)
+		}
+	}
+	v.retention, v.retainUntil = mode, retainUntil.UTC()
+	return nil
+}
+
+// GetRetention returns the retention of an object version
+func (o *objectService) GetRetention(ctx context.Context, bucketName, objectName string, opts ...object.RetentionOption) (objectlock.RetentionMode, time.Time, error) {
+	options := applyOptions(object.RetentionOptions{}, opts)
+	o.store.record("Object.GetRetention", bucketName, objectName, options)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return "", time.Time{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.lockedVersion(bucketName, objectName, options.VersionID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if v.retention == "" {
+		return "", time.Time{}, errNoLockConfiguration(bucketName, objectName)
+	}
+	return v.retention, v.retainUntil, nil
+}
+
+// errNoLockConfiguration reports an object without a legal hold or
+// retention
+func errNoLockConfiguration(bucketName, objectName string) error {
+	return apiError("NoSuchObjectLockConfiguration", http.StatusNotFound, "The specified object does not have a ObjectLock configuration", bucketName, objectName)
+}
+
+// errLockNotEnabled reports a lock operation in a bucket without object lock
+func errLockNotEnabled(bucketName, objectName string) error {
+	return apiError(apierrors.ErrCodeInvalidRequest, http.StatusBadRequest, "Bucket is missing Object Lock Configuration", bucketName, objectName)
+}
@@ -0,0 +1,341 @@
+// Package memory rustfstest/memory/memory.go
+package memory
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/bucket"
+	apierrors "github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/acl"
+	"github.com/Scorpio69t/rustfs-go/pkg/cors"
+	"github.com/Scorpio69t/rustfs-go/pkg/objectlock"
+	"github.com/Scorpio69t/rustfs-go/pkg/sse"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const (
+	// DefaultRegion is the region of buckets created without one
+	DefaultRegion = "us-east-1"
+	// OwnerID is the ID of the owner of every bucket and object
+	OwnerID = "memory"
+
+	// minPartSize is the smallest multipart part but the last
+	minPartSize = 5 << 20
+
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+)
+
+// Config configures a Store. Zero values select the defaults.
+type Config struct {
+	// Now returns the store clock, e.g. to let retention periods expire
+	// Default: time.Now
+	Now func() time.Time
+}
+
+// Call is a method call made on the services of a Store
+type Call struct {
+	// Method is the service and method name, e.g. Object.Put or Bucket.Create
+	Method string
+	// Bucket and Object name the target of the call, when it has one
+	Bucket string
+	Object string
+	// Options are the applied options, e.g. object.PutOptions, or nil for
+	// methods without options
+	Options any
+	// Args are the remaining arguments in order, without the context,
+	// names, readers and options, e.g. the size of a Put
+	Args []any
+}
+
+// Store keeps buckets, object versions and multipart uploads in memory and
+// serves them through object.Service and bucket.Service implementations.
+// It is safe for concurrent use.
+//
+// Example:
+//
+//	store := memory.New(memory.Config{})
+//	err := store.Bucket().Create(ctx, "bucket")
+//	_, err = store.Object().Put(ctx, "bucket", "key", strings.NewReader("data"), 4)
+//	calls := store.Calls("Object.Put")
+type Store struct {
+	config Config
+	ids    atomic.Int64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucketData
+	calls     []Call
+	listeners []*listener
+}
+
+// New returns an empty Store
+func New(config Config) *Store {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Store{
+		config:  config,
+		buckets: make(map[string]*bucketData),
+	}
+}
+
+// Object returns the object service of the store
+func (s *Store) Object() object.Service {
+	return &objectService{store: s}
+}
+
+// Bucket returns the bucket service of the store
+func (s *Store) Bucket() bucket.Service {
+	return &bucketService{store: s}
+}
+
+// Calls returns the calls made so far, in order, limited to the given
+// methods when there are any
+func (s *Store) Calls(methods ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, 0, len(s.calls))
+	for _, call := range s.calls {
+		if len(methods) == 0 || slices.Contains(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets the calls made so far
+func (s *Store) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+// record appends a call to the log
+func (s *Store) record(method, bucketName, objectName string, options any, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{
+		Method:  method,
+		Bucket:  bucketName,
+		Object:  objectName,
+		Options: options,
+		Args:    args,
+	})
+}
+
+// now returns the store clock at the one-second precision of HTTP dates
+func (s *Store) now() time.Time {
+	return s.config.Now().UTC().Truncate(time.Second)
+}
+
+// newID returns a unique version or upload ID
+func (s *Store) newID() string {
+	sum := md5.Sum([]byte(strconv.FormatInt(s.ids.Add(1), 10)))
+	return hex.EncodeToString(sum[:])
+}
+
+// bucket returns a bucket; the caller holds s.mu
+func (s *Store) bucket(name string) (*bucketData, error) {
+	b, ok := s.buckets[name]
+	if !ok {
+		return nil, apiError(apierrors.ErrCodeNoSuchBucket, http.StatusNotFound, "The specified bucket does not exist", name, "")
+	}
+	return b, nil
+}
+
+// bucketData is a bucket with its objects and configuration
+type bucketData struct {
+	name       string
+	region     string
+	created    time.Time
+	objectLock bool
+	versioning types.VersioningConfig
+
+	// objects holds the versions of every key, oldest first
+	objects map[string][]*version
+	uploads map[string]*upload
+
+	policy       string
+	lifecycle    []byte
+	replication  []byte
+	notification []byte
+	logging      []byte
+	encryption   *sse.Configuration
+	cors         *cors.Config
+	tags         map[string]string
+	acl          *acl.ACL
+	lockConfig   *objectlock.Config
+}
+
+// version is a version of an object or a delete marker
+type version struct {
+	key          string
+	id           string // empty for the null version
+	deleteMarker bool
+	modified     time.Time
+
+	data         []byte
+	etag         string
+	contentType  string
+	storageClass string
+	userMetadata map[string]string
+	tags         map[string]string
+	acl          *acl.ACL
+
+	retention   objectlock.RetentionMode
+	retainUntil time.Time
+	legalHold   objectlock.LegalHoldStatus
+}
+
+// versionLabel returns the version ID reported for v: the null version is
+// "null" once versioning has been configured
+func (b *bucketData) versionLabel(v *version) string {
+	if v.id == "" && b.versioning.Status != "" {
+		return "null"
+	}
+	return v.id
+}
+
+// latest returns the latest version of key, or nil
+func (b *bucketData) latest(key string) *version {
+	versions := b.objects[key]
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions[len(versions)-1]
+}
+
+// version returns the version of key named by versionID, or nil
+func (b *bucketData) version(key, versionID string) *version {
+	if versionID == "null" {
+		versionID = ""
+	}
+	for _, v := range b.objects[key] {
+		if v.id == versionID {
+			return v
+		}
+	}
+	return nil
+}
+
+// put stores v as the latest version of its key. Without versioning
+// enabled it takes the place of the null version.
+func (b *bucketData) put(s *Store, v *version) {
+	if b.versioning.Status == versioningEnabled {
+		v.id = s.newID()
+	} else {
+		v.id = ""
+		b.removeVersion(v.key, "")
+	}
+	if v.storageClass == "" {
+		v.storageClass = "STANDARD"
+	}
+	if !v.deleteMarker && b.lockConfig != nil && b.lockConfig.Rule != nil && v.retention == "" {
+		retention := b.lockConfig.Rule.DefaultRetention
+		v.retention = retention.Mode
+		v.retainUntil = v.modified.AddDate(retention.Years, 0, retention.Days)
+	}
+	b.objects[v.key] = append(b.objects[v.key], v)
+}
+
+// removeVersion removes a version of key, if present
+func (b *bucketData) removeVersion(key, id string) {
+	versions := slices.DeleteFunc(b.objects[key], func(v *version) bool {
+		return v.id == id
+	})
+	if len(versions) == 0 {
+		delete(b.objects, key)
+		return
+	}
+	b.objects[key] = versions
+}
+
+// empty reports whether the bucket holds no object versions
+func (b *bucketData) empty() bool {
+	return len(b.objects) == 0
+}
+
+// info describes a version as Stat and Get do
+func (b *bucketData) info(v *version) types.ObjectInfo {
+	info := types.ObjectInfo{
+		Key:            v.key,
+		Size:           int64(len(v.data)),
+		ETag:           v.etag,
+		ContentType:    v.contentType,
+		LastModified:   v.modified,
+		VersionID:      b.versionLabel(v),
+		IsDeleteMarker: v.deleteMarker,
+		UserMetadata:   make(types.StringMap, len(v.userMetadata)),
+		UserTagCount:   len(v.tags),
+	}
+	if v.storageClass != "STANDARD" {
+		info.StorageClass = v.storageClass
+	}
+	for k, value := range v.userMetadata {
+		info.UserMetadata[k] = value
+	}
+	return info
+}
+
+// locked returns an error when v is protected from deletion by a legal
+// hold or an unexpired retention period
+func (v *version) locked(bucketName string, now time.Time, bypassGovernance bool) error {
+	if v.legalHold == objectlock.LegalHoldOn {
+		return apiError(apierrors.ErrCodeAccessDenied, http.StatusForbidden, "Object is WORM protected by a legal hold", bucketName, v.key)
+	}
+	if v.retention == "" || !now.Before(v.retainUntil) {
+		return nil
+	}
+	if v.retention == objectlock.RetentionGovernance && bypassGovernance {
+		return nil
+	}
+	return apiError(apierrors.ErrCodeAccessDenied, http.StatusForbidden, "Object is WORM protected and cannot be overwritten", bucketName, v.key)
+}
+
+// apiError returns the error the client reports for an S3 error response
+func apiError(code apierrors.RustfsGoErrorCode, status int, message, bucketName, objectName string) error {
+	return apierrors.NewAPIError(code, message, status).WithResource("/" + bucketName + "/" + objectName)
+}
+
+// errNotFound is the error the client reports for a 404 response without a
+// body, as to a HEAD request
+func errNotFound(bucketName, objectName string) error {
+	return apiError(apierrors.ErrCodeNoSuchKey, http.StatusNotFound, http.StatusText(http.StatusNotFound), bucketName, objectName)
+}
+
+// errNoSuchKey reports a missing object
+func errNoSuchKey(bucketName, objectName string) error {
+	return apiError(apierrors.ErrCodeNoSuchKey, http.StatusNotFound, "The specified key does not exist.", bucketName, objectName)
+}
+
+// errNotSupported reports a method the store cannot serve
+func errNotSupported(method string) error {
+	return fmt.Errorf("memory: %s: %w", method, object.ErrNotImplemented)
+}
+
+// etag returns the ETag of data
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cloneMap returns a copy of m, or nil when it is empty
+func cloneMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	clone := make(map[string]string, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
@@ -0,0 +1,476 @@
+// Package memory rustfstest/memory/multipart.go
+package memory
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/notification"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const (
+	// maxParts is the highest part number and the most parts of an upload
+	maxParts = 10000
+)
+
+// upload is an in-progress multipart upload
+type upload struct {
+	id        string
+	key       string
+	initiated time.Time
+	options   object.PutOptions
+	parts     map[int]*part
+}
+
+// part is an uploaded part
+type part struct {
+	number   int
+	data     []byte
+	etag     string
+	modified time.Time
+}
+
+// upload returns an upload of key; the caller holds s.mu
+func (b *bucketData) upload(id, key string) (*upload, error) {
+	u, ok := b.uploads[id]
+	if !ok || u.key != key {
+		return nil, apiError(apierrors.ErrCodeNoSuchUpload, http.StatusNotFound, "The specified multipart upload does not exist. The upload ID might be invalid, or the multipart upload might have been aborted or completed.", b.name, key)
+	}
+	return u, nil
+}
+
+// InitiateMultipartUpload starts a multipart upload and returns its ID
+func (o *objectService) InitiateMultipartUpload(ctx context.Context, bucketName, objectName string, opts ...object.PutOption) (string, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.InitiateMultipartUpload", bucketName, objectName, options)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return "", err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return "", err
+	}
+	u := &upload{
+		id:        s.newID(),
+		key:       objectName,
+		initiated: s.now(),
+		options:   options,
+		parts:     make(map[int]*part),
+	}
+	b.uploads[u.id] = u
+	return u.id, nil
+}
+
+// UploadPart stores a part of a multipart upload, replacing any part with
+// the same number
+func (o *objectService) UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, partSize int64, opts ...object.PutOption) (types.ObjectPart, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.UploadPart", bucketName, objectName, options, uploadID, partNumber, partSize)
+	if err := validatePart(bucketName, objectName, uploadID, partNumber); err != nil {
+		return types.ObjectPart{}, err
+	}
+	data, err := readAll(ctx, reader, partSize)
+	if err != nil {
+		return types.ObjectPart{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storePart(bucketName, objectName, uploadID, partNumber, data)
+}
+
+// UploadPartCopy stores a part copied from an object or a range of it
+func (o *objectService) UploadPartCopy(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, src object.SourceInfo, opts ...object.PutOption) (types.ObjectPart, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.UploadPartCopy", bucketName, objectName, options, uploadID, partNumber, src)
+	if err := validatePart(bucketName, objectName, uploadID, partNumber); err != nil {
+		return types.ObjectPart{}, err
+	}
+	if err := validateNames(src.Bucket, src.Object); err != nil {
+		return types.ObjectPart{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := s.sourceData(src)
+	if err != nil {
+		return types.ObjectPart{}, err
+	}
+	return s.storePart(bucketName, objectName, uploadID, partNumber, data)
+}
+
+// validatePart checks the arguments of a part upload as the client does
+func validatePart(bucketName, objectName, uploadID string, partNumber int) error {
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if uploadID == "" {
+		return fmt.Errorf("upload ID cannot be empty")
+	}
+	if partNumber <= 0 {
+		return fmt.Errorf("part number must be greater than 0")
+	}
+	return nil
+}
+
+// storePart adds a part to an upload; the caller holds s.mu
+func (s *Store) storePart(bucketName, objectName, uploadID string, partNumber int, data []byte) (types.ObjectPart, error) {
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return types.ObjectPart{}, err
+	}
+	u, err := b.upload(uploadID, objectName)
+	if err != nil {
+		return types.ObjectPart{}, err
+	}
+	if partNumber > maxParts {
+		return types.ObjectPart{}, apiError(apierrors.ErrCodeInvalidArgument, http.StatusBadRequest, "Part number must be an integer between 1 and 10000, inclusive", bucketName, objectName)
+	}
+	p := &part{number: partNumber, data: data, etag: etag(data), modified: s.now()}
+	u.parts[partNumber] = p
+	return objectPart(p), nil
+}
+
+// sourceData returns the bytes a copy source names, after checking its
+// conditions; the caller holds s.mu
+func (s *Store) sourceData(src object.SourceInfo) ([]byte, error) {
+	_, v, err := s.find(src.Bucket, src.Object, src.VersionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCopyConditions(v, src.MatchETag, src.NotMatchETag, src.MatchModified, src.NotModified); err != nil {
+		return nil, err
+	}
+	if !src.RangeSet {
+		return v.data, nil
+	}
+	if src.RangeStart < 0 || src.RangeEnd < src.RangeStart || src.RangeEnd >= int64(len(v.data)) {
+		return nil, apiError(apierrors.ErrInvalidRange, http.StatusRequestedRangeNotSatisfiable, "The requested range is not satisfiable", src.Bucket, src.Object)
+	}
+	return v.data[src.RangeStart : src.RangeEnd+1], nil
+}
+
+// objectPart describes a part as the client reports it
+func objectPart(p *part) types.ObjectPart {
+	return types.ObjectPart{
+		PartNumber:   p.number,
+		ETag:         p.etag,
+		Size:         int64(len(p.data)),
+		LastModified: p.modified.Format(time.RFC3339),
+	}
+}
+
+// CompleteMultipartUpload assembles the listed parts into an object. As
+// the client does, parts are sent in ascending order; every part but the
+// last must be at least 5 MiB.
+func (o *objectService) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []types.ObjectPart, opts ...object.PutOption) (types.UploadInfo, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.CompleteMultipartUpload", bucketName, objectName, options, uploadID, slices.Clone(parts))
+	if err := validateNames(bucketName, objectName); err != nil {
+		return types.UploadInfo{}, err
+	}
+	if uploadID == "" {
+		return types.UploadInfo{}, fmt.Errorf("upload ID cannot be empty")
+	}
+	if len(parts) == 0 {
+		return types.UploadInfo{}, fmt.Errorf("parts cannot be empty")
+	}
+	parts = slices.Clone(parts)
+	slices.SortFunc(parts, func(a, b types.ObjectPart) int {
+		return a.PartNumber - b.PartNumber
+	})
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	u, err := b.upload(uploadID, objectName)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	var data []byte
+	etags := make([]string, len(parts))
+	for i, listed := range parts {
+		if i > 0 && listed.PartNumber == parts[i-1].PartNumber {
+			return types.UploadInfo{}, apiError(apierrors.ErrCodeInvalidPartOrder, http.StatusBadRequest, "The list of parts was not in ascending order. Parts must be ordered by part number.", bucketName, objectName)
+		}
+		p, ok := u.parts[listed.PartNumber]
+		if !ok || p.etag != strings.Trim(listed.ETag, `"`) {
+			return types.UploadInfo{}, apiError(apierrors.ErrCodeInvalidPart, http.StatusBadRequest, "One or more of the specified parts could not be found. The part might not have been uploaded, or the specified entity tag might not have matched the part's entity tag.", bucketName, objectName)
+		}
+		if i < len(parts)-1 && len(p.data) < minPartSize {
+			return types.UploadInfo{}, apiError(apierrors.ErrCodeEntityTooSmall, http.StatusBadRequest, "Your proposed upload is smaller than the minimum allowed object size.", bucketName, objectName)
+		}
+		data = append(data, p.data...)
+		etags[i] = p.etag
+	}
+
+	v := newVersion(objectName, s.now(), data, u.options)
+	v.etag = multipartETag(etags)
+	delete(b.uploads, uploadID)
+	b.put(s, v)
+	s.notify(b, notification.ObjectCreatedCompleteMultipartUpload, v)
+	return uploadInfo(b, v), nil
+}
+
+// multipartETag returns the ETag of an object assembled from parts: the MD5
+// of their binary MD5s, followed by the part count
+func multipartETag(etags []string) string {
+	hash := md5.New()
+	for _, etag := range etags {
+		sum, _ := hex.DecodeString(etag)
+		hash.Write(sum)
+	}
+	return hex.EncodeToString(hash.Sum(nil)) + "-" + strconv.Itoa(len(etags))
+}
+
+// AbortMultipartUpload discards an upload and its parts
+func (o *objectService) AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error {
+	o.store.record("Object.AbortMultipartUpload", bucketName, objectName, nil, uploadID)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if uploadID == "" {
+		return fmt.Errorf("upload ID cannot be empty")
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if _, err := b.upload(uploadID, objectName); err != nil {
+		return err
+	}
+	delete(b.uploads, uploadID)
+	return nil
+}
+
+// ListMultipartUploads returns one page of the in-progress uploads, ordered
+// by key and initiation
+func (o *objectService) ListMultipartUploads(ctx context.Context, bucketName string, opts ...object.MultipartListOption) (object.ListMultipartUploadsResult, error) {
+	options := applyOptions(object.ListMultipartUploadsOptions{MaxUploads: 1000}, opts)
+	o.store.record("Object.ListMultipartUploads", bucketName, "", options)
+	if err := validateBucketName(bucketName); err != nil {
+		return object.ListMultipartUploadsResult{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return object.ListMultipartUploadsResult{}, err
+	}
+	uploads := make([]*upload, 0, len(b.uploads))
+	for _, u := range b.uploads {
+		uploads = append(uploads, u)
+	}
+	slices.SortFunc(uploads, func(a, b *upload) int {
+		if c := strings.Compare(a.key, b.key); c != 0 {
+			return c
+		}
+		if c := a.initiated.Compare(b.initiated); c != 0 {
+			return c
+		}
+		return strings.Compare(a.id, b.id)
+	})
+
+	result := object.ListMultipartUploadsResult{
+		Bucket:         bucketName,
+		KeyMarker:      options.KeyMarker,
+		UploadIDMarker: options.UploadIDMarker,
+		MaxUploads:     options.MaxUploads,
+	}
+	owner := object.UserIdentity{ID: OwnerID, DisplayName: OwnerID}
+	pager := newPager(options.MaxUploads)
+	// Uploads after the upload ID marker of the marker key are listed
+	pastMarker := options.UploadIDMarker == ""
+	for _, u := range uploads {
+		if !strings.HasPrefix(u.key, options.Prefix) || u.key < options.KeyMarker {
+			continue
+		}
+		if u.key == options.KeyMarker {
+			if !pastMarker {
+				pastMarker = u.id == options.UploadIDMarker
+				continue
+			}
+			if options.UploadIDMarker == "" {
+				continue
+			}
+		}
+		if prefix, ok := commonPrefix(u.key, options.Prefix, options.Delimiter); ok {
+			if n := len(result.CommonPrefixes); n > 0 && result.CommonPrefixes[n-1].Prefix == prefix {
+				continue
+			}
+			if !pager.add() {
+				break
+			}
+			result.CommonPrefixes = append(result.CommonPrefixes, object.CommonPrefix{Prefix: prefix})
+			result.NextKeyMarker, result.NextUploadIDMarker = prefix, ""
+			continue
+		}
+		if !pager.add() {
+			break
+		}
+		result.Uploads = append(result.Uploads, object.MultipartUpload{
+			Key:          u.key,
+			UploadID:     u.id,
+			Initiator:    owner,
+			Owner:        owner,
+			StorageClass: "STANDARD",
+			Initiated:    u.initiated.Format(time.RFC3339),
+		})
+		result.NextKeyMarker, result.NextUploadIDMarker = u.key, u.id
+	}
+	result.IsTruncated = pager.truncated
+	if !result.IsTruncated {
+		result.NextKeyMarker, result.NextUploadIDMarker = "", ""
+	}
+	return result, nil
+}
+
+// ListObjectParts returns one page of the parts of an upload
+func (o *objectService) ListObjectParts(ctx context.Context, bucketName, objectName, uploadID string, opts ...object.ListPartsOption) (object.ListPartsResult, error) {
+	options := applyOptions(object.ListPartsOptions{MaxParts: 1000}, opts)
+	o.store.record("Object.ListObjectParts", bucketName, objectName, options, uploadID)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return object.ListPartsResult{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return object.ListPartsResult{}, err
+	}
+	u, err := b.upload(uploadID, objectName)
+	if err != nil {
+		return object.ListPartsResult{}, err
+	}
+
+	owner := object.UserIdentity{ID: OwnerID, DisplayName: OwnerID}
+	result := object.ListPartsResult{
+		Bucket:           bucketName,
+		Key:              objectName,
+		UploadID:         uploadID,
+		PartNumberMarker: options.PartNumberMarker,
+		MaxParts:         options.MaxParts,
+		Initiator:        owner,
+		Owner:            owner,
+		StorageClass:     "STANDARD",
+	}
+	numbers := make([]int, 0, len(u.parts))
+	for number := range u.parts {
+		if number > options.PartNumberMarker {
+			numbers = append(numbers, number)
+		}
+	}
+	slices.Sort(numbers)
+	pager := newPager(options.MaxParts)
+	for _, number := range numbers {
+		if !pager.add() {
+			break
+		}
+		result.Parts = append(result.Parts, objectPart(u.parts[number]))
+		result.NextPartNumberMarker = number
+	}
+	result.IsTruncated = pager.truncated
+	return result, nil
+}
+
+// Compose creates an object from sources, or ranges of them, copied in
+// order. A single whole source is copied and keeps its metadata unless opts
+// set new metadata; otherwise the object gets a multipart ETag.
+func (o *objectService) Compose(ctx context.Context, dst object.DestinationInfo, sources []object.SourceInfo, opts ...object.PutOption) (types.UploadInfo, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.Compose", dst.Bucket, dst.Object, options, slices.Clone(sources))
+	if err := validateNames(dst.Bucket, dst.Object); err != nil {
+		return types.UploadInfo{}, err
+	}
+	if len(sources) == 0 || len(sources) > maxParts {
+		return types.UploadInfo{}, fmt.Errorf("compose requires between 1 and %d source objects", maxParts)
+	}
+	for _, src := range sources {
+		if err := validateNames(src.Bucket, src.Object); err != nil {
+			return types.UploadInfo{}, err
+		}
+		if src.RangeSet && src.RangeStart < 0 {
+			return types.UploadInfo{}, fmt.Errorf("range start must be >= 0")
+		}
+		if src.RangeSet && src.RangeEnd < src.RangeStart {
+			return types.UploadInfo{}, fmt.Errorf("range end must be >= range start")
+		}
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(dst.Bucket)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	var data []byte
+	etags := make([]string, len(sources))
+	for i, src := range sources {
+		_, v, err := s.find(src.Bucket, src.Object, src.VersionID)
+		if err != nil {
+			return types.UploadInfo{}, headError(err, src.Bucket, src.Object)
+		}
+		if src.RangeSet && src.RangeEnd >= int64(len(v.data)) {
+			return types.UploadInfo{}, fmt.Errorf("range end %d exceeds object size %d", src.RangeEnd, len(v.data))
+		}
+		part, err := s.sourceData(src)
+		if err != nil {
+			return types.UploadInfo{}, err
+		}
+		if len(part) < minPartSize && i < len(sources)-1 {
+			return types.UploadInfo{}, fmt.Errorf("source %d is too small (%d bytes) and is not the last part", i, len(part))
+		}
+		data = append(data, part...)
+		etags[i] = etag(part)
+	}
+
+	v := newVersion(dst.Object, s.now(), data, options)
+	event := notification.ObjectCreatedCompleteMultipartUpload
+	if len(sources) == 1 && !sources[0].RangeSet || len(data) == 0 {
+		event = notification.ObjectCreatedCopy
+		_, src, _ := s.find(sources[0].Bucket, sources[0].Object, sources[0].VersionID)
+		if options.ContentType == "" && options.UserMetadata == nil {
+			v.contentType, v.userMetadata = src.contentType, src.userMetadata
+		}
+		if options.UserTags == nil {
+			v.tags = src.tags
+		}
+	} else {
+		v.etag = multipartETag(etags)
+	}
+	b.put(s, v)
+	s.notify(b, event, v)
+	return uploadInfo(b, v), nil
+}
@@ -0,0 +1,156 @@
+// Package memory rustfstest/memory/notify.go
+package memory
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/notification"
+)
+
+// listener is a ListenNotification subscription. Events queue up without
+// bound so that notify never blocks the store.
+type listener struct {
+	bucket string
+	prefix string
+	suffix string
+	events []notification.EventType
+
+	mu    sync.Mutex
+	queue []notification.Event
+	wake  chan struct{}
+}
+
+// matches reports whether the listener wants an event of eventType on key
+func (l *listener) matches(bucketName, key string, eventType notification.EventType) bool {
+	if l.bucket != bucketName || !strings.HasPrefix(key, l.prefix) || !strings.HasSuffix(key, l.suffix) {
+		return false
+	}
+	if len(l.events) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(l.events, func(pattern notification.EventType) bool {
+		if wildcard, ok := strings.CutSuffix(string(pattern), "*"); ok {
+			return strings.HasPrefix(string(eventType), wildcard)
+		}
+		return pattern == eventType
+	})
+}
+
+// push queues an event and wakes the listener
+func (l *listener) push(event notification.Event) {
+	l.mu.Lock()
+	l.queue = append(l.queue, event)
+	l.mu.Unlock()
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drain takes the queued events
+func (l *listener) drain() []notification.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	events := l.queue
+	l.queue = nil
+	return events
+}
+
+// ListenNotification streams the events of a bucket, one record per Info,
+// until ctx is done. Events are matched against prefix, suffix and events,
+// where "s3:ObjectCreated:*" style wildcards match a whole category and no
+// events match all of them.
+func (bs *bucketService) ListenNotification(ctx context.Context, bucketName, prefix, suffix string, events []notification.EventType) <-chan notification.Info {
+	bs.store.record("Bucket.ListenNotification", bucketName, "", nil, prefix, suffix, slices.Clone(events))
+	infoCh := make(chan notification.Info, 1)
+
+	l := &listener{
+		bucket: bucketName,
+		prefix: prefix,
+		suffix: suffix,
+		events: slices.Clone(events),
+		wake:   make(chan struct{}, 1),
+	}
+	err := bs.store.withBucket(bucketName, func(*bucketData) error {
+		bs.store.listeners = append(bs.store.listeners, l)
+		return nil
+	})
+	if err != nil {
+		infoCh <- notification.Info{Err: err}
+		close(infoCh)
+		return infoCh
+	}
+
+	go func() {
+		defer close(infoCh)
+		defer bs.store.unsubscribe(l)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-l.wake:
+			}
+			for _, event := range l.drain() {
+				select {
+				case infoCh <- notification.Info{Records: []notification.Event{event}}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return infoCh
+}
+
+// unsubscribe removes a listener
+func (s *Store) unsubscribe(l *listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = slices.DeleteFunc(s.listeners, func(other *listener) bool {
+		return other == l
+	})
+}
+
+// notify sends an event about v to the matching listeners; the caller holds
+// s.mu
+func (s *Store) notify(b *bucketData, eventType notification.EventType, v *version) {
+	var event *notification.Event
+	for _, l := range s.listeners {
+		if !l.matches(b.name, v.key, eventType) {
+			continue
+		}
+		if event == nil {
+			event = &notification.Event{
+				EventVersion: "2.0",
+				EventSource:  "rustfs:s3",
+				AwsRegion:    b.region,
+				EventTime:    s.now().Format(time.RFC3339),
+				EventName:    string(eventType),
+				UserIdentity: notification.Identity{PrincipalID: OwnerID},
+				S3: notification.EventMeta{
+					SchemaVersion: "1.0",
+					Bucket: notification.BucketMeta{
+						Name:          b.name,
+						OwnerIdentity: notification.Identity{PrincipalID: OwnerID},
+						ARN:           "arn:aws:s3:::" + b.name,
+					},
+					Object: notification.ObjectMeta{
+						Key:          v.key,
+						Size:         int64(len(v.data)),
+						ETag:         v.etag,
+						ContentType:  v.contentType,
+						UserMetadata: cloneMap(v.userMetadata),
+						VersionID:    b.versionLabel(v),
+						Sequencer:    strconv.FormatInt(s.ids.Add(1), 16),
+					},
+				},
+			}
+		}
+		l.push(*event)
+	}
+}
@@ -0,0 +1,642 @@
+// Package memory rustfstest/memory/object.go
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	apierrors "github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/cse"
+	"github.com/Scorpio69t/rustfs-go/pkg/notification"
+	"github.com/Scorpio69t/rustfs-go/pkg/policy"
+	"github.com/Scorpio69t/rustfs-go/pkg/restore"
+	s3select "github.com/Scorpio69t/rustfs-go/pkg/select"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+var _ object.Service = (*objectService)(nil)
+
+// objectService implements object.Service on a Store
+type objectService struct {
+	store *Store
+}
+
+// applyOptions applies functional options to their defaults
+func applyOptions[T any, O ~func(*T)](options T, opts []O) T {
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// validateNames checks names as the client does before sending a request
+func validateNames(bucketName, objectName string) error {
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+	if objectName == "" {
+		return object.ErrInvalidObjectName
+	}
+	return nil
+}
+
+// validateBucketName checks a bucket name as the client does
+func validateBucketName(bucketName string) error {
+	if len(bucketName) < 3 || len(bucketName) > 63 {
+		return object.ErrInvalidBucketName
+	}
+	return nil
+}
+
+// Put stores an object
+func (o *objectService) Put(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts ...object.PutOption) (types.UploadInfo, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.Put", bucketName, objectName, options, objectSize)
+	return o.put(ctx, bucketName, objectName, reader, objectSize, options)
+}
+
+// put stores an object read from reader
+func (o *objectService) put(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, options object.PutOptions) (types.UploadInfo, error) {
+	if err := validateNames(bucketName, objectName); err != nil {
+		return types.UploadInfo{}, err
+	}
+	if options.CSE != nil {
+		return types.UploadInfo{}, errNotSupported("client-side encryption")
+	}
+	data, err := readAll(ctx, reader, objectSize)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	v := newVersion(objectName, s.now(), data, options)
+	b.put(s, v)
+	s.notify(b, notification.ObjectCreatedPut, v)
+	return uploadInfo(b, v), nil
+}
+
+// newVersion returns an object version with the headers of a Put
+func newVersion(key string, modified time.Time, data []byte, options object.PutOptions) *version {
+	v := &version{
+		key:          key,
+		modified:     modified,
+		data:         data,
+		etag:         etag(data),
+		contentType:  options.ContentType,
+		storageClass: options.StorageClass,
+		userMetadata: userMetadata(options.UserMetadata),
+		tags:         cloneMap(options.UserTags),
+	}
+	if v.contentType == "" {
+		v.contentType = "application/octet-stream"
+	}
+	return v
+}
+
+// userMetadata returns metadata keyed as the client reads it back from
+// X-Amz-Meta- headers
+func userMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	canonical := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		canonical[http.CanonicalHeaderKey(k)] = v
+	}
+	return canonical
+}
+
+// uploadInfo describes a stored version as Put does
+func uploadInfo(b *bucketData, v *version) types.UploadInfo {
+	return types.UploadInfo{
+		Bucket:       b.name,
+		Key:          v.key,
+		ETag:         v.etag,
+		Size:         int64(len(v.data)),
+		LastModified: v.modified,
+		VersionID:    b.versionLabel(v),
+	}
+}
+
+// readAll reads size bytes from reader, or up to EOF when size is negative
+func readAll(ctx context.Context, reader io.Reader, size int64) ([]byte, error) {
+	if reader == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return io.ReadAll(reader)
+	}
+	data := make([]byte, size)
+	if n, err := io.ReadFull(reader, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("read %d of %d bytes: %w", n, size, err)
+	}
+	return data, nil
+}
+
+// Get returns the content of an object, honoring ranges and conditions
+func (o *objectService) Get(ctx context.Context, bucketName, objectName string, opts ...object.GetOption) (io.ReadCloser, types.ObjectInfo, error) {
+	options := applyOptions(object.GetOptions{}, opts)
+	o.store.record("Object.Get", bucketName, objectName, options)
+	data, info, err := o.get(ctx, bucketName, objectName, options)
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), info, nil
+}
+
+// get returns the content of an object
+func (o *objectService) get(ctx context.Context, bucketName, objectName string, options object.GetOptions) ([]byte, types.ObjectInfo, error) {
+	if err := validateNames(bucketName, objectName); err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+	if options.CSE != nil {
+		return nil, types.ObjectInfo{}, errNotSupported("client-side encryption")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, v, err := s.find(bucketName, objectName, options.VersionID)
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+	err = checkConditions(v, options.MatchETag, options.NotMatchETag, options.MatchModified, options.NotModified)
+	if err != nil {
+		return nil, types.ObjectInfo{}, err
+	}
+
+	info := b.info(v)
+	data := v.data
+	if options.SetRange {
+		start, end, ok := byteRange(options.RangeStart, options.RangeEnd, int64(len(data)))
+		if !ok {
+			return nil, types.ObjectInfo{}, apiError(apierrors.ErrInvalidRange, http.StatusRequestedRangeNotSatisfiable, "The requested range is not satisfiable", bucketName, objectName)
+		}
+		data = data[start : end+1]
+		info.Size = int64(len(data))
+	}
+	return data, info, nil
+}
+
+// find returns the version a GET request names. A delete marker as the
+// latest version hides the object; named explicitly, it is not readable.
+// The caller holds s.mu.
+func (s *Store) find(bucketName, objectName, versionID string) (*bucketData, *version, error) {
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if versionID != "" {
+		v := b.version(objectName, versionID)
+		switch {
+		case v == nil:
+			return nil, nil, apiError(apierrors.ErrCodeNoSuchVersion, http.StatusNotFound, "The specified version does not exist.", bucketName, objectName)
+		case v.deleteMarker:
+			return nil, nil, apiError(apierrors.ErrCodeMethodNotAllowed, http.StatusMethodNotAllowed, "The specified method is not allowed against this resource.", bucketName, objectName)
+		}
+		return b, v, nil
+	}
+	v := b.latest(objectName)
+	if v == nil || v.deleteMarker {
+		return nil, nil, errNoSuchKey(bucketName, objectName)
+	}
+	return b, v, nil
+}
+
+// checkConditions evaluates conditional request headers against v in the
+// order of RFC 9110
+func checkConditions(v *version, matchETag, notMatchETag string, modifiedSince, unmodifiedSince time.Time) error {
+	if matchETag != "" && !etagMatches(matchETag, v.etag) ||
+		matchETag == "" && !unmodifiedSince.IsZero() && v.modified.After(unmodifiedSince.Truncate(time.Second)) {
+		return apiError(apierrors.ErrCodePreconditionFailed, http.StatusPreconditionFailed, "At least one of the pre-conditions you specified did not hold", "", v.key)
+	}
+	if notMatchETag != "" && etagMatches(notMatchETag, v.etag) ||
+		notMatchETag == "" && !modifiedSince.IsZero() && !v.modified.After(modifiedSince.Truncate(time.Second)) {
+		return apiError(apierrors.ErrCodeNotModified, http.StatusNotModified, http.StatusText(http.StatusNotModified), "", v.key)
+	}
+	return nil
+}
+
+// etagMatches reports whether a list of ETags, or *, matches etag
+func etagMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.Trim(strings.TrimSpace(candidate), `"`)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// byteRange resolves a range option as the client sends it, bytes=start-
+// or bytes=start-end, to inclusive offsets. Malformed ranges select the
+// whole object; ok is false when the range starts past the end.
+func byteRange(start, end, size int64) (int64, int64, bool) {
+	if size == 0 || start < 0 || (end > 0 && end < start) {
+		return 0, size - 1, true
+	}
+	if start >= size {
+		return 0, 0, false
+	}
+	if (end <= 0 && end < start) || end >= size {
+		end = size - 1
+	}
+	return start, end, true
+}
+
+// Open returns a handle reading the object through the service
+func (o *objectService) Open(ctx context.Context, bucketName, objectName string, opts ...object.GetOption) (*object.Object, error) {
+	o.store.record("Object.Open", bucketName, objectName, applyOptions(object.GetOptions{}, opts))
+	return object.NewObject(ctx, o, bucketName, objectName, opts...)
+}
+
+// FPut stores the content of a local file
+func (o *objectService) FPut(ctx context.Context, bucketName, objectName, filePath string, opts ...object.PutOption) (types.UploadInfo, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.FPut", bucketName, objectName, options, filePath)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	if options.ContentType == "" {
+		options.ContentType = mime.TypeByExtension(strings.ToLower(filepath.Ext(filePath)))
+	}
+	return o.put(ctx, bucketName, objectName, file, stat.Size(), options)
+}
+
+// FGet writes the content of an object to a local file
+func (o *objectService) FGet(ctx context.Context, bucketName, objectName, filePath string, opts ...object.GetOption) (types.ObjectInfo, error) {
+	options := applyOptions(object.GetOptions{}, opts)
+	o.store.record("Object.FGet", bucketName, objectName, options, filePath)
+	data, info, err := o.get(ctx, bucketName, objectName, options)
+	if err != nil {
+		return types.ObjectInfo{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return types.ObjectInfo{}, err
+	}
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return types.ObjectInfo{}, err
+	}
+	return info, nil
+}
+
+// Stat returns the metadata of an object. Like a HEAD request, whose
+// response carries no error code, every 404 is reported as NoSuchKey.
+func (o *objectService) Stat(ctx context.Context, bucketName, objectName string, opts ...object.StatOption) (types.ObjectInfo, error) {
+	options := applyOptions(object.StatOptions{}, opts)
+	o.store.record("Object.Stat", bucketName, objectName, options)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return types.ObjectInfo{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return types.ObjectInfo{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, v, err := s.find(bucketName, objectName, options.VersionID)
+	if err != nil {
+		return types.ObjectInfo{}, headError(err, bucketName, objectName)
+	}
+	return b.info(v), nil
+}
+
+// headError returns the error the client reports for err in answer to a
+// HEAD request, which has only the status code
+func headError(err error, bucketName, objectName string) error {
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	status := apiErr.StatusCode()
+	if status == http.StatusNotFound {
+		return errNotFound(bucketName, objectName)
+	}
+	return apiError(apiErr.Code(), status, http.StatusText(status), bucketName, objectName)
+}
+
+// Delete removes an object. Without a version ID, a versioned bucket gets a
+// delete marker; removing a version fails while it is locked. Governance
+// retention is bypassed with the x-amz-bypass-governance-retention header.
+func (o *objectService) Delete(ctx context.Context, bucketName, objectName string, opts ...object.DeleteOption) error {
+	options := applyOptions(object.DeleteOptions{}, opts)
+	o.store.record("Object.Delete", bucketName, objectName, options)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	bypass := strings.EqualFold(options.CustomHeaders.Get("x-amz-bypass-governance-retention"), "true")
+	return s.deleteObject(b, objectName, options.VersionID, bypass)
+}
+
+// deleteObject deletes an object or one of its versions; the caller holds
+// s.mu
+func (s *Store) deleteObject(b *bucketData, key, versionID string, bypassGovernance bool) error {
+	if versionID == "" {
+		if b.versioning.Status == "" {
+			if v := b.latest(key); v != nil {
+				b.removeVersion(key, "")
+				s.notify(b, notification.ObjectRemovedDelete, v)
+			}
+			return nil
+		}
+		marker := &version{key: key, deleteMarker: true, modified: s.now()}
+		b.put(s, marker)
+		s.notify(b, notification.ObjectRemovedDeleteMarkerCreated, marker)
+		return nil
+	}
+
+	v := b.version(key, versionID)
+	if v == nil {
+		return nil
+	}
+	if err := v.locked(b.name, s.now(), bypassGovernance); err != nil {
+		return err
+	}
+	b.removeVersion(key, v.id)
+	s.notify(b, notification.ObjectRemovedDelete, v)
+	return nil
+}
+
+// DeleteMany deletes the objects received from objectsCh and reports the
+// ones that could not be deleted
+func (o *objectService) DeleteMany(ctx context.Context, bucketName string, objectsCh <-chan types.ObjectToDelete, opts ...object.DeleteManyOption) <-chan types.DeleteError {
+	options := applyOptions(object.DeleteManyOptions{}, opts)
+	o.store.record("Object.DeleteMany", bucketName, "", options)
+	errorCh := make(chan types.DeleteError)
+
+	go func() {
+		defer close(errorCh)
+		send := func(deleteErr types.DeleteError) bool {
+			select {
+			case errorCh <- deleteErr:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if len(bucketName) < 3 || len(bucketName) > 63 {
+			send(types.DeleteError{Err: object.ErrInvalidBucketName})
+			return
+		}
+
+		for {
+			var obj types.ObjectToDelete
+			select {
+			case <-ctx.Done():
+				return
+			case next, ok := <-objectsCh:
+				if !ok {
+					return
+				}
+				obj = next
+			}
+			if obj.Key == "" {
+				continue
+			}
+			if deleteErr, failed := o.deleteOne(bucketName, obj, options.GovernanceBypass); failed && !send(deleteErr) {
+				return
+			}
+		}
+	}()
+	return errorCh
+}
+
+// deleteOne deletes an object for DeleteMany; a missing bucket fails the
+// request, other errors are reported per key
+func (o *objectService) deleteOne(bucketName string, obj types.ObjectToDelete, bypassGovernance bool) (types.DeleteError, bool) {
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleteErr := types.DeleteError{Key: obj.Key, VersionID: obj.VersionID}
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		deleteErr.Err = err
+		return deleteErr, true
+	}
+	err = s.deleteObject(b, obj.Key, obj.VersionID, bypassGovernance)
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		return deleteErr, false
+	}
+	deleteErr.Code, deleteErr.Message = apiErr.ErrorCode, apiErr.ErrorMessage
+	return deleteErr, true
+}
+
+// Copy copies an object within the store
+func (o *objectService) Copy(ctx context.Context, destBucket, destObject, srcBucket, srcObject string, opts ...object.CopyOption) (types.CopyInfo, error) {
+	options := applyOptions(object.CopyOptions{}, opts)
+	o.store.record("Object.Copy", destBucket, destObject, options, srcBucket, srcObject)
+	if err := validateNames(destBucket, destObject); err != nil {
+		return types.CopyInfo{}, err
+	}
+	if err := validateNames(srcBucket, srcObject); err != nil {
+		return types.CopyInfo{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return types.CopyInfo{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dst, err := s.bucket(destBucket)
+	if err != nil {
+		return types.CopyInfo{}, err
+	}
+	srcB, src, err := s.find(srcBucket, srcObject, options.SourceVersionID)
+	if err != nil {
+		return types.CopyInfo{}, err
+	}
+	if err := checkCopyConditions(src, options.MatchETag, options.NotMatchETag, options.MatchModified, options.NotModified); err != nil {
+		return types.CopyInfo{}, err
+	}
+	if destBucket == srcBucket && destObject == srcObject && !options.ReplaceMetadata && !options.ReplaceTagging && options.StorageClass == "" {
+		return types.CopyInfo{}, apiError(apierrors.ErrCodeInvalidRequest, http.StatusBadRequest, "This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata, storage class, website redirect location or encryption attributes.", destBucket, destObject)
+	}
+
+	v := &version{
+		key:          destObject,
+		modified:     s.now(),
+		data:         src.data,
+		etag:         etag(src.data),
+		contentType:  src.contentType,
+		storageClass: options.StorageClass,
+		userMetadata: src.userMetadata,
+		tags:         src.tags,
+	}
+	if options.ReplaceMetadata {
+		v.contentType = options.ContentType
+		if v.contentType == "" {
+			v.contentType = "application/octet-stream"
+		}
+		v.userMetadata = userMetadata(options.UserMetadata)
+	}
+	if options.ReplaceTagging {
+		v.tags = cloneMap(options.UserTags)
+	}
+	dst.put(s, v)
+	s.notify(dst, notification.ObjectCreatedCopy, v)
+
+	return types.CopyInfo{
+		Bucket:          destBucket,
+		Key:             destObject,
+		ETag:            v.etag,
+		VersionID:       dst.versionLabel(v),
+		SourceVersionID: srcB.versionLabel(src),
+		LastModified:    v.modified,
+	}, nil
+}
+
+// checkCopyConditions evaluates the conditions on a copy source, all of
+// which fail with PreconditionFailed
+func checkCopyConditions(src *version, matchETag, notMatchETag string, modifiedSince, unmodifiedSince time.Time) error {
+	err := checkConditions(src, matchETag, notMatchETag, modifiedSince, unmodifiedSince)
+	var apiErr *apierrors.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode() == http.StatusNotModified {
+		return apiError(apierrors.ErrCodePreconditionFailed, http.StatusPreconditionFailed, "At least one of the pre-conditions you specified did not hold", "", src.key)
+	}
+	return err
+}
+
+// Rewrap is not supported: the store does not keep client-side encryption
+// envelopes
+func (o *objectService) Rewrap(ctx context.Context, bucketName, objectName string, client *cse.Client) (types.CopyInfo, error) {
+	o.store.record("Object.Rewrap", bucketName, objectName, nil)
+	return types.CopyInfo{}, errNotSupported("Rewrap")
+}
+
+// Append appends to an object at offset, which must be its current size
+func (o *objectService) Append(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, offset int64, opts ...object.PutOption) (types.UploadInfo, error) {
+	options := applyOptions(object.PutOptions{}, opts)
+	o.store.record("Object.Append", bucketName, objectName, options, objectSize, offset)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return types.UploadInfo{}, err
+	}
+	if objectSize < 0 {
+		return types.UploadInfo{}, fmt.Errorf("object size must be non-negative")
+	}
+	data, err := readAll(ctx, reader, objectSize)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.bucket(bucketName)
+	if err != nil {
+		return types.UploadInfo{}, err
+	}
+	v := newVersion(objectName, s.now(), nil, options)
+	switch current := b.latest(objectName); {
+	case current == nil || current.deleteMarker:
+		if offset != 0 {
+			return types.UploadInfo{}, errNoSuchKey(bucketName, objectName)
+		}
+	case offset != int64(len(current.data)):
+		return types.UploadInfo{}, apiError("InvalidWriteOffset", http.StatusBadRequest, fmt.Sprintf("The write offset %d does not match the object size %d.", offset, len(current.data)), bucketName, objectName)
+	default:
+		v.contentType, v.storageClass = current.contentType, current.storageClass
+		v.userMetadata, v.tags = current.userMetadata, current.tags
+		v.data = append(v.data, current.data...)
+	}
+	v.data = append(v.data, data...)
+	v.etag = etag(v.data)
+	b.put(s, v)
+	s.notify(b, notification.ObjectCreatedPut, v)
+	return uploadInfo(b, v), nil
+}
+
+// Select is not supported
+func (o *objectService) Select(ctx context.Context, bucketName, objectName string, opts s3select.Options) (*s3select.Results, error) {
+	o.store.record("Object.Select", bucketName, objectName, opts)
+	return nil, errNotSupported("Select")
+}
+
+// Restore fails as for any object not in an archive storage class
+func (o *objectService) Restore(ctx context.Context, bucketName, objectName, versionID string, req restore.RestoreRequest) error {
+	o.store.record("Object.Restore", bucketName, objectName, nil, versionID, req)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, _, err := s.find(bucketName, objectName, versionID); err != nil {
+		return err
+	}
+	return apiError("InvalidObjectState", http.StatusForbidden, "Restore is not allowed for the object's current storage class", bucketName, objectName)
+}
+
+// PresignedPostPolicy is not supported: there is no server to post to
+func (o *objectService) PresignedPostPolicy(ctx context.Context, policy *policy.PostPolicy) (*url.URL, map[string]string, error) {
+	o.store.record("Object.PresignedPostPolicy", "", "", nil, policy)
+	return nil, nil, errNotSupported("PresignedPostPolicy")
+}
+
+// PresignGet is not supported: there is no server to sign for
+func (o *objectService) PresignGet(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values, opts ...object.PresignOption) (*url.URL, http.Header, error) {
+	o.store.record("Object.PresignGet", bucketName, objectName, applyOptions(object.PresignOptions{}, opts), expires, reqParams)
+	return nil, nil, errNotSupported("PresignGet")
+}
+
+// PresignHead is not supported: there is no server to sign for
+func (o *objectService) PresignHead(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values, opts ...object.PresignOption) (*url.URL, http.Header, error) {
+	o.store.record("Object.PresignHead", bucketName, objectName, applyOptions(object.PresignOptions{}, opts), expires, reqParams)
+	return nil, nil, errNotSupported("PresignHead")
+}
+
+// PresignPut is not supported: there is no server to sign for
+func (o *objectService) PresignPut(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values, opts ...object.PresignOption) (*url.URL, http.Header, error) {
+	o.store.record("Object.PresignPut", bucketName, objectName, applyOptions(object.PresignOptions{}, opts), expires, reqParams)
+	return nil, nil, errNotSupported("PresignPut")
+}
@@ -0,0 +1,443 @@
+// Package memory rustfstest/memory/object_test.go
+package memory
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/bucket"
+	"github.com/Scorpio69t/rustfs-go/errors"
+	"github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/pkg/objectlock"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// errCode returns the S3 error code of err
+func errCode(err error) string {
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.ErrorCode
+	}
+	return ""
+}
+
+// newStore returns a store with a bucket created with opts
+func newStore(t *testing.T, opts ...bucket.CreateOption) *Store {
+	t.Helper()
+	store := New(Config{})
+	if err := store.Bucket().Create(context.Background(), "test-bucket", opts...); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return store
+}
+
+func put(t *testing.T, store *Store, key, data string, opts ...object.PutOption) types.UploadInfo {
+	t.Helper()
+	info, err := store.Object().Put(context.Background(), "test-bucket", key, strings.NewReader(data), int64(len(data)), opts...)
+	if err != nil {
+		t.Fatalf("Put(%q) error = %v", key, err)
+	}
+	return info
+}
+
+func get(t *testing.T, store *Store, key string, opts ...object.GetOption) (string, types.ObjectInfo) {
+	t.Helper()
+	reader, info, err := store.Object().Get(context.Background(), "test-bucket", key, opts...)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading %q: %v", key, err)
+	}
+	return string(data), info
+}
+
+func TestPutGetStat(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	uploaded := put(t, store, "a.txt", "hello world",
+		object.WithContentType("text/plain"),
+		object.WithUserMetadata(map[string]string{"owner": "alice"}),
+		object.WithUserTags(map[string]string{"team": "sdk"}))
+
+	data, info := get(t, store, "a.txt")
+	if data != "hello world" {
+		t.Errorf("Get() data = %q", data)
+	}
+	if info.ETag != uploaded.ETag || info.ContentType != "text/plain" || info.Size != 11 {
+		t.Errorf("Get() info = %+v, upload = %+v", info, uploaded)
+	}
+	if info.UserMetadata["Owner"] != "alice" || info.UserTagCount != 1 {
+		t.Errorf("Get() metadata = %v, tag count = %d", info.UserMetadata, info.UserTagCount)
+	}
+
+	stat, err := store.Object().Stat(ctx, "test-bucket", "a.txt")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if stat.ETag != info.ETag || !stat.LastModified.Equal(info.LastModified) {
+		t.Errorf("Stat() = %+v, want %+v", stat, info)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+
+	_, _, err := store.Object().Get(ctx, "test-bucket", "missing")
+	if !errors.IsObjectNotFound(err) {
+		t.Errorf("Get() error = %v, want NoSuchKey", err)
+	}
+	_, err = store.Object().Stat(ctx, "test-bucket", "missing")
+	if !errors.IsObjectNotFound(err) {
+		t.Errorf("Stat() error = %v, want NoSuchKey", err)
+	}
+	_, err = store.Object().Put(ctx, "no-bucket", "key", strings.NewReader("x"), 1)
+	if !errors.IsBucketNotFound(err) {
+		t.Errorf("Put() error = %v, want NoSuchBucket", err)
+	}
+	_, err = store.Object().Put(ctx, "test-bucket", "", strings.NewReader("x"), 1)
+	if !stderrors.Is(err, object.ErrInvalidObjectName) {
+		t.Errorf("Put() error = %v, want ErrInvalidObjectName", err)
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	put(t, store, "key", "0123456789")
+
+	tests := []struct {
+		start, end int64
+		want       string
+	}{
+		{2, 5, "2345"},
+		{0, 0, "0"},
+		{7, 0, "789"},
+		{8, 100, "89"},
+	}
+	for _, tt := range tests {
+		data, info := get(t, store, "key", object.WithGetRange(tt.start, tt.end))
+		if data != tt.want || info.Size != int64(len(tt.want)) {
+			t.Errorf("Get(%d-%d) = %q (size %d), want %q", tt.start, tt.end, data, info.Size, tt.want)
+		}
+	}
+
+	_, _, err := store.Object().Get(ctx, "test-bucket", "key", object.WithGetRange(10, 20))
+	if errCode(err) != string(errors.ErrInvalidRange) {
+		t.Errorf("Get() past the end error = %v, want InvalidRange", err)
+	}
+}
+
+func TestGetConditions(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	info := put(t, store, "key", "data")
+
+	withOptions := func(fn func(*object.GetOptions)) object.GetOption { return fn }
+	if _, _, err := store.Object().Get(ctx, "test-bucket", "key", withOptions(func(o *object.GetOptions) {
+		o.MatchETag = info.ETag
+	})); err != nil {
+		t.Errorf("Get(If-Match) error = %v", err)
+	}
+	_, _, err := store.Object().Get(ctx, "test-bucket", "key", withOptions(func(o *object.GetOptions) {
+		o.MatchETag = "other"
+	}))
+	if errCode(err) != string(errors.ErrCodePreconditionFailed) {
+		t.Errorf("Get(If-Match) error = %v, want PreconditionFailed", err)
+	}
+	_, _, err = store.Object().Get(ctx, "test-bucket", "key", withOptions(func(o *object.GetOptions) {
+		o.NotMatchETag = info.ETag
+	}))
+	if errCode(err) != string(errors.ErrCodeNotModified) {
+		t.Errorf("Get(If-None-Match) error = %v, want NotModified", err)
+	}
+}
+
+func TestVersioning(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	if err := store.Bucket().SetVersioning(ctx, "test-bucket", types.VersioningConfig{Status: "Enabled"}); err != nil {
+		t.Fatalf("SetVersioning() error = %v", err)
+	}
+	first := put(t, store, "key", "one")
+	second := put(t, store, "key", "two")
+	if first.VersionID == "" || first.VersionID == second.VersionID {
+		t.Fatalf("version IDs = %q, %q", first.VersionID, second.VersionID)
+	}
+
+	data, _ := get(t, store, "key", func(o *object.GetOptions) { o.VersionID = first.VersionID })
+	if data != "one" {
+		t.Errorf("Get(first version) = %q", data)
+	}
+
+	if err := store.Object().Delete(ctx, "test-bucket", "key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, _, err := store.Object().Get(ctx, "test-bucket", "key"); !errors.IsObjectNotFound(err) {
+		t.Errorf("Get() after delete error = %v, want NoSuchKey", err)
+	}
+	data, _ = get(t, store, "key", func(o *object.GetOptions) { o.VersionID = second.VersionID })
+	if data != "two" {
+		t.Errorf("Get(second version) after delete = %q", data)
+	}
+
+	var versions, markers int
+	for info := range store.Object().ListVersions(ctx, "test-bucket") {
+		if info.Err != nil {
+			t.Fatalf("ListVersions() error = %v", info.Err)
+		}
+		if info.IsDeleteMarker {
+			markers++
+			if !info.IsLatest {
+				t.Errorf("delete marker is not the latest version")
+			}
+		} else {
+			versions++
+		}
+	}
+	if versions != 2 || markers != 1 {
+		t.Errorf("ListVersions() = %d versions, %d delete markers", versions, markers)
+	}
+}
+
+func TestRetentionAndLegalHold(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := New(Config{Now: func() time.Time { return now }})
+	if err := store.Bucket().Create(ctx, "test-bucket", bucket.WithObjectLocking(true)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	info := put(t, store, "key", "data")
+	deleteVersion := func(opts ...object.DeleteOption) error {
+		opts = append(opts, func(o *object.DeleteOptions) { o.VersionID = info.VersionID })
+		return store.Object().Delete(ctx, "test-bucket", "key", opts...)
+	}
+
+	if err := store.Object().SetRetention(ctx, "test-bucket", "key", objectlock.RetentionGovernance, now.Add(time.Hour)); err != nil {
+		t.Fatalf("SetRetention() error = %v", err)
+	}
+	if err := deleteVersion(); !errors.IsAccessDenied(err) {
+		t.Errorf("Delete() under retention error = %v, want AccessDenied", err)
+	}
+
+	if err := store.Object().SetLegalHold(ctx, "test-bucket", "key", objectlock.LegalHoldOn); err != nil {
+		t.Fatalf("SetLegalHold() error = %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := deleteVersion(); !errors.IsAccessDenied(err) {
+		t.Errorf("Delete() under legal hold error = %v, want AccessDenied", err)
+	}
+
+	if err := store.Object().SetLegalHold(ctx, "test-bucket", "key", objectlock.LegalHoldOff); err != nil {
+		t.Fatalf("SetLegalHold() error = %v", err)
+	}
+	if err := deleteVersion(); err != nil {
+		t.Errorf("Delete() after retention expired error = %v", err)
+	}
+}
+
+func TestGovernanceBypass(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t, bucket.WithObjectLocking(true))
+	info := put(t, store, "key", "data")
+	until := time.Now().Add(time.Hour)
+	if err := store.Object().SetRetention(ctx, "test-bucket", "key", objectlock.RetentionGovernance, until); err != nil {
+		t.Fatalf("SetRetention() error = %v", err)
+	}
+
+	results := store.Object().DeleteMany(ctx, "test-bucket", objectsToDelete(types.ObjectToDelete{Key: "key", VersionID: info.VersionID}))
+	for result := range results {
+		if !errors.IsAccessDenied(result.Err) && result.Code != string(errors.ErrCodeAccessDenied) {
+			t.Errorf("DeleteMany() result = %+v, want AccessDenied", result)
+		}
+	}
+	results = store.Object().DeleteMany(ctx, "test-bucket", objectsToDelete(types.ObjectToDelete{Key: "key", VersionID: info.VersionID}), object.WithDeleteManyGovernanceBypass())
+	for result := range results {
+		t.Errorf("DeleteMany(bypass) result = %+v", result)
+	}
+}
+
+// objectsToDelete returns a closed channel of objects
+func objectsToDelete(objects ...types.ObjectToDelete) <-chan types.ObjectToDelete {
+	ch := make(chan types.ObjectToDelete, len(objects))
+	for _, o := range objects {
+		ch <- o
+	}
+	close(ch)
+	return ch
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	put(t, store, "src", "data", object.WithUserMetadata(map[string]string{"a": "1"}))
+
+	if _, err := store.Object().Copy(ctx, "test-bucket", "dst", "test-bucket", "src"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	data, info := get(t, store, "dst")
+	if data != "data" || info.UserMetadata["A"] != "1" {
+		t.Errorf("copied object = %q, metadata %v", data, info.UserMetadata)
+	}
+
+	if _, err := store.Object().Copy(ctx, "test-bucket", "dst", "test-bucket", "src",
+		object.WithCopyMetadata(map[string]string{"b": "2"}, true)); err != nil {
+		t.Fatalf("Copy(REPLACE) error = %v", err)
+	}
+	_, info = get(t, store, "dst")
+	if _, ok := info.UserMetadata["A"]; ok || info.UserMetadata["B"] != "2" {
+		t.Errorf("replaced metadata = %v", info.UserMetadata)
+	}
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	svc := store.Object()
+
+	uploadID, err := svc.InitiateMultipartUpload(ctx, "test-bucket", "big", object.WithContentType("application/zip"))
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload() error = %v", err)
+	}
+	first := bytes.Repeat([]byte("a"), minPartSize)
+	parts := make([]types.ObjectPart, 2)
+	for i, data := range [][]byte{first, []byte("tail")} {
+		parts[i], err = svc.UploadPart(ctx, "test-bucket", "big", uploadID, i+1, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("UploadPart(%d) error = %v", i+1, err)
+		}
+	}
+
+	listed, err := svc.ListObjectParts(ctx, "test-bucket", "big", uploadID)
+	if err != nil || len(listed.Parts) != 2 {
+		t.Fatalf("ListObjectParts() = %+v, %v", listed, err)
+	}
+
+	info, err := svc.CompleteMultipartUpload(ctx, "test-bucket", "big", uploadID, []types.ObjectPart{parts[1], parts[0]})
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload() error = %v", err)
+	}
+	if !strings.HasSuffix(info.ETag, "-2") || info.Size != int64(len(first)+4) {
+		t.Errorf("CompleteMultipartUpload() = %+v", info)
+	}
+	stat, err := svc.Stat(ctx, "test-bucket", "big")
+	if err != nil || stat.ContentType != "application/zip" {
+		t.Errorf("Stat() = %+v, %v", stat, err)
+	}
+
+	_, err = svc.UploadPart(ctx, "test-bucket", "big", uploadID, 3, strings.NewReader("x"), 1)
+	if errCode(err) != string(errors.ErrCodeNoSuchUpload) {
+		t.Errorf("UploadPart() after complete error = %v, want NoSuchUpload", err)
+	}
+}
+
+func TestCompleteRejectsSmallParts(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	svc := store.Object()
+
+	uploadID, err := svc.InitiateMultipartUpload(ctx, "test-bucket", "key")
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload() error = %v", err)
+	}
+	var parts []types.ObjectPart
+	for i := 1; i <= 2; i++ {
+		part, err := svc.UploadPart(ctx, "test-bucket", "key", uploadID, i, strings.NewReader("small"), 5)
+		if err != nil {
+			t.Fatalf("UploadPart(%d) error = %v", i, err)
+		}
+		parts = append(parts, part)
+	}
+	_, err = svc.CompleteMultipartUpload(ctx, "test-bucket", "key", uploadID, parts)
+	if errCode(err) != string(errors.ErrCodeEntityTooSmall) {
+		t.Errorf("CompleteMultipartUpload() error = %v, want EntityTooSmall", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	put(t, store, "key", "0123456789")
+
+	handle, err := store.Object().Open(ctx, "test-bucket", "key")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer handle.Close()
+	buf := make([]byte, 3)
+	if _, err := handle.ReadAt(buf, 4); err != nil {
+		t.Fatalf("ReadAt() error = %v", err)
+	}
+	if string(buf) != "456" {
+		t.Errorf("ReadAt() = %q", buf)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	store.ResetCalls()
+	put(t, store, "key", "data", object.WithContentType("text/plain"))
+	_, _ = store.Object().Stat(ctx, "test-bucket", "key")
+
+	calls := store.Calls("Object.Put")
+	if len(calls) != 1 {
+		t.Fatalf("Calls(Object.Put) = %+v", calls)
+	}
+	call := calls[0]
+	options, ok := call.Options.(object.PutOptions)
+	if call.Bucket != "test-bucket" || call.Object != "key" || !ok || options.ContentType != "text/plain" {
+		t.Errorf("Put call = %+v", call)
+	}
+	if len(call.Args) != 1 || call.Args[0] != int64(4) {
+		t.Errorf("Put call args = %v", call.Args)
+	}
+	if all := store.Calls(); len(all) != 2 || all[1].Method != "Object.Stat" {
+		t.Errorf("Calls() = %+v", all)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	put(t, store, "part1", strings.Repeat("a", minPartSize))
+	put(t, store, "part2", "tail", object.WithUserMetadata(map[string]string{"a": "1"}))
+	svc := store.Object()
+
+	dst := object.DestinationInfo{Bucket: "test-bucket", Object: "composed"}
+	info, err := svc.Compose(ctx, dst, []object.SourceInfo{
+		{Bucket: "test-bucket", Object: "part1"},
+		{Bucket: "test-bucket", Object: "part2"},
+	})
+	if err != nil {
+		t.Fatalf("Compose() error = %v", err)
+	}
+	if info.Size != minPartSize+4 || !strings.HasSuffix(info.ETag, "-2") {
+		t.Errorf("Compose() = %+v", info)
+	}
+
+	// A single whole source is a plain copy that keeps its metadata
+	if _, err := svc.Compose(ctx, dst, []object.SourceInfo{{Bucket: "test-bucket", Object: "part2"}}); err != nil {
+		t.Fatalf("Compose(single) error = %v", err)
+	}
+	data, stat := get(t, store, "composed")
+	if data != "tail" || stat.UserMetadata["A"] != "1" {
+		t.Errorf("Compose(single) = %q, metadata %v", data, stat.UserMetadata)
+	}
+
+	_, err = svc.Compose(ctx, dst, []object.SourceInfo{
+		{Bucket: "test-bucket", Object: "part2"},
+		{Bucket: "test-bucket", Object: "part1"},
+	})
+	if err == nil {
+		t.Error("Compose() with a small first source error = nil")
+	}
+}
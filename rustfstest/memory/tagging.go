@@ -0,0 +1,147 @@
+// Package memory rustfstest/memory/tagging.go
+package memory
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/acl"
+)
+
+const (
+	// maxObjectTags is the number of tags an object may carry
+	maxObjectTags = 10
+
+	allUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// SetTagging replaces the tags of the latest version of an object
+func (o *objectService) SetTagging(ctx context.Context, bucketName, objectName string, tags map[string]string) error {
+	o.store.record("Object.SetTagging", bucketName, objectName, nil, cloneMap(tags))
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if len(tags) > maxObjectTags {
+		return apiError("BadRequest", http.StatusBadRequest, "Object tags cannot be greater than 10", bucketName, objectName)
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, v, err := s.find(bucketName, objectName, "")
+	if err != nil {
+		return err
+	}
+	v.tags = cloneMap(tags)
+	return nil
+}
+
+// GetTagging returns the tags of the latest version of an object
+func (o *objectService) GetTagging(ctx context.Context, bucketName, objectName string) (map[string]string, error) {
+	o.store.record("Object.GetTagging", bucketName, objectName, nil)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return nil, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, v, err := s.find(bucketName, objectName, "")
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(v.tags))
+	for k, value := range v.tags {
+		tags[k] = value
+	}
+	return tags, nil
+}
+
+// DeleteTagging removes the tags of the latest version of an object
+func (o *objectService) DeleteTagging(ctx context.Context, bucketName, objectName string) error {
+	o.store.record("Object.DeleteTagging", bucketName, objectName, nil)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, v, err := s.find(bucketName, objectName, "")
+	if err != nil {
+		return err
+	}
+	v.tags = nil
+	return nil
+}
+
+// GetACL returns the ACL of the latest version of an object; objects are
+// private to the store owner until SetACL
+func (o *objectService) GetACL(ctx context.Context, bucketName, objectName string) (acl.ACL, error) {
+	o.store.record("Object.GetACL", bucketName, objectName, nil)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return acl.ACL{}, err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, v, err := s.find(bucketName, objectName, "")
+	if err != nil {
+		return acl.ACL{}, err
+	}
+	return expandACL(v.acl), nil
+}
+
+// SetACL replaces the ACL of the latest version of an object
+func (o *objectService) SetACL(ctx context.Context, bucketName, objectName string, policy acl.ACL) error {
+	o.store.record("Object.SetACL", bucketName, objectName, nil, policy)
+	if err := validateNames(bucketName, objectName); err != nil {
+		return err
+	}
+	if err := policy.Normalize(); err != nil {
+		return err
+	}
+
+	s := o.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, v, err := s.find(bucketName, objectName, "")
+	if err != nil {
+		return err
+	}
+	v.acl = &policy
+	return nil
+}
+
+// expandACL returns a stored ACL as GetACL reads it back: canned ACLs and
+// a missing ACL become the grants they stand for
+func expandACL(stored *acl.ACL) acl.ACL {
+	if stored != nil && stored.Canned == "" {
+		return *stored
+	}
+	owner := acl.Owner{ID: OwnerID, DisplayName: OwnerID}
+	policy := acl.ACL{
+		Owner: owner,
+		Grants: []acl.Grant{{
+			Grantee:    acl.Grantee{Type: "CanonicalUser", ID: owner.ID, DisplayName: owner.DisplayName},
+			Permission: acl.PermissionFullControl,
+		}},
+	}
+	if stored != nil {
+		group := func(uri string, permission acl.Permission) acl.Grant {
+			return acl.Grant{Grantee: acl.Grantee{Type: "Group", URI: uri}, Permission: permission}
+		}
+		switch stored.Canned {
+		case acl.ACLPublicRead:
+			policy.Grants = append(policy.Grants, group(allUsersURI, acl.PermissionRead))
+		case acl.ACLPublicReadWrite:
+			policy.Grants = append(policy.Grants, group(allUsersURI, acl.PermissionRead), group(allUsersURI, acl.PermissionWrite))
+		case acl.ACLAuthenticatedRead:
+			policy.Grants = append(policy.Grants, group(authenticatedUsersURI, acl.PermissionRead))
+		}
+	}
+	_ = policy.Normalize()
+	return policy
+}
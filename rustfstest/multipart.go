@@ -0,0 +1,420 @@
+// Package rustfstest rustfstest/multipart.go
+package rustfstest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+)
+
+const (
+	// minPartSize is the smallest size of a part other than the last
+	minPartSize = 5 << 20
+	// maxPartNumber is the highest part number of an upload
+	maxPartNumber = 10000
+)
+
+// upload is an in-progress multipart upload
+type upload struct {
+	id        string
+	key       string
+	initiated time.Time
+	header    http.Header
+	tags      map[string]string
+	parts     map[int]*part
+}
+
+// part is an uploaded part of a multipart upload
+type part struct {
+	number   int
+	data     []byte
+	etag     string
+	modified time.Time
+}
+
+// upload returns the upload with id of key
+func (b *bucket) upload(id, key string) (*upload, error) {
+	u, ok := b.uploads[id]
+	if !ok || u.key != key {
+		return nil, errNoSuchUpload
+	}
+	return u, nil
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (s *Server) initiateUpload(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	tags, err := objectTags(r.Header)
+	if err != nil {
+		return err
+	}
+	u := &upload{
+		id:        newID(),
+		key:       r.key,
+		initiated: s.now(),
+		header:    objectHeader(r.Header),
+		tags:      tags,
+		parts:     make(map[int]*part),
+	}
+	b.uploads[u.id] = u
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{Xmlns: s3Namespace, Bucket: b.name, Key: u.key, UploadID: u.id})
+	return nil
+}
+
+// partNumber parses the partNumber query parameter
+func partNumber(r *request) (int, error) {
+	n, err := strconv.Atoi(r.query.Get("partNumber"))
+	if err != nil || n < 1 || n > maxPartNumber {
+		return 0, errorf(http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and %d, inclusive.", maxPartNumber)
+	}
+	return n, nil
+}
+
+// uploadPart stores a part from the request body, or copies it from
+// x-amz-copy-source
+func (s *Server) uploadPart(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	number, err := partNumber(r)
+	if err != nil {
+		return err
+	}
+	u, err := b.upload(r.query.Get("uploadId"), r.key)
+	if err != nil {
+		return err
+	}
+	if r.Header.Get("x-amz-copy-source") != "" {
+		return s.uploadPartCopy(w, r, u, number)
+	}
+
+	p := &part{number: number, data: r.body, etag: md5Hex(r.body), modified: s.now()}
+	for name, values := range r.Header {
+		if _, ok := checksum.FromHeaderKey(name); ok {
+			w.Header()[name] = values
+		}
+	}
+	u.parts[number] = p
+	w.Header().Set("ETag", `"`+p.etag+`"`)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+type copyPartResult struct {
+	XMLName      xml.Name `xml:"CopyPartResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+func (s *Server) uploadPartCopy(w http.ResponseWriter, r *request, u *upload, number int) error {
+	src, err := s.copySource(r)
+	if err != nil {
+		return err
+	}
+	data := src.data
+	if spec := r.Header.Get("x-amz-copy-source-range"); spec != "" {
+		start, end, err := parseRange(spec, int64(len(src.data)))
+		if err != nil {
+			return errorf(http.StatusBadRequest, "InvalidArgument", "The x-amz-copy-source-range value %q is not valid for an object of %d bytes.", spec, len(src.data))
+		}
+		data = src.data[start : end+1]
+	}
+
+	p := &part{number: number, data: data, etag: md5Hex(data), modified: s.now()}
+	u.parts[number] = p
+	if src.versionID != "" {
+		w.Header().Set("x-amz-copy-source-version-id", src.versionID)
+	}
+	writeXML(w, http.StatusOK, copyPartResult{Xmlns: s3Namespace, LastModified: iso8601(p.modified), ETag: `"` + p.etag + `"`})
+	return nil
+}
+
+type listPartsResult struct {
+	XMLName              xml.Name    `xml:"ListPartsResult"`
+	Xmlns                string      `xml:"xmlns,attr"`
+	Bucket               string      `xml:"Bucket"`
+	Key                  string      `xml:"Key"`
+	UploadID             string      `xml:"UploadId"`
+	Initiator            owner       `xml:"Initiator"`
+	Owner                owner       `xml:"Owner"`
+	StorageClass         string      `xml:"StorageClass"`
+	PartNumberMarker     int         `xml:"PartNumberMarker"`
+	NextPartNumberMarker int         `xml:"NextPartNumberMarker"`
+	MaxParts             int         `xml:"MaxParts"`
+	IsTruncated          bool        `xml:"IsTruncated"`
+	Parts                []partEntry `xml:"Part"`
+}
+
+type partEntry struct {
+	PartNumber   int    `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+}
+
+func (s *Server) listParts(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	u, err := b.upload(r.query.Get("uploadId"), r.key)
+	if err != nil {
+		return err
+	}
+	marker, _ := strconv.Atoi(r.query.Get("part-number-marker"))
+	maxParts, err := maxEntries(r, "max-parts")
+	if err != nil {
+		return err
+	}
+
+	result := listPartsResult{
+		Xmlns:            s3Namespace,
+		Bucket:           b.name,
+		Key:              u.key,
+		UploadID:         u.id,
+		Initiator:        s.owner(),
+		Owner:            s.owner(),
+		StorageClass:     storageClass(u.header),
+		PartNumberMarker: marker,
+		MaxParts:         maxParts,
+	}
+	page := pager{max: maxParts}
+	for _, number := range sortedParts(u) {
+		p := u.parts[number]
+		if number <= marker {
+			continue
+		}
+		if !page.add() {
+			break
+		}
+		result.Parts = append(result.Parts, partEntry{PartNumber: number, LastModified: iso8601(p.modified), ETag: `"` + p.etag + `"`, Size: len(p.data)})
+		result.NextPartNumberMarker = number
+	}
+	result.IsTruncated = page.truncated
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// sortedParts returns the part numbers of u in ascending order
+func sortedParts(u *upload) []int {
+	numbers := make([]int, 0, len(u.parts))
+	for number := range u.parts {
+		numbers = append(numbers, number)
+	}
+	slices.Sort(numbers)
+	return numbers
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// completeUpload assembles the listed parts into an object. Its ETag is the
+// MD5 of the part MD5s followed by the number of parts.
+func (s *Server) completeUpload(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	u, err := b.upload(r.query.Get("uploadId"), r.key)
+	if err != nil {
+		return err
+	}
+	var req completeMultipartUpload
+	if err := decodeXML(r.body, &req); err != nil {
+		return err
+	}
+	if len(req.Parts) == 0 {
+		return errMalformedXML
+	}
+
+	var data, sums []byte
+	for i, listed := range req.Parts {
+		if i > 0 && listed.PartNumber <= req.Parts[i-1].PartNumber {
+			return errorf(http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order. The parts list must be specified in order by part number.")
+		}
+		p, ok := u.parts[listed.PartNumber]
+		if !ok || strings.Trim(listed.ETag, `"`) != p.etag {
+			return errorf(http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found. The part may not have been uploaded, or the specified entity tag may not match the part's entity tag.")
+		}
+		if i < len(req.Parts)-1 && len(p.data) < minPartSize {
+			return errorf(http.StatusBadRequest, "EntityTooSmall", "Your proposed upload is smaller than the minimum allowed object size.")
+		}
+		data = append(data, p.data...)
+		sum, _ := hex.DecodeString(p.etag)
+		sums = append(sums, sum...)
+	}
+	sum := md5.Sum(sums)
+
+	obj := &object{
+		key:      u.key,
+		data:     data,
+		etag:     hex.EncodeToString(sum[:]) + "-" + strconv.Itoa(len(req.Parts)),
+		modified: s.now(),
+		header:   u.header,
+		tags:     u.tags,
+	}
+	b.put(obj)
+	delete(b.uploads, u.id)
+
+	setVersionHeaders(w, obj)
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Xmlns:    s3Namespace,
+		Location: s.URL + "/" + b.name + "/" + obj.key,
+		Bucket:   b.name,
+		Key:      obj.key,
+		ETag:     `"` + obj.etag + `"`,
+	})
+	return nil
+}
+
+func (s *Server) abortUpload(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	u, err := b.upload(r.query.Get("uploadId"), r.key)
+	if err != nil {
+		return err
+	}
+	delete(b.uploads, u.id)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type listMultipartUploadsResult struct {
+	XMLName            xml.Name       `xml:"ListMultipartUploadsResult"`
+	Xmlns              string         `xml:"xmlns,attr"`
+	Bucket             string         `xml:"Bucket"`
+	KeyMarker          string         `xml:"KeyMarker"`
+	UploadIDMarker     string         `xml:"UploadIdMarker"`
+	NextKeyMarker      string         `xml:"NextKeyMarker"`
+	NextUploadIDMarker string         `xml:"NextUploadIdMarker"`
+	Prefix             string         `xml:"Prefix"`
+	Delimiter          string         `xml:"Delimiter,omitempty"`
+	MaxUploads         int            `xml:"MaxUploads"`
+	IsTruncated        bool           `xml:"IsTruncated"`
+	Uploads            []uploadEntry  `xml:"Upload"`
+	CommonPrefixes     []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type uploadEntry struct {
+	Key          string `xml:"Key"`
+	UploadID     string `xml:"UploadId"`
+	Initiator    owner  `xml:"Initiator"`
+	Owner        owner  `xml:"Owner"`
+	StorageClass string `xml:"StorageClass"`
+	Initiated    string `xml:"Initiated"`
+}
+
+// listUploads lists the in-progress uploads ordered by key and initiation
+func (s *Server) listUploads(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	q := r.query
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	keyMarker, idMarker := q.Get("key-marker"), q.Get("upload-id-marker")
+	maxUploads, err := maxEntries(r, "max-uploads")
+	if err != nil {
+		return err
+	}
+
+	uploads := make([]*upload, 0, len(b.uploads))
+	for _, u := range b.uploads {
+		uploads = append(uploads, u)
+	}
+	slices.SortFunc(uploads, func(a, b *upload) int {
+		if c := strings.Compare(a.key, b.key); c != 0 {
+			return c
+		}
+		if c := a.initiated.Compare(b.initiated); c != 0 {
+			return c
+		}
+		return strings.Compare(a.id, b.id)
+	})
+
+	result := listMultipartUploadsResult{
+		Xmlns:          s3Namespace,
+		Bucket:         b.name,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: idMarker,
+		Prefix:         prefix,
+		Delimiter:      delimiter,
+		MaxUploads:     maxUploads,
+	}
+	// An upload ID marker resumes within the uploads of the key marker
+	skipping := idMarker != ""
+	page := pager{max: maxUploads}
+	var last string
+	for _, u := range uploads {
+		switch {
+		case !strings.HasPrefix(u.key, prefix), u.key < keyMarker:
+			continue
+		case u.key == keyMarker && idMarker == "":
+			continue
+		case u.key == keyMarker && skipping:
+			skipping = u.id != idMarker
+			continue
+		}
+		if cp := commonPrefixOf(u.key, prefix, delimiter); cp != "" {
+			if cp == last || strings.HasPrefix(keyMarker, cp) {
+				continue
+			}
+			if !page.add() {
+				break
+			}
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: cp})
+			result.NextKeyMarker, result.NextUploadIDMarker, last = cp, "", cp
+			continue
+		}
+		if !page.add() {
+			break
+		}
+		result.Uploads = append(result.Uploads, uploadEntry{
+			Key:          u.key,
+			UploadID:     u.id,
+			Initiator:    s.owner(),
+			Owner:        s.owner(),
+			StorageClass: storageClass(u.header),
+			Initiated:    iso8601(u.initiated),
+		})
+		result.NextKeyMarker, result.NextUploadIDMarker, last = u.key, u.id, u.key
+	}
+	result.IsTruncated = page.truncated
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
@@ -0,0 +1,597 @@
+// Package rustfstest rustfstest/object.go
+package rustfstest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scorpio69t/rustfs-go/pkg/checksum"
+)
+
+// serveObject dispatches an object request
+func (s *Server) serveObject(w http.ResponseWriter, r *request) error {
+	q := r.query
+	if hasUnsupported(withoutFlags(q, "tagging", "uploads")) {
+		return errNotImplemented
+	}
+	switch r.Method {
+	case http.MethodGet:
+		switch {
+		case q.Has("uploadId"):
+			return s.listParts(w, r)
+		case q.Has("tagging"):
+			return s.getObjectTagging(w, r)
+		}
+		return s.getObject(w, r)
+	case http.MethodHead:
+		return s.getObject(w, r)
+	case http.MethodPut:
+		switch {
+		case q.Has("uploadId"):
+			return s.uploadPart(w, r)
+		case q.Has("tagging"):
+			return s.putObjectTagging(w, r)
+		case r.Header.Get("x-amz-copy-source") != "":
+			return s.copyObject(w, r)
+		case r.Header.Get("x-amz-write-offset-bytes") != "":
+			return s.appendObject(w, r)
+		}
+		return s.putObject(w, r)
+	case http.MethodPost:
+		switch {
+		case q.Has("uploads"):
+			return s.initiateUpload(w, r)
+		case q.Has("uploadId"):
+			return s.completeUpload(w, r)
+		}
+		return errNotImplemented
+	case http.MethodDelete:
+		switch {
+		case q.Has("uploadId"):
+			return s.abortUpload(w, r)
+		case q.Has("tagging"):
+			return s.deleteObjectTagging(w, r)
+		}
+		return s.deleteObject(w, r)
+	}
+	return errMethodNotAllowed
+}
+
+// withoutFlags returns q without the named parameters
+func withoutFlags(q url.Values, names ...string) url.Values {
+	rest := make(url.Values, len(q))
+	for name, values := range q {
+		rest[name] = values
+	}
+	for _, name := range names {
+		delete(rest, name)
+	}
+	return rest
+}
+
+// storedHeaders are the request headers kept with an object
+var storedHeaders = []string{
+	"Content-Type", "Content-Disposition", "Content-Language",
+	"Cache-Control", "Expires", "X-Amz-Storage-Class",
+}
+
+// objectHeader returns the content headers, user metadata and checksums of
+// an upload request
+func objectHeader(h http.Header) http.Header {
+	header := make(http.Header)
+	for _, name := range storedHeaders {
+		if value := h.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "binary/octet-stream")
+	}
+
+	// aws-chunked only describes the transfer
+	var encodings []string
+	for _, encoding := range strings.Split(h.Get("Content-Encoding"), ",") {
+		if encoding = strings.TrimSpace(encoding); encoding != "" && encoding != "aws-chunked" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	if len(encodings) > 0 {
+		header.Set("Content-Encoding", strings.Join(encodings, ","))
+	}
+
+	for name, values := range h {
+		if _, ok := checksum.FromHeaderKey(name); ok || strings.HasPrefix(name, "X-Amz-Meta-") {
+			header[name] = values
+		}
+	}
+	return header
+}
+
+// objectTags parses the x-amz-tagging header of an upload request
+func objectTags(h http.Header) (map[string]string, error) {
+	values, err := url.ParseQuery(h.Get("x-amz-tagging"))
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "InvalidTag", "The x-amz-tagging header is not a valid query string.")
+	}
+	if len(values) > maxObjectTags {
+		return nil, errorf(http.StatusBadRequest, "InvalidTag", "Object tags cannot be greater than %d.", maxObjectTags)
+	}
+	tags := make(map[string]string, len(values))
+	for key := range values {
+		tags[key] = values.Get(key)
+	}
+	return tags, nil
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// setVersionHeaders sets the version headers of a response about obj
+func setVersionHeaders(w http.ResponseWriter, obj *object) {
+	if obj.versionID != "" {
+		w.Header().Set("x-amz-version-id", obj.versionID)
+	}
+	if obj.deleteMarker {
+		w.Header().Set("x-amz-delete-marker", "true")
+	}
+}
+
+// setChecksumHeaders echoes the checksums stored with obj
+func setChecksumHeaders(w http.ResponseWriter, obj *object) {
+	for name, values := range obj.header {
+		if _, ok := checksum.FromHeaderKey(name); ok {
+			w.Header()[name] = values
+		}
+	}
+}
+
+// checkWritePreconditions applies If-Match and If-None-Match to an upload
+// replacing current
+func checkWritePreconditions(h http.Header, current *object) error {
+	exists := current != nil && !current.deleteMarker
+	if match := h.Get("If-Match"); match != "" && (!exists || !etagMatches(match, current.etag)) {
+		return errPreconditionFailed
+	}
+	if h.Get("If-None-Match") == "*" && exists {
+		return errPreconditionFailed
+	}
+	return nil
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	if err := checkWritePreconditions(r.Header, b.latest(r.key)); err != nil {
+		return err
+	}
+	tags, err := objectTags(r.Header)
+	if err != nil {
+		return err
+	}
+
+	obj := &object{
+		key:      r.key,
+		data:     r.body,
+		etag:     md5Hex(r.body),
+		modified: s.now(),
+		header:   objectHeader(r.Header),
+		tags:     tags,
+	}
+	b.put(obj)
+
+	w.Header().Set("ETag", `"`+obj.etag+`"`)
+	setVersionHeaders(w, obj)
+	setChecksumHeaders(w, obj)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// appendObject appends the body to an object at x-amz-write-offset-bytes,
+// which must be the current size of the object
+func (s *Server) appendObject(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("x-amz-write-offset-bytes"), 10, 64)
+	if err != nil || offset < 0 {
+		return errorf(http.StatusBadRequest, "InvalidArgument", "Invalid x-amz-write-offset-bytes.")
+	}
+
+	obj := &object{key: r.key, modified: s.now(), header: objectHeader(r.Header)}
+	current := b.latest(r.key)
+	switch {
+	case current == nil || current.deleteMarker:
+		if offset != 0 {
+			return errNoSuchKey
+		}
+		if obj.tags, err = objectTags(r.Header); err != nil {
+			return err
+		}
+	case offset != int64(len(current.data)):
+		return errorf(http.StatusBadRequest, "InvalidWriteOffset", "The write offset %d does not match the object size %d.", offset, len(current.data))
+	default:
+		obj.header, obj.tags = current.header, current.tags
+		obj.data = append(obj.data, current.data...)
+	}
+	obj.data = append(obj.data, r.body...)
+	obj.etag = md5Hex(obj.data)
+	b.put(obj)
+
+	w.Header().Set("ETag", `"`+obj.etag+`"`)
+	w.Header().Set("x-amz-object-size", strconv.Itoa(len(obj.data)))
+	setVersionHeaders(w, obj)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// lookup returns the requested version of an object, or the latest
+func (s *Server) lookup(w http.ResponseWriter, b *bucket, key, versionID string) (*object, error) {
+	if versionID != "" {
+		obj := b.version(key, versionID)
+		switch {
+		case obj == nil:
+			return nil, errNoSuchVersion
+		case obj.deleteMarker:
+			setVersionHeaders(w, obj)
+			w.Header().Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+			return nil, errMethodNotAllowed
+		}
+		return obj, nil
+	}
+	obj := b.latest(key)
+	if obj == nil {
+		return nil, errNoSuchKey
+	}
+	if obj.deleteMarker {
+		setVersionHeaders(w, obj)
+		return nil, errNoSuchKey
+	}
+	return obj, nil
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	obj, err := s.lookup(w, b, r.key, r.query.Get("versionId"))
+	if err != nil {
+		return err
+	}
+
+	header := w.Header()
+	for name, values := range obj.header {
+		if _, ok := checksum.FromHeaderKey(name); !ok {
+			header[name] = values
+		}
+	}
+	if strings.EqualFold(r.Header.Get("x-amz-checksum-mode"), "ENABLED") {
+		setChecksumHeaders(w, obj)
+	}
+	header.Set("ETag", `"`+obj.etag+`"`)
+	header.Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+	header.Set("Accept-Ranges", "bytes")
+	setVersionHeaders(w, obj)
+	if len(obj.tags) > 0 {
+		header.Set("x-amz-tagging-count", strconv.Itoa(len(obj.tags)))
+	}
+	if err := checkReadPreconditions(r.Header, obj); err != nil {
+		return err
+	}
+	if !r.anonymous {
+		for name, values := range r.query {
+			if override, ok := strings.CutPrefix(name, "response-"); ok {
+				header.Set(override, values[0])
+			}
+		}
+	}
+
+	data, status := obj.data, http.StatusOK
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+		start, end, err := parseRange(rangeHeader, int64(len(obj.data)))
+		if err != nil {
+			header.Set("Content-Range", "bytes */"+strconv.Itoa(len(obj.data)))
+			return err
+		}
+		data, status = obj.data[start:end+1], http.StatusPartialContent
+		header.Set("Content-Range", "bytes "+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10)+"/"+strconv.Itoa(len(obj.data)))
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+	return nil
+}
+
+// checkReadPreconditions applies the conditional headers of a GET or HEAD
+func checkReadPreconditions(h http.Header, obj *object) error {
+	match, noneMatch := h.Get("If-Match"), h.Get("If-None-Match")
+	if match != "" && !etagMatches(match, obj.etag) {
+		return errPreconditionFailed
+	}
+	if since, err := http.ParseTime(h.Get("If-Unmodified-Since")); match == "" && err == nil && obj.modified.Truncate(time.Second).After(since) {
+		return errPreconditionFailed
+	}
+	if noneMatch != "" && etagMatches(noneMatch, obj.etag) {
+		return errNotModified
+	}
+	if since, err := http.ParseTime(h.Get("If-Modified-Since")); noneMatch == "" && err == nil && !obj.modified.Truncate(time.Second).After(since) {
+		return errNotModified
+	}
+	return nil
+}
+
+// etagMatches reports whether a list of ETags, or "*", matches etag
+func etagMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.Trim(strings.TrimSpace(candidate), `"`)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRange parses a single byte range of an object of size bytes,
+// returning its inclusive bounds
+func parseRange(header string, size int64) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	first, last, found := strings.Cut(spec, "-")
+	if !ok || !found || strings.Contains(spec, ",") {
+		return 0, 0, errorf(http.StatusBadRequest, "InvalidArgument", "Invalid Range header %q.", header)
+	}
+	if first == "" {
+		// Suffix range: the last n bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, errInvalidRange
+		}
+		return max(size-n, 0), size - 1, nil
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, errInvalidRange
+	}
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, errInvalidRange
+		}
+		end = min(end, size-1)
+	}
+	return start, end, nil
+}
+
+// deleteVersion deletes an object, or one of its versions, returning the
+// deleted version or the delete marker created
+func (b *bucket) deleteVersion(key, versionID string, now time.Time) *object {
+	if versionID != "" {
+		return b.remove(key, versionID)
+	}
+	if b.versioning == "" {
+		b.remove(key, "")
+		return nil
+	}
+	marker := &object{key: key, deleteMarker: true, modified: now}
+	b.put(marker)
+	return marker
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	if deleted := b.deleteVersion(r.key, r.query.Get("versionId"), s.now()); deleted != nil {
+		setVersionHeaders(w, deleted)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+type deleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool     `xml:"Quiet"`
+	Objects []struct {
+		Key       string `xml:"Key"`
+		VersionID string `xml:"VersionId"`
+	} `xml:"Object"`
+}
+
+type deleteResult struct {
+	XMLName xml.Name        `xml:"DeleteResult"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Deleted []deletedObject `xml:"Deleted"`
+}
+
+type deletedObject struct {
+	Key                   string `xml:"Key"`
+	VersionID             string `xml:"VersionId,omitempty"`
+	DeleteMarker          bool   `xml:"DeleteMarker,omitempty"`
+	DeleteMarkerVersionID string `xml:"DeleteMarkerVersionId,omitempty"`
+}
+
+func (s *Server) deleteObjects(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	var req deleteRequest
+	if err := decodeXML(r.body, &req); err != nil {
+		return err
+	}
+	if len(req.Objects) == 0 || len(req.Objects) > 1000 {
+		return errMalformedXML
+	}
+
+	result := deleteResult{Xmlns: s3Namespace}
+	for _, o := range req.Objects {
+		entry := deletedObject{Key: o.Key, VersionID: o.VersionID}
+		if deleted := b.deleteVersion(o.Key, o.VersionID, s.now()); deleted != nil && deleted.deleteMarker {
+			entry.DeleteMarker = true
+			if o.VersionID == "" {
+				entry.DeleteMarkerVersionID = deleted.versionID
+			}
+		}
+		if !req.Quiet {
+			result.Deleted = append(result.Deleted, entry)
+		}
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// copySource returns the object named by the x-amz-copy-source header,
+// after applying the x-amz-copy-source-if-* conditions
+func (s *Server) copySource(r *request) (*object, error) {
+	source := r.Header.Get("x-amz-copy-source")
+	source, versionID, _ := strings.Cut(source, "?versionId=")
+	source, err := url.PathUnescape(strings.TrimPrefix(source, "/"))
+	srcBucket, srcKey, ok := strings.Cut(source, "/")
+	if err != nil || !ok || srcKey == "" {
+		return nil, errorf(http.StatusBadRequest, "InvalidArgument", "Copy Source must mention the source bucket and key: sourcebucket/sourcekey.")
+	}
+
+	b, err := s.bucket(srcBucket)
+	if err != nil {
+		return nil, err
+	}
+	obj := b.latest(srcKey)
+	if versionID != "" {
+		obj = b.version(srcKey, versionID)
+	}
+	if obj == nil || obj.deleteMarker {
+		if versionID != "" {
+			return nil, errNoSuchVersion
+		}
+		return nil, errNoSuchKey
+	}
+
+	h := r.Header
+	if match := h.Get("x-amz-copy-source-if-match"); match != "" && !etagMatches(match, obj.etag) {
+		return nil, errPreconditionFailed
+	}
+	if noneMatch := h.Get("x-amz-copy-source-if-none-match"); noneMatch != "" && etagMatches(noneMatch, obj.etag) {
+		return nil, errPreconditionFailed
+	}
+	modified := obj.modified.Truncate(time.Second)
+	if since, err := http.ParseTime(h.Get("x-amz-copy-source-if-unmodified-since")); err == nil && modified.After(since) {
+		return nil, errPreconditionFailed
+	}
+	if since, err := http.ParseTime(h.Get("x-amz-copy-source-if-modified-since")); err == nil && !modified.After(since) {
+		return nil, errPreconditionFailed
+	}
+	return obj, nil
+}
+
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+func (s *Server) copyObject(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	src, err := s.copySource(r)
+	if err != nil {
+		return err
+	}
+
+	obj := &object{
+		key:      r.key,
+		data:     src.data,
+		etag:     src.etag,
+		modified: s.now(),
+		header:   src.header,
+		tags:     src.tags,
+	}
+	replaceMetadata := strings.EqualFold(r.Header.Get("x-amz-metadata-directive"), "REPLACE")
+	if replaceMetadata {
+		obj.header = objectHeader(r.Header)
+		for name, values := range src.header {
+			if _, ok := checksum.FromHeaderKey(name); ok {
+				obj.header[name] = values
+			}
+		}
+	} else if src == b.latest(r.key) && r.Header.Get("x-amz-storage-class") == "" {
+		return errorf(http.StatusBadRequest, "InvalidRequest", "This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata, storage class, website redirect location or encryption attributes.")
+	}
+	if strings.EqualFold(r.Header.Get("x-amz-tagging-directive"), "REPLACE") {
+		if obj.tags, err = objectTags(r.Header); err != nil {
+			return err
+		}
+	}
+	b.put(obj)
+
+	if src.versionID != "" {
+		w.Header().Set("x-amz-copy-source-version-id", src.versionID)
+	}
+	setVersionHeaders(w, obj)
+	writeXML(w, http.StatusOK, copyObjectResult{Xmlns: s3Namespace, LastModified: iso8601(obj.modified), ETag: `"` + obj.etag + `"`})
+	return nil
+}
+
+func (s *Server) putObjectTagging(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	obj, err := s.lookup(w, b, r.key, r.query.Get("versionId"))
+	if err != nil {
+		return err
+	}
+	tags, err := decodeTagging(r.body, maxObjectTags)
+	if err != nil {
+		return err
+	}
+	obj.tags = tags
+	setVersionHeaders(w, obj)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func (s *Server) getObjectTagging(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	obj, err := s.lookup(w, b, r.key, r.query.Get("versionId"))
+	if err != nil {
+		return err
+	}
+	setVersionHeaders(w, obj)
+	writeXML(w, http.StatusOK, encodeTagging(obj.tags))
+	return nil
+}
+
+func (s *Server) deleteObjectTagging(w http.ResponseWriter, r *request) error {
+	b, err := s.bucket(r.bucket)
+	if err != nil {
+		return err
+	}
+	obj, err := s.lookup(w, b, r.key, r.query.Get("versionId"))
+	if err != nil {
+		return err
+	}
+	obj.tags = nil
+	setVersionHeaders(w, obj)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
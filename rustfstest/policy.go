@@ -0,0 +1,175 @@
+// Package rustfstest rustfstest/policy.go
+package rustfstest
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// policyDocument is a bucket policy. Only statements granting to everyone
+// ("*") and without conditions are evaluated.
+type policyDocument struct {
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect    string          `json:"Effect"`
+	Principal json.RawMessage `json:"Principal"`
+	Action    stringList      `json:"Action"`
+	Resource  stringList      `json:"Resource"`
+	Condition json.RawMessage `json:"Condition,omitempty"`
+}
+
+// stringList is a policy element that is a string or an array of strings
+type stringList []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// public reports whether the statement applies to anonymous requests
+func (st policyStatement) public() bool {
+	if len(st.Condition) > 0 {
+		return false
+	}
+	var principal struct {
+		AWS stringList `json:"AWS"`
+	}
+	var everyone string
+	if json.Unmarshal(st.Principal, &everyone) == nil {
+		return everyone == "*"
+	}
+	if json.Unmarshal(st.Principal, &principal) != nil {
+		return false
+	}
+	for _, p := range principal.AWS {
+		if p == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether value matches one of the wildcard patterns
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches value against a pattern where * matches any
+// sequence of characters, including "/", and ? matches any one character
+func wildcardMatch(pattern, value string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(value); i >= 0; i-- {
+				if wildcardMatch(pattern[1:], value[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if value == "" {
+				return false
+			}
+		default:
+			if value == "" || value[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, value = pattern[1:], value[1:]
+	}
+	return value == ""
+}
+
+// anonymousAction returns the policy action and resource of a request
+func anonymousAction(r *request) (string, string) {
+	q := r.query
+	if r.key == "" {
+		resource := "arn:aws:s3:::" + r.bucket
+		switch {
+		case r.Method == http.MethodGet && q.Has("uploads"):
+			return "s3:ListBucketMultipartUploads", resource
+		case r.Method == http.MethodGet && q.Has("versions"):
+			return "s3:ListBucketVersions", resource
+		case r.Method == http.MethodGet && q.Has("location"):
+			return "s3:GetBucketLocation", resource
+		case r.Method == http.MethodGet && !hasUnsupported(q), r.Method == http.MethodHead:
+			return "s3:ListBucket", resource
+		}
+		return "", ""
+	}
+
+	resource := "arn:aws:s3:::" + r.bucket + "/" + r.key
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case q.Has("uploadId"):
+			return "s3:ListMultipartUploadParts", resource
+		case q.Has("tagging"):
+			return "s3:GetObjectTagging", resource
+		case q.Has("versionId"):
+			return "s3:GetObjectVersion", resource
+		}
+		return "s3:GetObject", resource
+	case http.MethodPut:
+		if q.Has("tagging") {
+			return "s3:PutObjectTagging", resource
+		}
+		return "s3:PutObject", resource
+	case http.MethodPost:
+		return "s3:PutObject", resource
+	case http.MethodDelete:
+		if q.Has("uploadId") {
+			return "s3:AbortMultipartUpload", resource
+		}
+		return "s3:DeleteObject", resource
+	}
+	return "", ""
+}
+
+// allowAnonymous reports whether the bucket policy lets an unsigned request
+// through. Deny statements take precedence over Allow statements.
+func (s *Server) allowAnonymous(r *request) bool {
+	b, ok := s.buckets[r.bucket]
+	if !ok || b.policy == nil {
+		return false
+	}
+	action, resource := anonymousAction(r)
+	if action == "" {
+		return false
+	}
+	var doc policyDocument
+	if err := json.Unmarshal(b.policy, &doc); err != nil {
+		return false
+	}
+
+	allowed := false
+	for _, st := range doc.Statement {
+		if !st.public() || !matchesAny(st.Action, action) || !matchesAny(st.Resource, resource) {
+			continue
+		}
+		switch st.Effect {
+		case "Deny":
+			return false
+		case "Allow":
+			allowed = true
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,290 @@
+// Package rustfstest rustfstest/server.go
+package rustfstest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rustfs "github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/pkg/credentials"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+const (
+	// DefaultAccessKey is the access key of a server without one configured
+	DefaultAccessKey = "rustfstest"
+	// DefaultSecretKey is the secret key of a server without one configured
+	DefaultSecretKey = "rustfstest-secret-key"
+	// DefaultRegion is the region of a server without one configured
+	DefaultRegion = "us-east-1"
+
+	// maxClockSkew is how far the signing time may be from the server clock
+	maxClockSkew = 15 * time.Minute
+)
+
+// Config configures a Server. Zero values select the defaults.
+type Config struct {
+	// AccessKey and SecretKey are the only credentials accepted
+	AccessKey string
+	SecretKey string
+	// Region is the region of the server and of all its buckets
+	Region string
+	// Now returns the server clock, e.g. to test clock skew
+	// Default: time.Now
+	Now func() time.Time
+}
+
+// Server is an in-memory S3-compatible server for tests. It keeps buckets,
+// object versions, multipart uploads and tags in memory, verifies SigV4
+// header, presigned and streaming signatures, and lets anonymous requests
+// through when the bucket policy allows them.
+//
+// Buckets are addressed path-style. Operations it does not implement, such
+// as lifecycle, replication or S3 Select, fail with NotImplemented.
+type Server struct {
+	// URL is the base URL of the server, e.g. http://127.0.0.1:50000
+	URL string
+
+	config Config
+	server *httptest.Server
+	ids    atomic.Int64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewServer starts a server, which must be closed with Close
+func NewServer(config Config) *Server {
+	if config.AccessKey == "" {
+		config.AccessKey = DefaultAccessKey
+	}
+	if config.SecretKey == "" {
+		config.SecretKey = DefaultSecretKey
+	}
+	if config.Region == "" {
+		config.Region = DefaultRegion
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	s := &Server{config: config, buckets: make(map[string]*bucket)}
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// NewClient starts a server that is closed when the test ends and returns
+// a client for it
+//
+// Example:
+//
+//	client := rustfstest.NewClient(t)
+//	err := client.Bucket().Create(ctx, "bucket")
+func NewClient(t testing.TB) *rustfs.Client {
+	t.Helper()
+	s := NewServer(Config{})
+	t.Cleanup(s.Close)
+	return s.NewClient(t)
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Endpoint returns the host:port to pass to rustfs.New
+func (s *Server) Endpoint() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// Options returns client options with the server's credentials and region,
+// to be adjusted before calling rustfs.New with Endpoint
+func (s *Server) Options() *rustfs.Options {
+	return &rustfs.Options{
+		Credentials:  credentials.NewStaticV4(s.config.AccessKey, s.config.SecretKey, ""),
+		Region:       s.config.Region,
+		BucketLookup: types.BucketLookupPath,
+		Transport:    s.server.Client().Transport,
+	}
+}
+
+// NewClient returns a client for the server, failing the test on error
+func (s *Server) NewClient(t testing.TB) *rustfs.Client {
+	t.Helper()
+	client, err := rustfs.New(s.Endpoint(), s.Options())
+	if err != nil {
+		t.Fatalf("rustfstest: creating client: %v", err)
+	}
+	return client
+}
+
+// request is an authenticated request with its decoded body
+type request struct {
+	*http.Request
+	bucket, key string
+	query       url.Values
+	body        []byte
+	anonymous   bool
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-amz-request-id", strconv.FormatInt(s.ids.Add(1), 16))
+	w.Header().Set("Date", s.config.Now().UTC().Format(http.TimeFormat))
+	w.Header().Set("Server", "RustFS")
+
+	req := &request{Request: r, query: r.URL.Query()}
+	req.bucket, req.key, _ = strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if err := s.serve(w, req); err != nil {
+		writeError(w, req, err)
+	}
+}
+
+// serve authenticates the request, reads its body and dispatches it
+func (s *Server) serve(w http.ResponseWriter, r *request) error {
+	auth, err := s.authenticate(r.Request)
+	if err != nil {
+		return err
+	}
+	r.anonymous = auth == nil
+	if r.body, err = s.readBody(r.Request, auth); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.anonymous && !s.allowAnonymous(r) {
+		return errAccessDenied
+	}
+	switch {
+	case r.bucket == "" && r.Method == http.MethodGet:
+		return s.listBuckets(w, r)
+	case r.bucket == "":
+		return errMethodNotAllowed
+	case r.key == "":
+		return s.serveBucket(w, r)
+	default:
+		return s.serveObject(w, r)
+	}
+}
+
+// apiError is an S3 error response
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.code + ": " + e.message
+}
+
+// errorf returns an S3 error with a formatted message
+func errorf(status int, code, format string, args ...any) *apiError {
+	return &apiError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	errAccessDenied         = errorf(http.StatusForbidden, "AccessDenied", "Access Denied.")
+	errMethodNotAllowed     = errorf(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	errNotImplemented       = errorf(http.StatusNotImplemented, "NotImplemented", "A header or query you provided implies functionality that is not implemented.")
+	errNoSuchBucket         = errorf(http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist.")
+	errNoSuchKey            = errorf(http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+	errNoSuchVersion        = errorf(http.StatusNotFound, "NoSuchVersion", "The specified version does not exist.")
+	errNoSuchUpload         = errorf(http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist.")
+	errMalformedXML         = errorf(http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema.")
+	errPreconditionFailed   = errorf(http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the preconditions you specified did not hold.")
+	errInvalidRange         = errorf(http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "The requested range is not satisfiable.")
+	errSignatureMismatch    = errorf(http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided.")
+	errInvalidAccessKeyID   = errorf(http.StatusForbidden, "InvalidAccessKeyId", "The access key ID you provided does not exist in our records.")
+	errRequestTimeTooSkewed = errorf(http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the server's time is too large.")
+	errNotModified          = &apiError{status: http.StatusNotModified}
+)
+
+// errorResponse is the body of an S3 error response
+type errorResponse struct {
+	XMLName    xml.Name `xml:"Error"`
+	Code       string   `xml:"Code"`
+	Message    string   `xml:"Message"`
+	BucketName string   `xml:"BucketName,omitempty"`
+	Key        string   `xml:"Key,omitempty"`
+	Resource   string   `xml:"Resource"`
+	RequestID  string   `xml:"RequestId"`
+	Region     string   `xml:"Region,omitempty"`
+}
+
+// writeError writes err as an S3 error response; HEAD responses and 304s
+// have no body
+func writeError(w http.ResponseWriter, r *request, err error) {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = errorf(http.StatusInternalServerError, "InternalError", "%v", err)
+	}
+	if r.Method == http.MethodHead || apiErr.status == http.StatusNotModified {
+		w.WriteHeader(apiErr.status)
+		return
+	}
+	writeXML(w, apiErr.status, errorResponse{
+		Code:       apiErr.code,
+		Message:    apiErr.message,
+		BucketName: r.bucket,
+		Key:        r.key,
+		Resource:   r.URL.Path,
+		RequestID:  w.Header().Get("x-amz-request-id"),
+	})
+}
+
+// s3Namespace is the XML namespace of S3 responses
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// writeXML writes v as an XML response body
+func writeXML(w http.ResponseWriter, status int, v any) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(xml.Header)+len(body)))
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
+
+// decodeXML decodes the request body into v
+func decodeXML(body []byte, v any) error {
+	if err := xml.Unmarshal(body, v); err != nil {
+		return errMalformedXML
+	}
+	return nil
+}
+
+// newID returns a random version or upload ID
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// iso8601 formats t as in S3 XML responses
+func iso8601(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// now returns the server time truncated like Last-Modified headers
+func (s *Server) now() time.Time {
+	return s.config.Now().UTC().Truncate(time.Millisecond)
+}
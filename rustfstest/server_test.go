@@ -0,0 +1,334 @@
+// Package rustfstest rustfstest/server_test.go
+package rustfstest
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+
+	rustfs "github.com/Scorpio69t/rustfs-go"
+	"github.com/Scorpio69t/rustfs-go/errors"
+	objectapi "github.com/Scorpio69t/rustfs-go/object"
+	"github.com/Scorpio69t/rustfs-go/types"
+)
+
+// errCode returns the S3 error code of err
+func errCode(err error) string {
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.ErrorCode
+	}
+	return ""
+}
+
+// newBucket returns a client and a bucket created for the test
+func newBucket(t *testing.T) (*rustfs.Client, string) {
+	t.Helper()
+	client := NewClient(t)
+	if err := client.Bucket().Create(context.Background(), "test-bucket"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return client, "test-bucket"
+}
+
+func put(t *testing.T, client *rustfs.Client, bucket, key, data string, opts ...objectapi.PutOption) types.UploadInfo {
+	t.Helper()
+	info, err := client.Object().Put(context.Background(), bucket, key, strings.NewReader(data), int64(len(data)), opts...)
+	if err != nil {
+		t.Fatalf("Put(%q) error = %v", key, err)
+	}
+	return info
+}
+
+func get(t *testing.T, client *rustfs.Client, bucket, key string, opts ...objectapi.GetOption) (string, types.ObjectInfo) {
+	t.Helper()
+	reader, info, err := client.Object().Get(context.Background(), bucket, key, opts...)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading %q: %v", key, err)
+	}
+	return string(data), info
+}
+
+func TestBuckets(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+
+	if err := client.Bucket().Create(ctx, bucket); !errors.IsBucketExists(err) {
+		t.Errorf("Create() of an existing bucket error = %v, want BucketAlreadyOwnedByYou", err)
+	}
+	if exists, err := client.Bucket().Exists(ctx, bucket); err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true", exists, err)
+	}
+	if exists, err := client.Bucket().Exists(ctx, "missing"); err != nil || exists {
+		t.Errorf("Exists(missing) = %v, %v, want false", exists, err)
+	}
+	buckets, err := client.Bucket().List(ctx)
+	if err != nil || len(buckets) != 1 || buckets[0].Name != bucket {
+		t.Errorf("List() = %+v, %v", buckets, err)
+	}
+	if location, err := client.Bucket().GetLocation(ctx, bucket); err != nil || location != DefaultRegion {
+		t.Errorf("GetLocation() = %q, %v, want %q", location, err, DefaultRegion)
+	}
+
+	tags := map[string]string{"team": "storage"}
+	if err := client.Bucket().SetTagging(ctx, bucket, tags); err != nil {
+		t.Fatalf("SetTagging() error = %v", err)
+	}
+	if got, err := client.Bucket().GetTagging(ctx, bucket); err != nil || got["team"] != "storage" {
+		t.Errorf("GetTagging() = %v, %v", got, err)
+	}
+
+	put(t, client, bucket, "object", "data")
+	if err := client.Bucket().Delete(ctx, bucket); !errors.IsBucketNotEmpty(err) {
+		t.Errorf("Delete() of a non-empty bucket error = %v, want BucketNotEmpty", err)
+	}
+	if err := client.Object().Delete(ctx, bucket, "object"); err != nil {
+		t.Fatalf("Object().Delete() error = %v", err)
+	}
+	if err := client.Bucket().Delete(ctx, bucket); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if err := client.Bucket().Delete(ctx, bucket); !errors.IsBucketNotFound(err) {
+		t.Errorf("Delete() of a missing bucket error = %v, want NoSuchBucket", err)
+	}
+}
+
+func TestObjects(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+
+	info := put(t, client, bucket, "dir/hello world.txt", "hello, world",
+		objectapi.WithContentType("text/plain"),
+		objectapi.WithUserMetadata(map[string]string{"origin": "test"}),
+		objectapi.WithUserTags(map[string]string{"kind": "greeting"}),
+	)
+	if info.ETag == "" {
+		t.Error("Put() returned no ETag")
+	}
+
+	data, got := get(t, client, bucket, "dir/hello world.txt")
+	if data != "hello, world" || got.ContentType != "text/plain" || got.ETag != info.ETag {
+		t.Errorf("Get() = %q, %+v", data, got)
+	}
+	stat, err := client.Object().Stat(ctx, bucket, "dir/hello world.txt")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if stat.Size != 12 || stat.UserMetadata["Origin"] != "test" && stat.UserMetadata["origin"] != "test" {
+		t.Errorf("Stat() = size %d, metadata %v", stat.Size, stat.UserMetadata)
+	}
+	if data, _ := get(t, client, bucket, "dir/hello world.txt", objectapi.WithGetRange(7, 11)); data != "world" {
+		t.Errorf("Get() of a range = %q, want %q", data, "world")
+	}
+
+	tags, err := client.Object().GetTagging(ctx, bucket, "dir/hello world.txt")
+	if err != nil || tags["kind"] != "greeting" {
+		t.Errorf("GetTagging() = %v, %v", tags, err)
+	}
+	if err := client.Object().DeleteTagging(ctx, bucket, "dir/hello world.txt"); err != nil {
+		t.Errorf("DeleteTagging() error = %v", err)
+	}
+
+	if err := client.Object().Delete(ctx, bucket, "dir/hello world.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := client.Object().Stat(ctx, bucket, "dir/hello world.txt"); !errors.IsObjectNotFound(err) {
+		t.Errorf("Stat() after Delete() error = %v, want NoSuchKey", err)
+	}
+	if _, err := client.Object().Put(ctx, "missing", "key", strings.NewReader(""), 0); !errors.IsBucketNotFound(err) {
+		t.Errorf("Put() into a missing bucket error = %v, want NoSuchBucket", err)
+	}
+}
+
+func TestVersioning(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+
+	if err := client.Bucket().SetVersioning(ctx, bucket, types.VersioningConfig{Status: "Enabled"}); err != nil {
+		t.Fatalf("SetVersioning() error = %v", err)
+	}
+	first := put(t, client, bucket, "key", "first")
+	second := put(t, client, bucket, "key", "second")
+	if first.VersionID == "" || first.VersionID == second.VersionID {
+		t.Fatalf("Put() version IDs = %q, %q", first.VersionID, second.VersionID)
+	}
+
+	if data, _ := get(t, client, bucket, "key", func(o *objectapi.GetOptions) { o.VersionID = first.VersionID }); data != "first" {
+		t.Errorf("Get() of the first version = %q", data)
+	}
+	if err := client.Object().Delete(ctx, bucket, "key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := client.Object().Stat(ctx, bucket, "key"); !errors.IsObjectNotFound(err) {
+		t.Errorf("Stat() of a deleted key error = %v, want NoSuchKey", err)
+	}
+
+	var versions, markers int
+	for info := range client.Object().ListVersions(ctx, bucket) {
+		if info.Err != nil {
+			t.Fatalf("ListVersions() error = %v", info.Err)
+		}
+		if info.IsDeleteMarker {
+			markers++
+		} else {
+			versions++
+		}
+	}
+	if versions != 2 || markers != 1 {
+		t.Errorf("ListVersions() = %d versions, %d delete markers, want 2 and 1", versions, markers)
+	}
+
+	if err := client.Object().Delete(ctx, bucket, "key", func(o *objectapi.DeleteOptions) { o.VersionID = second.VersionID }); err != nil {
+		t.Fatalf("Delete() of a version error = %v", err)
+	}
+	if _, err := client.Object().Stat(ctx, bucket, "key", func(o *objectapi.StatOptions) { o.VersionID = second.VersionID }); err == nil {
+		t.Error("Stat() of a deleted version succeeded")
+	}
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+	put(t, client, bucket, "source", "copied data", objectapi.WithUserMetadata(map[string]string{"a": "1"}))
+
+	if _, err := client.Object().Copy(ctx, bucket, "target", bucket, "source"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if data, _ := get(t, client, bucket, "target"); data != "copied data" {
+		t.Errorf("Get() of the copy = %q", data)
+	}
+	if _, err := client.Object().Copy(ctx, bucket, "source", bucket, "source"); errCode(err) != "InvalidRequest" {
+		t.Errorf("Copy() onto itself error = %v, want InvalidRequest", err)
+	}
+	if _, err := client.Object().Copy(ctx, bucket, "source", bucket, "source", objectapi.WithCopyMetadata(map[string]string{"a": "2"}, true)); err != nil {
+		t.Errorf("Copy() onto itself replacing metadata error = %v", err)
+	}
+}
+
+func TestMultipart(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+
+	uploadID, err := client.Object().InitiateMultipartUpload(ctx, bucket, "large")
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload() error = %v", err)
+	}
+	first := bytes.Repeat([]byte("a"), minPartSize)
+	var parts []types.ObjectPart
+	for i, data := range [][]byte{first, []byte("tail")} {
+		part, err := client.Object().UploadPart(ctx, bucket, "large", uploadID, i+1, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("UploadPart(%d) error = %v", i+1, err)
+		}
+		parts = append(parts, part)
+	}
+
+	listed, err := client.Object().ListObjectParts(ctx, bucket, "large", uploadID)
+	if err != nil || len(listed.Parts) != 2 {
+		t.Fatalf("ListObjectParts() = %+v, %v", listed, err)
+	}
+	uploads, err := client.Object().ListMultipartUploads(ctx, bucket)
+	if err != nil || len(uploads.Uploads) != 1 || uploads.Uploads[0].UploadID != uploadID {
+		t.Fatalf("ListMultipartUploads() = %+v, %v", uploads, err)
+	}
+
+	stale := []types.ObjectPart{parts[0], parts[1]}
+	stale[1].ETag = "0123456789abcdef0123456789abcdef"
+	if _, err := client.Object().CompleteMultipartUpload(ctx, bucket, "large", uploadID, stale); errCode(err) != "InvalidPart" {
+		t.Errorf("CompleteMultipartUpload() with a stale ETag error = %v, want InvalidPart", err)
+	}
+	info, err := client.Object().CompleteMultipartUpload(ctx, bucket, "large", uploadID, parts)
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload() error = %v", err)
+	}
+	if !strings.HasSuffix(info.ETag, "-2") {
+		t.Errorf("CompleteMultipartUpload() ETag = %q, want a multipart ETag", info.ETag)
+	}
+	stat, err := client.Object().Stat(ctx, bucket, "large")
+	if err != nil || stat.Size != int64(len(first)+4) {
+		t.Errorf("Stat() = size %d, %v", stat.Size, err)
+	}
+
+	uploadID, err = client.Object().InitiateMultipartUpload(ctx, bucket, "aborted")
+	if err != nil {
+		t.Fatalf("InitiateMultipartUpload() error = %v", err)
+	}
+	if err := client.Object().AbortMultipartUpload(ctx, bucket, "aborted", uploadID); err != nil {
+		t.Errorf("AbortMultipartUpload() error = %v", err)
+	}
+	if _, err := client.Object().ListObjectParts(ctx, bucket, "aborted", uploadID); errCode(err) != "NoSuchUpload" {
+		t.Errorf("ListObjectParts() of an aborted upload error = %v, want NoSuchUpload", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+	for _, key := range []string{"a/1", "a/2", "b/1", "c", "d", "e"} {
+		put(t, client, bucket, key, key)
+	}
+
+	var keys []string
+	for info := range client.Object().List(ctx, bucket, objectapi.WithListMaxKeys(2)) {
+		if info.Err != nil {
+			t.Fatalf("List() error = %v", info.Err)
+		}
+		keys = append(keys, info.Key)
+	}
+	if got := strings.Join(keys, ","); got != "a/,b/,c,d,e" {
+		t.Errorf("List() = %s, want a/,b/,c,d,e", got)
+	}
+
+	keys = nil
+	for info := range client.Object().List(ctx, bucket, objectapi.WithListPrefix("a/"), objectapi.WithListRecursive(true)) {
+		if info.Err != nil {
+			t.Fatalf("List() error = %v", info.Err)
+		}
+		keys = append(keys, info.Key)
+	}
+	if got := strings.Join(keys, ","); got != "a/1,a/2" {
+		t.Errorf("List() of a/ = %s, want a/1,a/2", got)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+	put(t, client, bucket, "log", "one,")
+
+	if _, err := client.Object().Append(ctx, bucket, "log", strings.NewReader("two"), 3, 4); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if data, _ := get(t, client, bucket, "log"); data != "one,two" {
+		t.Errorf("Get() after Append() = %q", data)
+	}
+	if _, err := client.Object().Append(ctx, bucket, "log", strings.NewReader("x"), 1, 2); err == nil {
+		t.Error("Append() at a stale offset succeeded")
+	}
+}
+
+func TestDeleteMany(t *testing.T) {
+	ctx := context.Background()
+	client, bucket := newBucket(t)
+	objects := make(chan types.ObjectToDelete, 3)
+	for _, key := range []string{"x", "y", "z"} {
+		put(t, client, bucket, key, key)
+		objects <- types.ObjectToDelete{Key: key}
+	}
+	close(objects)
+
+	for result := range client.Object().DeleteMany(ctx, bucket, objects) {
+		t.Errorf("DeleteMany() error = %+v", result)
+	}
+	for info := range client.Object().List(ctx, bucket) {
+		t.Errorf("List() after DeleteMany() = %+v", info)
+	}
+}
@@ -90,6 +90,9 @@ type DeleteError struct {
 	VersionID string
 	Code      string
 	Message   string
+
+	// Err is set when the delete request itself failed (network or HTTP error)
+	Err error
 }
 
 // CopyInfo contains copy information